@@ -0,0 +1,176 @@
+// Package aggregate groups transformed reports into tumbling windows of event time
+// by state, county, and report type, and emits a summary for each window once it
+// can no longer receive reports.
+package aggregate
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	report "github.com/stormsync/transformer/proto"
+	rpt "github.com/stormsync/transformer/report"
+)
+
+// SummaryType is the report type used when producing summary messages.
+const SummaryType = "Summary"
+
+type key struct {
+	start  int64
+	state  string
+	county string
+	rtype  string
+}
+
+type window struct {
+	summary  *report.SummaryMsg
+	hasPoint bool
+}
+
+// Aggregator keeps the open windows. A window [start, end) closes once the
+// watermark, the latest event time seen less the allowed lateness, reaches its end.
+// Reports that arrive for a window that has already closed are counted as late
+// and otherwise ignored.
+type Aggregator struct {
+	size     int64
+	lateness int64
+
+	mu        sync.Mutex
+	maxTime   int64
+	windows   map[key]*window
+	lateCount int64
+}
+
+// NewAggregator returns an Aggregator with windows of the given size that accepts
+// reports up to lateness behind the latest event time seen.
+func NewAggregator(size, lateness time.Duration) (*Aggregator, error) {
+	if size < time.Second {
+		return nil, errors.New("window size must be at least one second")
+	}
+	if lateness < 0 {
+		return nil, errors.New("allowed lateness cannot be negative")
+	}
+	return &Aggregator{
+		size:     int64(size / time.Second),
+		lateness: int64(lateness / time.Second),
+		windows:  make(map[key]*window),
+	}, nil
+}
+
+// Add places the report in its window and returns the summaries of any windows
+// closed by the report advancing the watermark. The bool is true when the report
+// was too late for its window. Reports without an event time are ignored.
+func (a *Aggregator) Add(r rpt.Report) ([]*report.SummaryMsg, bool) {
+	t := r.GetTime()
+	if t <= 0 {
+		return nil, false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	start := t - t%a.size
+	if a.maxTime > 0 && start+a.size <= a.watermark() {
+		a.lateCount++
+		return nil, true
+	}
+
+	k := key{
+		start:  start,
+		state:  strings.ToUpper(strings.TrimSpace(r.GetState())),
+		county: strings.TrimSpace(r.GetCounty()),
+		rtype:  rpt.Kind(r),
+	}
+	w, ok := a.windows[k]
+	if !ok {
+		w = &window{summary: &report.SummaryMsg{
+			State:       k.state,
+			County:      k.county,
+			Type:        k.rtype,
+			WindowStart: start,
+			WindowEnd:   start + a.size,
+			FirstTime:   t,
+			LastTime:    t,
+		}}
+		a.windows[k] = w
+	}
+	w.add(r)
+
+	if t > a.maxTime {
+		a.maxTime = t
+	}
+	return a.closeUntil(a.watermark()), false
+}
+
+// Flush closes every open window regardless of the watermark and returns their summaries.
+// It is meant to be called on shutdown.
+func (a *Aggregator) Flush() []*report.SummaryMsg {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.closeUntil(math.MaxInt64)
+}
+
+// Late returns the number of reports that arrived after their window closed.
+func (a *Aggregator) Late() int64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.lateCount
+}
+
+// Open returns the number of windows that have not yet closed.
+func (a *Aggregator) Open() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.windows)
+}
+
+func (a *Aggregator) watermark() int64 {
+	return a.maxTime - a.lateness
+}
+
+// closeUntil removes the windows ending at or before the watermark and returns
+// their summaries ordered by window, state, county and type.
+func (a *Aggregator) closeUntil(watermark int64) []*report.SummaryMsg {
+	var closed []*report.SummaryMsg
+	for k, w := range a.windows {
+		if w.summary.WindowEnd <= watermark {
+			closed = append(closed, w.summary)
+			delete(a.windows, k)
+		}
+	}
+	slices.SortFunc(closed, func(x, y *report.SummaryMsg) int {
+		return cmp.Or(
+			cmp.Compare(x.WindowStart, y.WindowStart),
+			cmp.Compare(x.State, y.State),
+			cmp.Compare(x.County, y.County),
+			cmp.Compare(x.Type, y.Type),
+		)
+	})
+	return closed
+}
+
+func (w *window) add(r rpt.Report) {
+	s := w.summary
+	s.Count++
+	s.MaxMagnitude = max(s.MaxMagnitude, rpt.Magnitude(r))
+	s.FirstTime = min(s.FirstTime, r.GetTime())
+	s.LastTime = max(s.LastTime, r.GetTime())
+
+	lat, lon, err := rpt.LatLon(r)
+	if err != nil {
+		return
+	}
+	if !w.hasPoint {
+		s.MinLat, s.MaxLat, s.MinLon, s.MaxLon = lat, lat, lon, lon
+		w.hasPoint = true
+		return
+	}
+	s.MinLat = min(s.MinLat, lat)
+	s.MaxLat = max(s.MaxLat, lat)
+	s.MinLon = min(s.MinLon, lon)
+	s.MaxLon = max(s.MaxLon, lon)
+}
@@ -0,0 +1,102 @@
+package aggregate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	report "github.com/stormsync/transformer/proto"
+)
+
+// base is 2024-05-17 18:00:00 UTC, the start of an hour window.
+const base = int64(1715968800)
+
+func hail(t int64, county string, size int32, lat, lon string) *report.HailMsg {
+	return &report.HailMsg{Type: "Hail", Time: t, State: "TX", County: county, Size: size, Lat: lat, Lon: lon}
+}
+
+func TestAggregator_Add(t *testing.T) {
+	a, err := NewAggregator(time.Hour, 10*time.Minute)
+	require.NoError(t, err)
+
+	closed, late := a.Add(hail(base+60, "Hood", 100, "32.36", "-97.66"))
+	assert.Empty(t, closed)
+	assert.False(t, late)
+
+	closed, _ = a.Add(hail(base+1800, "Hood", 175, "32.40", "-97.70"))
+	assert.Empty(t, closed)
+
+	// inside the next window but not past the allowed lateness yet
+	closed, _ = a.Add(hail(base+3600+300, "Tarrant", 100, "32.75", "-97.33"))
+	assert.Empty(t, closed)
+
+	// a late report for the first window is still accepted
+	closed, late = a.Add(hail(base+3000, "Hood", 250, "", ""))
+	assert.Empty(t, closed)
+	assert.False(t, late)
+
+	// advancing the watermark past the first window closes it
+	closed, _ = a.Add(hail(base+3600+600, "Tarrant", 100, "32.76", "-97.30"))
+	require.Len(t, closed, 1)
+	assert.Equal(t, &report.SummaryMsg{
+		State:        "TX",
+		County:       "Hood",
+		Type:         "Hail",
+		WindowStart:  base,
+		WindowEnd:    base + 3600,
+		Count:        3,
+		MaxMagnitude: 250,
+		FirstTime:    base + 60,
+		LastTime:     base + 3000,
+		MinLat:       32.36,
+		MinLon:       -97.70,
+		MaxLat:       32.40,
+		MaxLon:       -97.66,
+	}, closed[0])
+
+	// the first window has closed so this report is dropped as late
+	closed, late = a.Add(hail(base+120, "Hood", 100, "32.36", "-97.66"))
+	assert.Empty(t, closed)
+	assert.True(t, late)
+	assert.Equal(t, int64(1), a.Late())
+
+	assert.Equal(t, 1, a.Open())
+	flushed := a.Flush()
+	require.Len(t, flushed, 1)
+	assert.Equal(t, "Tarrant", flushed[0].GetCounty())
+	assert.Equal(t, int32(2), flushed[0].GetCount())
+	assert.Equal(t, 0, a.Open())
+}
+
+func TestAggregator_IgnoresReportsWithoutTime(t *testing.T) {
+	a, err := NewAggregator(time.Hour, 0)
+	require.NoError(t, err)
+
+	closed, late := a.Add(hail(0, "Hood", 100, "32.36", "-97.66"))
+	assert.Empty(t, closed)
+	assert.False(t, late)
+	assert.Equal(t, 0, a.Open())
+}
+
+func TestNewAggregator_Validation(t *testing.T) {
+	_, err := NewAggregator(time.Millisecond, 0)
+	assert.EqualError(t, err, "window size must be at least one second")
+
+	_, err = NewAggregator(time.Hour, -time.Minute)
+	assert.EqualError(t, err, "allowed lateness cannot be negative")
+}
+
+func TestAggregator_LSREventTypes(t *testing.T) {
+	a, err := NewAggregator(time.Hour, 0)
+	require.NoError(t, err)
+	a.Add(&report.LSRMsg{Type: "LSR", EventType: "Flood", Time: base + 60, State: "OK", County: "Cleveland", Lat: "35.35", Lon: "-97.48"})
+	a.Add(&report.LSRMsg{Type: "LSR", EventType: "Snow", Time: base + 120, State: "OK", County: "Cleveland", Lat: "35.36", Lon: "-97.47"})
+
+	var types []string
+	for _, s := range a.Flush() {
+		types = append(types, s.GetType())
+	}
+	assert.ElementsMatch(t, []string{"Flood", "Snow"}, types, "floods and snow are summarized apart")
+}
@@ -0,0 +1,266 @@
+// Package alert evaluates configurable rules over transformed reports and raises
+// alerts for high impact weather, such as any tornado or a cluster of wind
+// reports in one county.
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stormsync/collector"
+	"gopkg.in/yaml.v3"
+
+	"github.com/stormsync/transformer/geo"
+	report "github.com/stormsync/transformer/proto"
+	rpt "github.com/stormsync/transformer/report"
+)
+
+// AlertType is the report type used when producing alert messages.
+const AlertType = "Alert"
+
+// Values for Rule.GroupBy.
+const (
+	GroupByNone   = ""
+	GroupByState  = "state"
+	GroupByCounty = "county"
+)
+
+// Rule raises an alert once Count matching reports, within the Within window of
+// each other and sharing the same GroupBy value, have been seen. A report matches
+// when it is one of Types, at or above MinMagnitude, in one of States and inside
+// Polygon; conditions left empty are not checked. After an alert the rule is
+// quiet for the group until Suppress has passed.
+//
+// Magnitudes are in the units of the source column: hail size in hundredths of an
+// inch, wind speed in knots, and the tornado F/EF scale. Polygon vertices are
+// [lat, lon] pairs. Times are report event times.
+type Rule struct {
+	ID           string        `yaml:"id"`
+	Description  string        `yaml:"description"`
+	Types        []string      `yaml:"types"`
+	MinMagnitude int32         `yaml:"min-magnitude"`
+	States       []string      `yaml:"states"`
+	Polygon      [][2]float64  `yaml:"polygon"`
+	Count        int           `yaml:"count"`
+	Within       time.Duration `yaml:"within"`
+	GroupBy      string        `yaml:"group-by"`
+	Suppress     time.Duration `yaml:"suppress"`
+}
+
+type rule struct {
+	Rule
+	polygon *geo.Feature
+}
+
+type groupKey struct {
+	rule  string
+	group string
+}
+
+type groupState struct {
+	reports       []*report.AlertReport
+	suppressUntil int64
+	// within is the rule's window in seconds
+	within int64
+}
+
+// Engine evaluates alert rules against reports. Groups are dropped once none of
+// their reports are within the rule's window and their suppression has passed,
+// going by the latest report time seen.
+type Engine struct {
+	rules []rule
+
+	mu      sync.Mutex
+	groups  map[groupKey]*groupState
+	maxTime int64
+}
+
+// NewEngine validates the rules and returns an Engine that evaluates them.
+// All problems found with the rules are returned together.
+func NewEngine(rules []Rule) (*Engine, error) {
+	e := &Engine{groups: make(map[groupKey]*groupState)}
+	seen := make(map[string]struct{}, len(rules))
+
+	var errs []error
+	for i, r := range rules {
+		if r.ID == "" {
+			errs = append(errs, fmt.Errorf("rule %d: id cannot be empty", i))
+		} else if _, ok := seen[r.ID]; ok {
+			errs = append(errs, fmt.Errorf("rule %d: duplicate id %q", i, r.ID))
+		}
+		seen[r.ID] = struct{}{}
+
+		c := rule{Rule: r}
+		for _, t := range r.Types {
+			if _, err := collector.FromString(t); err != nil {
+				errs = append(errs, fmt.Errorf("rule %q: unknown report type %q", r.ID, t))
+			}
+		}
+		if c.Count == 0 {
+			c.Count = 1
+		}
+		if c.Count < 0 {
+			errs = append(errs, fmt.Errorf("rule %q: count cannot be negative", r.ID))
+		}
+		if c.Count > 1 && c.Within <= 0 {
+			errs = append(errs, fmt.Errorf("rule %q: within is required when count is above 1", r.ID))
+		}
+		if c.Suppress < 0 {
+			errs = append(errs, fmt.Errorf("rule %q: suppress cannot be negative", r.ID))
+		}
+		switch c.GroupBy {
+		case GroupByNone, GroupByState, GroupByCounty:
+		default:
+			errs = append(errs, fmt.Errorf("rule %q: unknown group-by %q", r.ID, c.GroupBy))
+		}
+		if len(c.Polygon) > 0 {
+			if len(c.Polygon) < 3 {
+				errs = append(errs, fmt.Errorf("rule %q: polygon needs at least 3 vertices", r.ID))
+			} else {
+				c.polygon = geo.NewPolygonFeature(c.Polygon, nil)
+			}
+		}
+		e.rules = append(e.rules, c)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// LoadRules decodes a YAML list of alert rules.
+func LoadRules(r io.Reader) ([]Rule, error) {
+	var rules []Rule
+	if err := yaml.NewDecoder(r).Decode(&rules); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("unable to decode alert rules: %w", err)
+	}
+	return rules, nil
+}
+
+// Evaluate checks the report against every rule and returns the alerts it raised.
+func (e *Engine) Evaluate(r rpt.Report) []*report.Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var alerts []*report.Alert
+	for _, rl := range e.rules {
+		if !rl.matches(r) {
+			continue
+		}
+		k := groupKey{rule: rl.ID, group: rl.group(r)}
+		// events other than hail, wind and tornadoes are counted apart by their
+		// event type, so a flood and a snow report do not add up to an alert
+		if _, ok := r.(*report.LSRMsg); ok {
+			k.group += "/" + rpt.Kind(r)
+		}
+		gs, ok := e.groups[k]
+		if !ok {
+			gs = &groupState{within: int64(rl.Within / time.Second)}
+			e.groups[k] = gs
+		}
+
+		t := r.GetTime()
+		gs.reports = append(gs.reports, toAlertReport(r))
+		if rl.Within > 0 {
+			oldest := t - int64(rl.Within/time.Second)
+			gs.reports = slices.DeleteFunc(gs.reports, func(ar *report.AlertReport) bool { return ar.GetTime() < oldest })
+		} else if len(gs.reports) > rl.Count {
+			gs.reports = gs.reports[len(gs.reports)-rl.Count:]
+		}
+		if len(gs.reports) < rl.Count || t < gs.suppressUntil {
+			continue
+		}
+
+		alerts = append(alerts, &report.Alert{
+			AlertID:     fmt.Sprintf("%s/%s/%d", rl.ID, k.group, t),
+			RuleID:      rl.ID,
+			Description: rl.Description,
+			Time:        t,
+			Group:       k.group,
+			Reports:     gs.reports,
+		})
+		gs.reports = nil
+		gs.suppressUntil = t + int64(rl.Suppress/time.Second)
+	}
+
+	e.maxTime = max(e.maxTime, r.GetTime())
+	e.expire()
+	return alerts
+}
+
+// Groups returns the number of groups being tracked across all rules.
+func (e *Engine) Groups() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.groups)
+}
+
+func (e *Engine) expire() {
+	for k, gs := range e.groups {
+		if gs.suppressUntil > e.maxTime {
+			continue
+		}
+		oldest := e.maxTime - gs.within
+		if !slices.ContainsFunc(gs.reports, func(ar *report.AlertReport) bool { return ar.GetTime() >= oldest }) {
+			delete(e.groups, k)
+		}
+	}
+}
+
+func (r rule) matches(rp rpt.Report) bool {
+	if len(r.Types) > 0 && !containsFold(r.Types, rp.GetType()) {
+		return false
+	}
+	if r.MinMagnitude > 0 && rpt.Magnitude(rp) < r.MinMagnitude {
+		return false
+	}
+	if len(r.States) > 0 && !containsFold(r.States, rp.GetState()) {
+		return false
+	}
+	if r.polygon != nil {
+		lat, lon, err := rpt.LatLon(rp)
+		if err != nil || !r.polygon.Contains(lat, lon) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r rule) group(rp rpt.Report) string {
+	state := strings.ToUpper(strings.TrimSpace(rp.GetState()))
+	switch r.GroupBy {
+	case GroupByState:
+		return state
+	case GroupByCounty:
+		return state + "/" + strings.TrimSpace(rp.GetCounty())
+	}
+	return "all"
+}
+
+func containsFold(values []string, s string) bool {
+	s = strings.TrimSpace(s)
+	return slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, s) })
+}
+
+func toAlertReport(r rpt.Report) *report.AlertReport {
+	ar := &report.AlertReport{
+		Type:      rpt.Kind(r),
+		Time:      r.GetTime(),
+		Magnitude: rpt.Magnitude(r),
+		Location:  r.GetLocation(),
+		County:    r.GetCounty(),
+		State:     r.GetState(),
+		Lat:       r.GetLat(),
+		Lon:       r.GetLon(),
+		Remarks:   r.GetRemarks(),
+	}
+	if e, ok := r.(interface{ GetEventId() string }); ok {
+		ar.EventID = e.GetEventId()
+	}
+	return ar
+}
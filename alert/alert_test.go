@@ -0,0 +1,160 @@
+package alert
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	report "github.com/stormsync/transformer/proto"
+)
+
+const base = int64(1715968800)
+
+func TestEngine_AnyTornado(t *testing.T) {
+	e, err := NewEngine([]Rule{{ID: "any-tornado", Types: []string{"Tornado"}, Suppress: 10 * time.Minute}})
+	require.NoError(t, err)
+
+	alerts := e.Evaluate(&report.TornadoMsg{Type: "Tornado", Time: base, State: "FL", County: "Jefferson", EventId: "tornado-1"})
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "any-tornado", alerts[0].GetRuleID())
+	require.Len(t, alerts[0].GetReports(), 1)
+	assert.Equal(t, "tornado-1", alerts[0].GetReports()[0].GetEventID())
+
+	// suppressed for ten minutes
+	assert.Empty(t, e.Evaluate(&report.TornadoMsg{Type: "Tornado", Time: base + 300, State: "FL"}))
+	assert.Len(t, e.Evaluate(&report.TornadoMsg{Type: "Tornado", Time: base + 600, State: "FL"}), 1)
+
+	assert.Empty(t, e.Evaluate(&report.HailMsg{Type: "Hail", Time: base + 900, Size: 400}))
+}
+
+func TestEngine_HailInPolygon(t *testing.T) {
+	e, err := NewEngine([]Rule{{
+		ID:           "giant-hail",
+		Types:        []string{"Hail"},
+		MinMagnitude: 275,
+		Polygon:      [][2]float64{{33.4, -97.6}, {33.4, -96.4}, {32.4, -96.4}, {32.4, -97.6}},
+	}})
+	require.NoError(t, err)
+
+	assert.Empty(t, e.Evaluate(&report.HailMsg{Type: "Hail", Time: base, Size: 200, Lat: "32.75", Lon: "-97.33"}), "too small")
+	assert.Empty(t, e.Evaluate(&report.HailMsg{Type: "Hail", Time: base, Size: 300, Lat: "32.36", Lon: "-97.66"}), "outside polygon")
+	assert.Len(t, e.Evaluate(&report.HailMsg{Type: "Hail", Time: base, Size: 300, Lat: "32.75", Lon: "-97.33"}), 1)
+}
+
+func TestEngine_CountyWindCluster(t *testing.T) {
+	e, err := NewEngine([]Rule{{
+		ID:       "county-wind",
+		Types:    []string{"Wind"},
+		Count:    3,
+		Within:   30 * time.Minute,
+		GroupBy:  GroupByCounty,
+		Suppress: time.Hour,
+	}})
+	require.NoError(t, err)
+
+	wind := func(offset int64, county string) *report.WindMsg {
+		return &report.WindMsg{Type: "Wind", Time: base + offset, State: "GA", County: county}
+	}
+
+	assert.Empty(t, e.Evaluate(wind(0, "Irwin")))
+	assert.Empty(t, e.Evaluate(wind(600, "Irwin")))
+	assert.Empty(t, e.Evaluate(wind(700, "Tift")), "different county")
+	// the first report has aged out of the window
+	assert.Empty(t, e.Evaluate(wind(1900, "Irwin")))
+
+	alerts := e.Evaluate(wind(2000, "Irwin"))
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "GA/Irwin", alerts[0].GetGroup())
+	assert.Len(t, alerts[0].GetReports(), 3)
+	assert.Equal(t, "county-wind/GA/Irwin/1715970800", alerts[0].GetAlertID())
+
+	// suppressed for an hour even after the count is reached again
+	for i := int64(1); i <= 3; i++ {
+		assert.Empty(t, e.Evaluate(wind(2000+i*60, "Irwin")))
+	}
+}
+
+func TestEngine_ExpiresGroups(t *testing.T) {
+	e, err := NewEngine([]Rule{
+		{ID: "any-tornado", Types: []string{"Tornado"}, GroupBy: GroupByState, Suppress: time.Hour},
+		{ID: "county-wind", Types: []string{"Wind"}, Count: 2, Within: 30 * time.Minute, GroupBy: GroupByCounty},
+	})
+	require.NoError(t, err)
+
+	e.Evaluate(&report.TornadoMsg{Type: "Tornado", Time: base, State: "OK"})
+	e.Evaluate(&report.WindMsg{Type: "Wind", Time: base, State: "GA", County: "Fulton"})
+	assert.Equal(t, 2, e.Groups())
+
+	// Fulton is out of the window, the tornado alert is still suppressed
+	e.Evaluate(&report.WindMsg{Type: "Wind", Time: base + 2400, State: "GA", County: "Cobb"})
+	assert.Equal(t, 2, e.Groups())
+
+	// the suppression has passed and the Cobb alert leaves nothing to count
+	assert.Len(t, e.Evaluate(&report.WindMsg{Type: "Wind", Time: base + 3600, State: "GA", County: "Cobb"}), 1)
+	assert.Equal(t, 0, e.Groups())
+
+	// a late report is still counted with the reports inside its window
+	e.Evaluate(&report.WindMsg{Type: "Wind", Time: base + 3590, State: "GA", County: "Dekalb"})
+	assert.Len(t, e.Evaluate(&report.WindMsg{Type: "Wind", Time: base + 3595, State: "GA", County: "Dekalb"}), 1)
+}
+
+func TestNewEngine_Validation(t *testing.T) {
+	_, err := NewEngine([]Rule{
+		{ID: ""},
+		{ID: "a", Types: []string{"Snow"}},
+		{ID: "a"},
+		{ID: "b", Count: 5},
+		{ID: "c", GroupBy: "city"},
+		{ID: "d", Polygon: [][2]float64{{1, 1}, {2, 2}}},
+	})
+	require.Error(t, err)
+	for _, want := range []string{
+		"rule 0: id cannot be empty",
+		`rule "a": unknown report type "Snow"`,
+		`rule 2: duplicate id "a"`,
+		`rule "b": within is required when count is above 1`,
+		`rule "c": unknown group-by "city"`,
+		`rule "d": polygon needs at least 3 vertices`,
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	rules, err := LoadRules(strings.NewReader(`
+- id: county-wind
+  types: [Wind]
+  count: 5
+  within: 30m
+  group-by: county
+  suppress: 1h
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{{
+		ID:       "county-wind",
+		Types:    []string{"Wind"},
+		Count:    5,
+		Within:   30 * time.Minute,
+		GroupBy:  GroupByCounty,
+		Suppress: time.Hour,
+	}}, rules)
+}
+
+func TestEngine_LSREventTypes(t *testing.T) {
+	e, err := NewEngine([]Rule{{ID: "county-reports", Count: 2, Within: 30 * time.Minute, GroupBy: GroupByCounty}})
+	require.NoError(t, err)
+
+	lsr := func(offset int64, eventType string) *report.LSRMsg {
+		return &report.LSRMsg{Type: "LSR", EventType: eventType, Time: base + offset, State: "OK", County: "Cleveland"}
+	}
+	assert.Empty(t, e.Evaluate(lsr(0, "Flood")))
+	assert.Empty(t, e.Evaluate(lsr(60, "Snow")), "a flood and a snow report are counted apart")
+
+	alerts := e.Evaluate(lsr(120, "Flood"))
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "OK/Cleveland/Flood", alerts[0].GetGroup())
+	assert.Equal(t, "Flood", alerts[0].GetReports()[0].GetType())
+}
@@ -0,0 +1,393 @@
+// Package backfill publishes the reports of archived SPC daily files, such as
+// 240517_rpts_hail.csv, through a transformer.
+//
+// The files of an archive directory are read in order of their convective day,
+// with each line dated on the day in its file name. Reading can be throttled,
+// progress is logged as it goes, and the position reached is saved to a
+// checkpoint file once every message before it has been delivered, so an
+// interrupted backfill resumes where it left off.
+package backfill
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/stormsync/transformer/consumer"
+	"github.com/stormsync/transformer/provider"
+)
+
+// File is an SPC archive file.
+type File struct {
+	Path string    // path to open
+	Name string    // path relative to the archive directory, as recorded in checkpoints
+	Day  time.Time // convective day of the reports
+}
+
+// Find returns the SPC archive files under dir, such as 240517_rpts_hail.csv or
+// the combined 240517_rpts.csv, ordered by convective day and then name.
+func Find(dir string) ([]File, error) {
+	var files []File
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".csv") {
+			return nil
+		}
+		day, ok := consumer.ConvectiveDayFromFilename(path)
+		if !ok {
+			return nil
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, File{Path: path, Name: filepath.ToSlash(name), Day: day})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find archive files: %w", err)
+	}
+	slices.SortFunc(files, compareFiles)
+	return files, nil
+}
+
+func compareFiles(a, b File) int {
+	if c := a.Day.Compare(b.Day); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Name, b.Name)
+}
+
+// Checkpoint is the position a backfill has published up to.
+type Checkpoint struct {
+	File  string    `json:"file"`  // name of the file being read
+	Line  int64     `json:"line"`  // last line of the file that has been delivered
+	Lines int64     `json:"lines"` // report lines read over every run
+	Saved time.Time `json:"saved"`
+}
+
+// LoadCheckpoint reads the checkpoint at path. The zero checkpoint, which starts
+// from the first file, is returned when there is no file at path.
+func LoadCheckpoint(path string) (Checkpoint, error) {
+	var cp Checkpoint
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return cp, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	if err := json.Unmarshal(b, &cp); err != nil {
+		return cp, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+	if cp.File != "" {
+		if _, ok := consumer.ConvectiveDayFromFilename(cp.File); !ok {
+			return cp, fmt.Errorf("invalid checkpoint %s: %q is not an archive file", path, cp.File)
+		}
+	}
+	return cp, nil
+}
+
+// Save writes the checkpoint to path, replacing the previous one only once it
+// has been written in full.
+func (cp Checkpoint) Save(path string) error {
+	b, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace checkpoint: %w", err)
+	}
+	return nil
+}
+
+// after reports whether the file comes after the checkpoint's file.
+func (cp Checkpoint) after(f File) bool {
+	if cp.File == "" {
+		return true
+	}
+	day, _ := consumer.ConvectiveDayFromFilename(cp.File)
+	return compareFiles(f, File{Name: cp.File, Day: day}) > 0
+}
+
+// Transformer transforms the messages read from a Backfill.
+type Transformer interface {
+	GetMessage(ctx context.Context) error
+	Flush(ctx context.Context) error
+}
+
+// Backfill reads the lines of archive files as a consumer.Consumer, to be
+// transformed and published by a transformer driven with Run.
+type Backfill struct {
+	files  []File
+	logger *slog.Logger
+
+	checkpointPath  string
+	checkpointEvery time.Duration
+	progressEvery   time.Duration
+	limiter         *rate.Limiter
+
+	resume    Checkpoint
+	next      int // index of the next file to open
+	current   *consumer.FileConsumer
+	file      File
+	line      int64      // last line returned from file
+	lines     int64      // lines returned this run
+	handed    Checkpoint // position of the last line the transformer took
+	filesDone int
+	failed    atomic.Int64
+	started   time.Time
+}
+
+var _ consumer.Consumer = (*Backfill)(nil)
+
+// Option configures optional behavior of a Backfill.
+type Option func(*Backfill)
+
+// WithRate reads at most perSecond report lines a second. Reading is not
+// throttled when perSecond is zero.
+func WithRate(perSecond float64) Option {
+	return func(b *Backfill) {
+		if perSecond > 0 {
+			b.limiter = rate.NewLimiter(rate.Limit(perSecond), 1)
+		}
+	}
+}
+
+// WithCheckpoint resumes from the checkpoint at path and saves the position
+// reached to it every interval, and when the backfill stops.
+func WithCheckpoint(path string, interval time.Duration) Option {
+	return func(b *Backfill) {
+		b.checkpointPath = path
+		b.checkpointEvery = interval
+	}
+}
+
+// WithProgressInterval logs progress every interval instead of every minute.
+func WithProgressInterval(interval time.Duration) Option {
+	return func(b *Backfill) {
+		b.progressEvery = interval
+	}
+}
+
+// NewBackfill returns a backfill of the files, in the order given, resuming from
+// the checkpoint if one is configured and has been saved before.
+func NewBackfill(files []File, logger *slog.Logger, opts ...Option) (*Backfill, error) {
+	b := &Backfill{
+		files:         files,
+		logger:        logger,
+		progressEvery: time.Minute,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.checkpointPath != "" {
+		cp, err := LoadCheckpoint(b.checkpointPath)
+		if err != nil {
+			return nil, err
+		}
+		b.resume = cp
+		if cp.File != "" {
+			logger.Info("resuming backfill from checkpoint", "file", cp.File, "line", cp.Line, "lines", cp.Lines)
+		}
+	}
+	return b, nil
+}
+
+// ReadMessage returns the next report line, waiting for the rate limit. It
+// returns an error wrapping io.EOF once every file has been read.
+func (b *Backfill) ReadMessage(ctx context.Context) (consumer.ReaderResponse, error) {
+	for {
+		if b.current == nil {
+			if b.next == len(b.files) {
+				return consumer.ReaderResponse{}, fmt.Errorf("no more archive files: %w", io.EOF)
+			}
+			f := b.files[b.next]
+			b.next++
+			if !b.resume.after(f) && f.Name != b.resume.File {
+				b.filesDone++
+				continue
+			}
+			if err := b.open(f); err != nil {
+				return consumer.ReaderResponse{}, err
+			}
+		}
+
+		msg, err := b.current.ReadMessage(ctx)
+		if errors.Is(err, io.EOF) {
+			if err := b.current.Close(ctx); err != nil {
+				return consumer.ReaderResponse{}, err
+			}
+			b.current = nil
+			b.filesDone++
+			continue
+		}
+		if err != nil {
+			return consumer.ReaderResponse{}, err
+		}
+		// lines up to the checkpoint were delivered by an earlier run
+		if b.file.Name == b.resume.File && msg.Offset <= b.resume.Line {
+			continue
+		}
+
+		if b.limiter != nil {
+			if err := b.limiter.Wait(ctx); err != nil {
+				return consumer.ReaderResponse{}, fmt.Errorf("failed to read message from %s: %w", b.file.Name, err)
+			}
+		}
+		b.line = msg.Offset
+		b.lines++
+		return msg, nil
+	}
+}
+
+func (b *Backfill) open(f File) error {
+	r, err := os.Open(f.Path)
+	if err != nil {
+		return fmt.Errorf("failed to open archive file: %w", err)
+	}
+	c, err := consumer.NewFileConsumer(r, f.Path, "", b.logger)
+	if err != nil {
+		r.Close()
+		return err
+	}
+	b.current, b.file, b.line = c, f, 0
+	b.logger.Debug("backfilling file", "file", f.Name, "day", f.Day.Format(time.DateOnly))
+	return nil
+}
+
+// Close closes the file being read.
+func (b *Backfill) Close(ctx context.Context) error {
+	if b.current == nil {
+		return nil
+	}
+	return b.current.Close(ctx)
+}
+
+// OnDelivery records messages that could not be delivered, so the checkpoint is
+// not moved past them. It is meant for provider.WithDeliveryCallback.
+func (b *Backfill) OnDelivery(_ provider.WriterPayload, err error) {
+	if err != nil {
+		b.failed.Add(1)
+	}
+}
+
+// Position returns the checkpoint of the last line the transformer took. A line
+// read while the transformer was interrupted, before it was written or queued,
+// is not included.
+func (b *Backfill) Position() Checkpoint {
+	cp := Checkpoint{File: b.handed.File, Line: b.handed.Line, Lines: b.resume.Lines + b.handed.Lines}
+	if cp.File == "" {
+		cp.File, cp.Line = b.resume.File, b.resume.Line
+	}
+	return cp
+}
+
+// handedOff records that the transformer took the last line read.
+func (b *Backfill) handedOff() {
+	b.handed = Checkpoint{File: b.file.Name, Line: b.line, Lines: b.lines}
+}
+
+// Run transforms every line of the files with t, which must read from b. The
+// checkpoint is saved as configured and when the files are exhausted or ctx is
+// cancelled, which stops the backfill without an error.
+func (b *Backfill) Run(ctx context.Context, t Transformer) error {
+	b.started = time.Now()
+	lastSave, lastProgress := b.started, b.started
+	for {
+		err := t.GetMessage(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if ctx.Err() != nil {
+			// the line being transformed may not have been queued, so the checkpoint
+			// stays at the last line handed off
+			saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+			defer cancel()
+			if err := b.save(saveCtx, t); err != nil {
+				return err
+			}
+			b.logProgress("backfill interrupted")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		b.handedOff()
+
+		now := time.Now()
+		if b.checkpointPath != "" && b.checkpointEvery > 0 && now.Sub(lastSave) >= b.checkpointEvery {
+			if err := b.save(ctx, t); err != nil {
+				return err
+			}
+			lastSave = now
+		}
+		if b.progressEvery > 0 && now.Sub(lastProgress) >= b.progressEvery {
+			b.logProgress("backfill progress")
+			lastProgress = now
+		}
+	}
+	if err := b.save(ctx, t); err != nil {
+		return err
+	}
+	b.logProgress("backfill finished")
+	return nil
+}
+
+// save flushes the transformer and saves the position reached, unless a message
+// could not be delivered, which leaves the checkpoint where it was so the
+// undelivered messages are published again on resume.
+func (b *Backfill) save(ctx context.Context, t Transformer) error {
+	if err := t.Flush(ctx); err != nil {
+		return fmt.Errorf("failed to flush before checkpoint: %w", err)
+	}
+	if n := b.failed.Load(); n > 0 {
+		return fmt.Errorf("%d messages were not delivered, resume from the last checkpoint to publish them again", n)
+	}
+	if b.checkpointPath == "" {
+		return nil
+	}
+	cp := b.Position()
+	cp.Saved = time.Now().UTC()
+	if err := cp.Save(b.checkpointPath); err != nil {
+		return err
+	}
+	b.logger.Debug("checkpoint saved", "file", cp.File, "line", cp.Line)
+	return nil
+}
+
+func (b *Backfill) logProgress(msg string) {
+	elapsed := time.Since(b.started)
+	var perSecond float64
+	if elapsed > 0 {
+		perSecond = float64(b.lines) / elapsed.Seconds()
+	}
+	attrs := []any{
+		"files", fmt.Sprintf("%d/%d", b.filesDone, len(b.files)),
+		"lines", b.lines,
+		"lines per second", fmt.Sprintf("%.1f", perSecond),
+		"elapsed", elapsed.Round(time.Second).String(),
+	}
+	if b.file.Name != "" {
+		attrs = append(attrs, "file", b.file.Name, "day", b.file.Day.Format(time.DateOnly))
+	}
+	b.logger.Info(msg, attrs...)
+}
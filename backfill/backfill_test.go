@@ -0,0 +1,174 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	slogenv "github.com/cbrewster/slog-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/stormsync/transformer"
+	"github.com/stormsync/transformer/memqueue"
+	pb "github.com/stormsync/transformer/proto"
+	"github.com/stormsync/transformer/provider"
+)
+
+var logger = slog.New(slogenv.NewHandler(slog.NewTextHandler(os.Stderr, nil)))
+
+// archive writes an archive directory and returns its path.
+func archive(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"240518_rpts_wind.csv": "Time,Speed,Location,County,State,Lat,Lon,Comments\n" +
+			"1835,UNK,2 N Holt,Irwin,GA,31.63,-83.15,Trees down. (TAE)\n",
+		"240517_rpts_hail.csv": "Time,Size,Location,County,State,Lat,Lon,Comments\n" +
+			"1830,100,2 W Ralston,Douglas,NE,41.21,-96.08,Quarter hail. (OAX)\n" +
+			"0130,175,Granbury,Hood,TX,32.36,-97.66,Golf ball hail. (FWD)\n",
+		"2024/240517_rpts_torn.csv": "1131,UNK,2 SSW Lamont,Jefferson,FL,30.35,-83.83,Tornado touched down. (TAE)\n",
+		"today_hail.csv":            "1830,100,Ralston,Douglas,NE,41.21,-96.08,Not archived. (OAX)\n",
+		"README.txt":                "not a report\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
+
+// run backfills the files to a memory topic and returns the messages written.
+func run(t *testing.T, files []File, opts ...Option) ([]memqueue.Message, *Backfill, error) {
+	t.Helper()
+	bf, err := NewBackfill(files, logger, opts...)
+	require.NoError(t, err)
+	broker := memqueue.NewBroker()
+	tr := transformer.NewTransformer(bf, memqueue.NewProvider(broker, "reports", logger), nil, logger)
+	err = bf.Run(context.Background(), tr)
+	require.NoError(t, tr.Close(context.Background()))
+	return broker.Messages("reports"), bf, err
+}
+
+func TestFind(t *testing.T) {
+	files, err := Find(archive(t))
+	require.NoError(t, err)
+
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"2024/240517_rpts_torn.csv", "240517_rpts_hail.csv", "240518_rpts_wind.csv"}, names)
+	assert.Equal(t, "2024-05-17", files[1].Day.Format(time.DateOnly))
+
+	_, err = Find(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestBackfill_Run(t *testing.T) {
+	files, err := Find(archive(t))
+	require.NoError(t, err)
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	msgs, _, err := run(t, files, WithCheckpoint(checkpoint, time.Hour), WithRate(1000))
+	require.NoError(t, err)
+	require.Len(t, msgs, 4)
+
+	var hail pb.HailMsg
+	require.NoError(t, proto.Unmarshal(msgs[2].Value, &hail))
+	assert.Equal(t, "Granbury", hail.GetLocation())
+	assert.Equal(t, "2024-05-18T01:30:00Z", time.Unix(hail.GetTime(), 0).UTC().Format(time.RFC3339), "dated on the convective day of the file")
+
+	cp, err := LoadCheckpoint(checkpoint)
+	require.NoError(t, err)
+	assert.Equal(t, "240518_rpts_wind.csv", cp.File)
+	assert.Equal(t, int64(2), cp.Line)
+	assert.Equal(t, int64(4), cp.Lines)
+
+	msgs, _, err = run(t, files, WithCheckpoint(checkpoint, time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, msgs, "everything was delivered before the checkpoint")
+
+	require.NoError(t, Checkpoint{File: "240517_rpts_hail.csv", Line: 2, Lines: 2}.Save(checkpoint))
+	msgs, _, err = run(t, files, WithCheckpoint(checkpoint, time.Hour))
+	require.NoError(t, err)
+	require.Len(t, msgs, 2, "resumes after line 2 of the hail file")
+	cp, err = LoadCheckpoint(checkpoint)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), cp.Lines)
+}
+
+func TestBackfill_DeliveryFailure(t *testing.T) {
+	files, err := Find(archive(t))
+	require.NoError(t, err)
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.json")
+	require.NoError(t, Checkpoint{File: "240517_rpts_hail.csv", Line: 2}.Save(checkpoint))
+
+	bf, err := NewBackfill(files, logger, WithCheckpoint(checkpoint, time.Hour))
+	require.NoError(t, err)
+	bf.OnDelivery(provider.WriterPayload{}, errors.New("broker unavailable"))
+	tr := transformer.NewTransformer(bf, memqueue.NewProvider(memqueue.NewBroker(), "reports", logger), nil, logger)
+	assert.ErrorContains(t, bf.Run(context.Background(), tr), "1 messages were not delivered")
+
+	cp, err := LoadCheckpoint(checkpoint)
+	require.NoError(t, err)
+	assert.Equal(t, Checkpoint{File: "240517_rpts_hail.csv", Line: 2}, cp, "the checkpoint is not moved past undelivered messages")
+}
+
+func TestLoadCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	cp, err := LoadCheckpoint(filepath.Join(dir, "missing.json"))
+	require.NoError(t, err)
+	assert.Zero(t, cp)
+
+	path := filepath.Join(dir, "bad.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"file": "notes.csv"}`), 0o644))
+	_, err = LoadCheckpoint(path)
+	assert.ErrorContains(t, err, "not an archive file")
+}
+
+// interruptedTransformer reads from the backfill and is interrupted while
+// queueing the line after the first stop lines.
+type interruptedTransformer struct {
+	bf     *Backfill
+	cancel context.CancelFunc
+	stop   int
+	read   int
+}
+
+func (t *interruptedTransformer) GetMessage(ctx context.Context) error {
+	if _, err := t.bf.ReadMessage(ctx); err != nil {
+		return err
+	}
+	if t.read++; t.read > t.stop {
+		t.cancel()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (t *interruptedTransformer) Flush(context.Context) error { return nil }
+
+func TestBackfill_Interrupted(t *testing.T) {
+	files, err := Find(archive(t))
+	require.NoError(t, err)
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	bf, err := NewBackfill(files, logger, WithCheckpoint(checkpoint, time.Hour))
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, bf.Run(ctx, &interruptedTransformer{bf: bf, cancel: cancel, stop: 2}))
+
+	cp, err := LoadCheckpoint(checkpoint)
+	require.NoError(t, err)
+	assert.Equal(t, "240517_rpts_hail.csv", cp.File, "the line read when interrupted was never queued")
+	assert.Equal(t, int64(2), cp.Line, "the first report, after the header")
+	assert.Equal(t, int64(2), cp.Lines)
+}
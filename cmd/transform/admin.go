@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/stormsync/transformer"
+)
+
+// startAdmin serves /healthz and the transformer's counters on /stats, and as
+// Prometheus metrics on /metrics, at addr.
+// It returns nil without starting a server when addr is empty.
+func startAdmin(addr string, t *transformer.Transformer, logger *slog.Logger) *http.Server {
+	if addr == "" {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("ok\n"))
+	})
+	mux.HandleFunc("GET /stats", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"dropped": t.DroppedCounts(),
+			"skipped": t.SkippedCount(),
+			"qc":      t.QCCounts(),
+		})
+	})
+
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		writeMetrics(w, t)
+	})
+
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		logger.Info("admin server listening", "address", addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("admin server failed", "error", err)
+		}
+	}()
+	return srv
+}
+
+// writeMetrics writes the transformer's counters in the Prometheus text format.
+func writeMetrics(w io.Writer, t *transformer.Transformer) {
+	fmt.Fprintln(w, "# HELP transformer_skipped_lines_total Lines skipped because they could not be parsed.")
+	fmt.Fprintln(w, "# TYPE transformer_skipped_lines_total counter")
+	fmt.Fprintf(w, "transformer_skipped_lines_total %d\n", t.SkippedCount())
+	writeCounters(w, "transformer_dropped_reports_total", "Reports dropped by each filter rule.", "rule", t.DroppedCounts())
+	writeCounters(w, "transformer_qc_failures_total", "Reports that failed each quality check.", "check", t.QCCounts())
+}
+
+// writeCounters writes a counter with one series per label value, sorted so the
+// output is stable.
+func writeCounters(w io.Writer, name, help, label string, counts map[string]int64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s{%s=%q} %d\n", name, label, k, counts[k])
+	}
+}
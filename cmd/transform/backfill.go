@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/stormsync/transformer"
+	"github.com/stormsync/transformer/backfill"
+	"github.com/stormsync/transformer/provider"
+)
+
+// backfillCommand publishes the reports of a directory of archived SPC daily
+// files, such as 240517_rpts_hail.csv, dating each on the day in its file name.
+func backfillCommand(ctx context.Context, args []string, stdio stdio) error {
+	fs, global := newFlagSet("backfill", "dir", stdio)
+	perSecond := fs.Float64("rate", 100, "most report lines to publish a second, 0 for no limit")
+	checkpoint := fs.String("checkpoint", "backfill-checkpoint.json", "file recording the position reached, which an interrupted backfill resumes from. Empty to always start from the first file")
+	checkpointEvery := fs.Duration("checkpoint-interval", 10*time.Second, "how often to save the checkpoint")
+	progressEvery := fs.Duration("progress", 30*time.Second, "how often to log progress")
+	topic := fs.String("topic", "", "topic to publish to. Defaults to the provider topic of the config")
+	outputPath := fs.String("output", "", "write messages to this file, or - for stdout, instead of the topic")
+	outputFormat := fs.String("output-format", string(provider.FormatNDJSON), "format of the output file: protobuf, ndjson or csv")
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(fs.Output(), "the archive directory is required")
+		fs.Usage()
+		return errUsage
+	}
+
+	app, err := global.load(stdio.err)
+	if err != nil {
+		return err
+	}
+	logger := app.logger
+
+	files, err := backfill.Find(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no SPC archive files such as 240517_rpts_hail.csv found in %s", fs.Arg(0))
+	}
+	logger.Info("archive files found", "dir", fs.Arg(0), "files", len(files),
+		"first", files[0].Day.Format(time.DateOnly), "last", files[len(files)-1].Day.Format(time.DateOnly))
+
+	opts := []backfill.Option{backfill.WithRate(*perSecond), backfill.WithProgressInterval(*progressEvery)}
+	if *checkpoint != "" {
+		opts = append(opts, backfill.WithCheckpoint(*checkpoint, *checkpointEvery))
+	}
+	bf, err := backfill.NewBackfill(files, logger, opts...)
+	if err != nil {
+		return err
+	}
+
+	var out provider.Provider
+	if *outputPath != "" {
+		w, name := stdio.out, "stdout"
+		if *outputPath != "-" {
+			f, err := os.Create(*outputPath)
+			if err != nil {
+				return fmt.Errorf("unable to create output: %w", err)
+			}
+			w, name = f, *outputPath
+		}
+		if out, err = provider.NewFileProvider(w, name, provider.Format(*outputFormat), logger); err != nil {
+			return fmt.Errorf("unable to create file provider: %w", err)
+		}
+	} else {
+		if *topic == "" {
+			*topic = app.cfg.Topics.Provider
+		}
+		if *topic == "" {
+			return errors.New("topic is required.  Use -topic or env var PROVIDER_TOPIC")
+		}
+		conn, err := app.kafkaConn(ctx)
+		if err != nil {
+			return err
+		}
+		onDelivery := provider.WithDeliveryCallback(func(wp provider.WriterPayload, err error) {
+			if err != nil {
+				logger.Error("message delivery failed", "type", wp.Type, "key", string(wp.Key), "size", len(wp.Body), "error", err)
+			}
+			bf.OnDelivery(wp, err)
+		})
+		if out, err = provider.NewKProvider(conn, *topic, logger, provider.WithWriterConfig(app.cfg.Kafka.Producer), onDelivery); err != nil {
+			return fmt.Errorf("unable to create provider: %w", err)
+		}
+	}
+
+	// summaries, events and alerts are left to the live service
+	reportOpts, err := app.reportOptions()
+	if err != nil {
+		return err
+	}
+	t := transformer.NewTransformer(bf, out, nil, logger, reportOpts...)
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	runErr := bf.Run(ctx, t)
+
+	closeCtx, closeCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer closeCancel()
+	if err := t.Close(closeCtx); err != nil {
+		runErr = errors.Join(runErr, fmt.Errorf("failed to close transformer: %w", err))
+	}
+	if n := t.SkippedCount(); n > 0 {
+		logger.Warn("skipped lines that could not be parsed", "skipped", n)
+	}
+	return runErr
+}
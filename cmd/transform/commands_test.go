@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stormsync/transformer"
+	"github.com/stormsync/transformer/qc"
+)
+
+const windReports = `Time,Speed,Location,County,State,Lat,Lon,Comments
+1835,UNK,2 N Holt,Irwin,GA,31.63,-83.15,Trees down on McLeod Road. (TAE)
+1900,65,Nowhere,,ZZ,0,0,Gust measured.
+garbage
+`
+
+// archiveFile writes the lines to a file named like an SPC archive of
+// 2024-05-17, so their times are dated on that day rather than today's.
+func archiveFile(t *testing.T, lines string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "240517_rpts_wind.csv")
+	require.NoError(t, os.WriteFile(path, []byte(lines), 0o600))
+	return path
+}
+
+// runCmd runs the command with stdin and returns what it wrote to stdout.
+func runCmd(t *testing.T, fn func(context.Context, []string, stdio) error, stdin string, args ...string) (string, error) {
+	t.Helper()
+	var out, logs bytes.Buffer
+	err := fn(context.Background(), append([]string{"-config", ""}, args...), stdio{in: strings.NewReader(stdin), out: &out, err: &logs})
+	return out.String(), err
+}
+
+func TestValidateCommand(t *testing.T) {
+	path := archiveFile(t, windReports)
+	out, err := runCmd(t, validateCommand, "", "-report-type", "Wind", path)
+	assert.ErrorIs(t, err, errProblems)
+	assert.Equal(t, path+`:3: failed quality checks: coordinates, state-code, empty-location
+`+path+`:4: unable to convert line to wind report "garbage": line did not contain at least 8 columns
+3 lines checked, 2 with problems
+  coordinates: 1
+  empty-location: 1
+  parse: 1
+  state-code: 1
+`, out)
+
+	clean := strings.Join(strings.Split(windReports, "\n")[:2], "\n")
+	out, err = runCmd(t, validateCommand, "", archiveFile(t, clean))
+	assert.NoError(t, err, "the type is taken from the header row")
+	assert.Equal(t, "1 lines checked, 0 with problems\n", out)
+}
+
+func TestParseAndDecodeCommands(t *testing.T) {
+	t.Setenv("PARSING_MODE", "lenient")
+	out, err := runCmd(t, parseCommand, "", "-format", "protobuf", archiveFile(t, windReports))
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "wind.pb")
+	require.NoError(t, os.WriteFile(path, []byte(out), 0o600))
+
+	decoded, err := runCmd(t, decodeCommand, "", "-type", "Wind", "-compact", path)
+	require.NoError(t, err)
+	// protojson varies its spacing between builds, so it is removed before comparing
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(decoded), " ", ""), "\n")
+	require.Len(t, lines, 2, "the unparseable line is skipped")
+	assert.Contains(t, lines[0], `"Location":"Holt"`)
+	assert.Contains(t, lines[1], `"qcIssues":["coordinates","state-code","empty-location"]`)
+
+	decoded, err = runCmd(t, decodeCommand, out, "-type", "Wind", "-n", "1", "-")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(decoded, "# Wind stdin[0]@1\n{\n"), decoded)
+	assert.Equal(t, 1, strings.Count(decoded, "# Wind"))
+
+	_, err = runCmd(t, decodeCommand, "", path)
+	assert.ErrorContains(t, err, "-type")
+	_, err = runCmd(t, decodeCommand, "\xff\xff\xff\xff\x7f", "-type", "Wind", "-")
+	assert.ErrorContains(t, err, "more than the")
+}
+
+func TestParseCommand_Strict(t *testing.T) {
+	_, err := runCmd(t, parseCommand, windReports)
+	assert.ErrorContains(t, err, "failed to parse stdin")
+
+	_, err = runCmd(t, parseCommand, "", "a.csv", "b.csv")
+	assert.ErrorIs(t, err, errUsage)
+}
+
+func TestParseCommand_Geocode(t *testing.T) {
+	_, err := loadGeocoder("", "")
+	assert.ErrorContains(t, err, "no boundaries are embedded", "empty boundaries are refused rather than geocoding nothing")
+	t.Setenv("GEOCODE", "true")
+	_, err = runCmd(t, parseCommand, "")
+	assert.ErrorContains(t, err, "no boundaries are embedded")
+	t.Setenv("GEOCODE", "false")
+
+	dir := t.TempDir()
+	square := func(props string) string {
+		return `{"type":"FeatureCollection","features":[{"type":"Feature","properties":` + props +
+			`,"geometry":{"type":"Polygon","coordinates":[[[-84,31],[-83,31],[-83,32],[-84,32],[-84,31]]]}}]}`
+	}
+	counties, cwas := filepath.Join(dir, "counties.geojson"), filepath.Join(dir, "cwas.geojson")
+	require.NoError(t, os.WriteFile(counties, []byte(square(`{"GEOID":"13155","STATEFP":"13","NAME":"Irwin"}`)), 0o600))
+	require.NoError(t, os.WriteFile(cwas, []byte(square(`{"CWA":"TAE"}`)), 0o600))
+	t.Setenv("GEO_COUNTIES", counties)
+	t.Setenv("GEO_CWAS", cwas)
+
+	clean := strings.Join(strings.Split(windReports, "\n")[:2], "\n")
+	out, err := runCmd(t, parseCommand, clean)
+	require.NoError(t, err)
+	out = strings.ReplaceAll(out, " ", "")
+	assert.Contains(t, out, `"countyFips":"13155"`)
+	assert.Contains(t, out, `"cwa":"TAE"`)
+}
+
+func TestBackfillCommand(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "240517_rpts_wind.csv"), []byte(windReports), 0o600))
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.json")
+	t.Setenv("PARSING_MODE", "lenient")
+
+	out, err := runCmd(t, backfillCommand, "", "-output", "-", "-rate", "0", "-checkpoint", checkpoint, dir)
+	require.NoError(t, err)
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(out), " ", ""), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"Time":"1715970900"`, "1835Z on 2024-05-17")
+
+	out, err = runCmd(t, backfillCommand, "", "-output", "-", "-checkpoint", checkpoint, dir)
+	require.NoError(t, err)
+	assert.Empty(t, out, "resumed after the last line")
+
+	_, err = runCmd(t, backfillCommand, "", "-output", "-")
+	assert.ErrorIs(t, err, errUsage)
+	_, err = runCmd(t, backfillCommand, "", "-output", "-", t.TempDir())
+	assert.ErrorContains(t, err, "no SPC archive files")
+}
+
+func TestWriteMetrics(t *testing.T) {
+	cfg := qc.DefaultConfig()
+	cfg.Checks = []string{"state-code", "coordinates"}
+	checker, err := qc.NewChecker(cfg)
+	require.NoError(t, err)
+	tr := transformer.NewTransformer(nil, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), transformer.WithQC(checker))
+
+	var out bytes.Buffer
+	writeMetrics(&out, tr)
+	assert.Equal(t, `# HELP transformer_skipped_lines_total Lines skipped because they could not be parsed.
+# TYPE transformer_skipped_lines_total counter
+transformer_skipped_lines_total 0
+# HELP transformer_dropped_reports_total Reports dropped by each filter rule.
+# TYPE transformer_dropped_reports_total counter
+# HELP transformer_qc_failures_total Reports that failed each quality check.
+# TYPE transformer_qc_failures_total counter
+transformer_qc_failures_total{check="coordinates"} 0
+transformer_qc_failures_total{check="state-code"} 0
+`, out.String())
+}
+
+func TestRunCommand_CSVOutputWithSummaries(t *testing.T) {
+	t.Setenv("SUMMARY_TOPIC", "summaries")
+	_, err := runCmd(t, runCommand, windReports, "-input", "-", "-report-type", "Wind", "-output", "-", "-output-format", "csv")
+	assert.EqualError(t, err, "csv output only holds reports, unset SUMMARY_TOPIC, EVENT_TOPIC and ALERT_TOPIC or use another -output-format")
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	slogenv "github.com/cbrewster/slog-env"
+
+	"github.com/stormsync/transformer"
+	"github.com/stormsync/transformer/config"
+	"github.com/stormsync/transformer/geo"
+	"github.com/stormsync/transformer/kafkaconn"
+	"github.com/stormsync/transformer/observability"
+	"github.com/stormsync/transformer/reload"
+)
+
+// globalFlags are the flags every command accepts.
+type globalFlags struct {
+	configPath string
+}
+
+// newFlagSet returns the flag set of a command with the shared flags registered.
+// args describes the arguments expected after the flags, for the usage message.
+func newFlagSet(name, args string, stdio stdio) (*flag.FlagSet, *globalFlags) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stdio.err)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: transform %s [flags] %s\n\nflags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	g := &globalFlags{}
+	fs.StringVar(&g.configPath, "config", os.Getenv("TRANSFORM_CONFIG"), "config file, see configs/transformer.yaml. Environment variables override its values. Defaults to env var TRANSFORM_CONFIG")
+	return fs, g
+}
+
+// parseFlags parses the command line, allowing at most maxArgs arguments after the flags.
+func parseFlags(fs *flag.FlagSet, args []string, maxArgs int) error {
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return err
+		}
+		return errUsage
+	}
+	if maxArgs >= 0 && fs.NArg() > maxArgs {
+		fmt.Fprintf(fs.Output(), "too many arguments: %v\n", fs.Args())
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// app is the config and logger shared by the commands.
+type app struct {
+	configPath string
+	snapshot   *reload.Snapshot
+	cfg        config.Config
+	level      *slog.LevelVar
+	logger     *slog.Logger
+}
+
+// load reads the config and sets up logging to w as it configures.
+func (g *globalFlags) load(w io.Writer) (*app, error) {
+	snapshot, err := reload.Load(g.configPath, os.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	cfg := snapshot.Config
+
+	// GO_LOG still filters per package, while the configured level can be changed by reloading
+	level := new(slog.LevelVar)
+	level.Set(snapshot.LogLevel)
+	handler, err := observability.NewLogHandler(w, cfg.Log.Format, &slog.HandlerOptions{Level: level},
+		observability.WithErrorSampling(cfg.Log.ErrorSampling))
+	if err != nil {
+		return nil, fmt.Errorf("invalid log settings: %w", err)
+	}
+	logger := slog.New(slogenv.NewHandler(handler, slogenv.WithDefaultLevel(slog.LevelDebug)))
+
+	return &app{
+		configPath: g.configPath,
+		snapshot:   snapshot,
+		cfg:        cfg,
+		level:      level,
+		logger:     logger,
+	}, nil
+}
+
+// reportOptions returns the transformer options applied to every report: the
+// filter, parsing, geocoding and quality checks of the config.
+func (a *app) reportOptions() ([]transformer.Option, error) {
+	var opts []transformer.Option
+	if a.snapshot.Rules.Filter != nil {
+		opts = append(opts, transformer.WithFilter(a.snapshot.Rules.Filter))
+	}
+	if a.cfg.Parsing.Mode == config.ParsingLenient {
+		opts = append(opts, transformer.WithLenientParsing())
+	}
+	if th := a.snapshot.Rules.Thresholds; th != nil {
+		opts = append(opts, transformer.WithClassification(*th))
+	}
+	if a.cfg.Parsing.Remarks {
+		opts = append(opts, transformer.WithRemarksAnalysis())
+	}
+	if a.cfg.Parsing.InferMagnitude {
+		opts = append(opts, transformer.WithMagnitudeInference())
+	}
+
+	if a.cfg.Parsing.Geocode || (a.cfg.Rules.Counties != "" && a.cfg.Rules.CWAs != "") {
+		geocoder, err := loadGeocoder(a.cfg.Rules.Counties, a.cfg.Rules.CWAs)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load geocoder boundaries: %w", err)
+		}
+		counties, cwas := geocoder.Counts()
+		a.logger.Info("geocoder boundaries loaded", "counties", counties, "cwas", cwas)
+		opts = append(opts, transformer.WithGeocoder(geocoder))
+	}
+	return append(opts, transformer.WithQC(a.snapshot.Rules.QC)), nil
+}
+
+// kafkaConn returns the validated Kafka connection settings. Secret references
+// in the user and password are resolved again on each new connection so rotated
+// credentials are used, and once now so a bad reference fails fast.
+func (a *app) kafkaConn(ctx context.Context) (kafkaconn.Options, error) {
+	if err := a.cfg.Kafka.Validate(); err != nil {
+		return kafkaconn.Options{}, fmt.Errorf("invalid kafka connection settings: %w", err)
+	}
+	conn := a.cfg.Kafka.Options
+	if config.IsSecretRef(conn.User) || config.IsSecretRef(conn.Password) {
+		resolver, err := config.NewResolver(a.cfg.Secrets, os.Getenv)
+		if err != nil {
+			return kafkaconn.Options{}, fmt.Errorf("unable to set up secrets: %w", err)
+		}
+		conn.Credentials = resolver.KafkaCredentials(conn.User, conn.Password)
+		if _, _, err := conn.Credentials(ctx); err != nil {
+			return kafkaconn.Options{}, fmt.Errorf("unable to read kafka credentials: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// openInput opens the file at path, or returns stdin for "-".
+func openInput(path string, stdin io.Reader) (io.Reader, string, error) {
+	if path == "-" {
+		return stdin, "stdin", nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to open input: %w", err)
+	}
+	return f, path, nil
+}
+
+// loadGeocoder builds a geocoder from the boundary files when both are given,
+// otherwise the boundaries embedded in the binary are used. Builds without
+// embedded boundaries would geocode nothing, so they need the files.
+func loadGeocoder(countiesPath, cwasPath string) (*geo.Geocoder, error) {
+	if countiesPath == "" || cwasPath == "" {
+		geocoder, err := geo.NewEmbeddedGeocoder()
+		if err != nil {
+			return nil, err
+		}
+		if counties, cwas := geocoder.Counts(); counties == 0 || cwas == 0 {
+			return nil, errors.New("no boundaries are embedded in this build, set rules.counties and rules.cwas (GEO_COUNTIES and GEO_CWAS) to boundary files or set parsing.geocode (GEOCODE) to false")
+		}
+		return geocoder, nil
+	}
+	counties, err := os.Open(countiesPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open county boundaries: %w", err)
+	}
+	defer counties.Close()
+
+	cwas, err := os.Open(cwasPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cwa boundaries: %w", err)
+	}
+	defer cwas.Close()
+
+	return geo.NewGeocoder(counties, cwas)
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/stormsync/transformer/consumer"
+	"github.com/stormsync/transformer/provider"
+)
+
+// decodeCommand prints the messages of an output topic, or of a file written
+// with -format protobuf, as JSON.
+func decodeCommand(ctx context.Context, args []string, stdio stdio) error {
+	fs, global := newFlagSet("decode", "[file|-]", stdio)
+	topic := fs.String("topic", "", "topic to read. Defaults to the provider topic of the config")
+	from := fs.String("from", "earliest", "where to start reading the topic: earliest, latest, an RFC 3339 time, or partition:offset pairs such as 0:1200,1:980")
+	to := fs.String("to", "latest", "where to stop reading the topic, exclusive: latest, an RFC 3339 time, or partition:offset pairs")
+	msgType := fs.String("type", "", "message type of a file: Hail, Wind, Tornado, LSR, Summary, Event or Alert. Topic messages carry theirs in the reportType header")
+	limit := fs.Int("n", 0, "stop after this many messages, 0 for no limit")
+	compact := fs.Bool("compact", false, "print each message on one line without its position")
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+
+	app, err := global.load(stdio.err)
+	if err != nil {
+		return err
+	}
+
+	var cons consumer.Consumer
+	if fs.NArg() == 1 {
+		if *msgType == "" {
+			return errors.New("the message type of a file is required.  Use -type")
+		}
+		in, name, err := openInput(fs.Arg(0), stdio.in)
+		if err != nil {
+			return err
+		}
+		cons = newDelimitedConsumer(in, name, *msgType)
+	} else {
+		if *topic == "" {
+			*topic = app.cfg.Topics.Provider
+		}
+		if *topic == "" {
+			return errors.New("topic is required.  Use -topic or env var PROVIDER_TOPIC")
+		}
+		replay, err := parseReplay(*from, *to)
+		if err != nil {
+			return fmt.Errorf("invalid range: %w", err)
+		}
+		conn, err := app.kafkaConn(ctx)
+		if err != nil {
+			return err
+		}
+		if cons, err = consumer.NewKConsumer(conn, *topic, app.cfg.GroupID, app.logger, consumer.WithReplay(replay)); err != nil {
+			return fmt.Errorf("unable to create consumer: %w", err)
+		}
+	}
+	defer cons.Close(ctx)
+
+	p := printer{out: stdio.out, compact: *compact}
+	for n := 0; *limit == 0 || n < *limit; n++ {
+		msg, err := cons.ReadMessage(ctx)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := p.print(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printer writes decoded messages as JSON.
+type printer struct {
+	out     io.Writer
+	compact bool
+}
+
+// print decodes the message by the type in its reportType header and writes it,
+// preceded by its type and position unless compact.
+func (p printer) print(msg consumer.ReaderResponse) error {
+	var msgType string
+	for _, h := range msg.Headers {
+		if strings.EqualFold(h.Key, "reportType") {
+			msgType = string(h.Value)
+			break
+		}
+	}
+	decoded, err := provider.Decode(provider.WriterPayload{Type: msgType, Body: msg.Value})
+	if err != nil {
+		return fmt.Errorf("%s[%d]@%d: %w", msg.Topic, msg.Partition, msg.Offset, err)
+	}
+
+	if p.compact {
+		b, err := protojson.Marshal(decoded)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(p.out, "%s\n", b)
+		return err
+	}
+	b, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(decoded)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(p.out, "# %s %s[%d]@%d\n%s\n", msgType, msg.Topic, msg.Partition, msg.Offset, b)
+	return err
+}
+
+// maxMessageSize bounds the length read for a message, so a file that is not
+// length-delimited fails instead of allocating gigabytes.
+const maxMessageSize = 64 << 20
+
+// delimitedConsumer reads the length-delimited messages of a file written with
+// provider.FormatProtobuf, which are all of one type.
+type delimitedConsumer struct {
+	name    string
+	msgType string
+	r       *bufio.Reader
+	closer  io.Closer
+	n       int64
+}
+
+func newDelimitedConsumer(r io.Reader, name, msgType string) *delimitedConsumer {
+	c := &delimitedConsumer{name: name, msgType: msgType, r: bufio.NewReader(r)}
+	if closer, ok := r.(io.Closer); ok {
+		c.closer = closer
+	}
+	return c
+}
+
+// ReadMessage returns the next message, or an error wrapping io.EOF at the end of the file.
+func (c *delimitedConsumer) ReadMessage(_ context.Context) (consumer.ReaderResponse, error) {
+	size, err := binary.ReadUvarint(c.r)
+	if errors.Is(err, io.EOF) {
+		return consumer.ReaderResponse{}, fmt.Errorf("no more messages in %s: %w", c.name, io.EOF)
+	}
+	if err != nil {
+		return consumer.ReaderResponse{}, fmt.Errorf("failed to read message length from %s: %w", c.name, err)
+	}
+	if size > maxMessageSize {
+		return consumer.ReaderResponse{}, fmt.Errorf("message %d of %s claims %d bytes, more than the %d allowed", c.n+1, c.name, size, maxMessageSize)
+	}
+	body := make([]byte, size)
+	if _, err := io.ReadFull(c.r, body); err != nil {
+		return consumer.ReaderResponse{}, fmt.Errorf("failed to read message from %s: %w", c.name, err)
+	}
+	c.n++
+	return consumer.ReaderResponse{
+		Topic:   c.name,
+		Offset:  c.n,
+		Value:   body,
+		Headers: []consumer.ReaderHeader{{Key: "reportType", Value: []byte(c.msgType)}},
+	}, nil
+}
+
+// Close closes the file.
+func (c *delimitedConsumer) Close(_ context.Context) error {
+	if c.closer != nil {
+		return c.closer.Close()
+	}
+	return nil
+}
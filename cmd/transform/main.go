@@ -1,137 +1,113 @@
+// Command transform turns raw SPC storm report lines into protobuf messages.
+//
+// The run command is the service, reading the consumer topic and writing the
+// provider topics. The other commands work on files for backfills and debugging:
+//
+//	transform run -config configs/transformer.yaml
+//	transform replay -from 2024-05-17T00:00:00Z -to latest
+//	transform parse 240517_rpts_hail.csv
+//	transform validate 240517_rpts_*.csv
+//	transform decode -from 0:1200 -n 10
+//	transform backfill -rate 200 /data/spc-archive
 package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
-	"log/slog"
 	"os"
-	"time"
-
-	jaegerPropagator "go.opentelemetry.io/contrib/propagators/jaeger"
-
-	slogenv "github.com/cbrewster/slog-env"
-	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
-	"go.opentelemetry.io/otel/sdk/resource"
-	"go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
-
-	"github.com/stormsync/transformer"
-	"github.com/stormsync/transformer/consumer"
-	"github.com/stormsync/transformer/provider"
+	"strings"
+	"text/tabwriter"
 )
 
-func main() {
-	logger := slog.New(slogenv.NewHandler(slog.NewTextHandler(os.Stderr, nil)))
-	otel.SetTextMapPropagator(jaegerPropagator.Jaeger{})
-	ctx := context.Background()
-	traceProvider, err := startTracer()
-	if err != nil {
-		log.Fatal("Unable to initiate tracer: ", err)
-	}
-	defer func() {
-		if err := traceProvider.Shutdown(context.Background()); err != nil {
-			log.Fatalf("traceprovider: %v", err)
-		}
-	}()
+// Brokers selectable with the -broker flag.
+const (
+	brokerKafka  = "kafka"
+	brokerMemory = "memory"
+)
 
-	tracer := traceProvider.Tracer("transform")
-	ctx, span := tracer.Start(ctx, "main")
-	defer span.End()
+// stdio is where a command reads its input and writes its output and logs.
+type stdio struct {
+	in  io.Reader
+	out io.Writer
+	err io.Writer
+}
 
-	var groupID = "transform-consume"
-	address := os.Getenv("KAFKA_ADDRESS")
-	if address == "" {
-		log.Fatal("address is required.  Use env var ADDRESS")
-	}
+// command is a transform subcommand. Its run func is given the arguments after
+// the command name.
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string, stdio stdio) error
+}
 
-	user := os.Getenv("KAFKA_USER")
-	if user == "" {
-		log.Fatal("kafka user is required.  Use env var KAFKA_USER")
-	}
+var commands = []command{
+	{name: "run", summary: "transform reports from the consumer topic, or a file, as a service. The default command", run: runCommand},
+	{name: "replay", summary: "reprocess a range of the consumer topic into a separate topic", run: replayCommand},
+	{name: "parse", summary: "turn SPC CSV lines from a file or stdin into JSON, CSV or protobuf on stdout", run: parseCommand},
+	{name: "validate", summary: "report SPC CSV lines that cannot be parsed or fail quality checks", run: validateCommand},
+	{name: "decode", summary: "pretty-print the protobuf messages of an output topic or file", run: decodeCommand},
+	{name: "backfill", summary: "publish the reports of a directory of archived SPC daily files, resuming from a checkpoint", run: backfillCommand},
+}
 
-	pw := os.Getenv("KAFKA_PASSWORD")
-	if pw == "" {
-		log.Fatal("kafka password is required.  Use env var KAFKA_PASSWORD")
-	}
+var (
+	// errUsage is returned for a bad command line, which has already been reported.
+	errUsage = errors.New("invalid command line")
+	// errProblems is returned by validate when lines have problems, which have already been reported.
+	errProblems = errors.New("problems found")
+)
 
-	consumerTopic := os.Getenv("CONSUMER_TOPIC")
-	if consumerTopic == "" {
-		log.Fatal("consume topic is required.  Use env var CONSUMER_TOPIC")
+func main() {
+	// flags without a command are the run command's, as before there were commands
+	name, args := "run", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
 	}
-	providerTopic := os.Getenv("PROVIDER_TOPIC")
-	if providerTopic == "" {
-		log.Fatal("provider topic is required.  Use env var PROVIDER_TOPIC")
+	if name == "help" {
+		usage(os.Stdout)
+		return
 	}
-
-	newConsumer, err := consumer.NewKConsumer(address, consumerTopic, user, pw, groupID, logger)
-
-	if err != nil {
-		log.Fatal("unable to create consume: ", err)
+	cmd, ok := findCommand(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		usage(os.Stderr)
+		os.Exit(2)
 	}
 
-	provider, err := provider.NewKProvider(address, providerTopic, user, pw, logger)
-	if err != nil {
-		log.Fatal("unable to create provider: ", err)
+	err := cmd.run(context.Background(), args, stdio{in: os.Stdin, out: os.Stdout, err: os.Stderr})
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	case errors.Is(err, errProblems):
+		os.Exit(1)
+	default:
+		log.Fatalf("%s: %v", name, err)
 	}
+}
 
-	transformer := transformer.NewTransformer(newConsumer, provider, tracer, logger)
-
-	if err != nil {
-		log.Fatal("failed to create the collect: %w", err)
-	}
-
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	logger.Info("Starting transform service")
-
-	for {
-		if err := transformer.GetMessage(ctx); err != nil {
-			logger.Error("failed to collect message: ", err)
-			cancel()
-			time.Sleep(10 * time.Second)
-			break
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
 		}
-		// TODO: remove this - using for testing
-		time.Sleep(10 * time.Second)
 	}
+	return command{}, false
 }
 
-func startTracer() (*trace.TracerProvider, error) {
-	headers := map[string]string{
-		"content-type": "application/json",
-	}
-
-	exporter, err := otlptrace.New(
-		context.Background(),
-		otlptracehttp.NewClient(
-			otlptracehttp.WithEndpoint("localhost:4318"),
-			otlptracehttp.WithHeaders(headers),
-			otlptracehttp.WithInsecure(),
-		),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create new tracing exporter: %w", err)
+// usage lists the commands.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: transform <command> [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, cmd := range commands {
+		fmt.Fprintf(tw, "  %s\t%s\n", cmd.name, cmd.summary)
 	}
-
-	tracerProvider := trace.NewTracerProvider(
-		trace.WithBatcher(
-			exporter,
-			trace.WithMaxExportBatchSize(trace.DefaultMaxExportBatchSize),
-			trace.WithBatchTimeout(trace.DefaultScheduleDelay*time.Millisecond),
-			trace.WithMaxExportBatchSize(trace.DefaultMaxExportBatchSize),
-		),
-		trace.WithResource(
-			resource.NewWithAttributes(
-				semconv.SchemaURL,
-				semconv.ServiceNameKey.String("collector"),
-			),
-		),
-	)
-
-	otel.SetTracerProvider(tracerProvider)
-
-	return tracerProvider, nil
+	tw.Flush()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "transform <command> -h" for the flags of a command. Every command reads the config given with -config.`)
 }
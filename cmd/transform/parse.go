@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/stormsync/transformer"
+	"github.com/stormsync/transformer/consumer"
+	"github.com/stormsync/transformer/provider"
+)
+
+// parseCommand transforms SPC CSV lines from a file or stdin to stdout, with the
+// same filter, enrichment and quality checks as the service.
+func parseCommand(ctx context.Context, args []string, stdio stdio) error {
+	fs, global := newFlagSet("parse", "[file|-]", stdio)
+	reportType := fs.String("report-type", "", "report type of the lines: Hail, Wind or Tornado, HistoricalHail, HistoricalWind or HistoricalTornado for the SPC database files, StormEvents for the NCEI Storm Events details files, or LSR for NWS Local Storm Report products. Taken from header rows or the file name when not set")
+	format := fs.String("format", string(provider.FormatNDJSON), "output format: ndjson, csv, or protobuf as length-delimited messages")
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+	path := "-"
+	if fs.NArg() == 1 {
+		path = fs.Arg(0)
+	}
+
+	app, err := global.load(stdio.err)
+	if err != nil {
+		return err
+	}
+	in, name, err := openInput(path, stdio.in)
+	if err != nil {
+		return err
+	}
+	cons, err := consumer.NewFileConsumer(in, name, *reportType, app.logger)
+	if err != nil {
+		return err
+	}
+	out, err := provider.NewFileProvider(stdio.out, "stdout", provider.Format(*format), app.logger)
+	if err != nil {
+		return err
+	}
+	opts, err := app.reportOptions()
+	if err != nil {
+		return err
+	}
+
+	t := transformer.NewTransformer(cons, out, nil, app.logger, opts...)
+	for {
+		if err := t.GetMessage(ctx); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return errors.Join(fmt.Errorf("failed to parse %s: %w", name, err), t.Close(ctx))
+		}
+	}
+	if n := t.SkippedCount(); n > 0 {
+		app.logger.Warn("skipped lines that could not be parsed", "input", name, "skipped", n)
+	}
+	return t.Close(ctx)
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/stormsync/collector"
+
+	"github.com/stormsync/transformer"
+	"github.com/stormsync/transformer/aggregate"
+	"github.com/stormsync/transformer/consumer"
+	"github.com/stormsync/transformer/correlate"
+	"github.com/stormsync/transformer/memqueue"
+	"github.com/stormsync/transformer/observability"
+	"github.com/stormsync/transformer/provider"
+	"github.com/stormsync/transformer/reload"
+)
+
+// runCommand runs the transform service.
+func runCommand(ctx context.Context, args []string, stdio stdio) error {
+	return serve(ctx, "run", args, stdio)
+}
+
+// replayCommand reprocesses a range of the consumer topic into a separate topic.
+func replayCommand(ctx context.Context, args []string, stdio stdio) error {
+	return serve(ctx, "replay", args, stdio)
+}
+
+// serve transforms messages until the input is exhausted or the process is
+// interrupted. Replays only write reports, so live summaries, events and alerts
+// are not duplicated.
+func serve(ctx context.Context, command string, args []string, stdio stdio) error {
+	replaying := command == "replay"
+
+	fs, global := newFlagSet(command, "", stdio)
+	brokerKind := fs.String("broker", brokerKafka, "message broker to use: kafka, or memory for local runs without a cluster")
+	seedFile := fs.String("seed", "", "file of raw report lines to load onto the consumer topic when the broker is memory")
+	seedType := fs.String("seed-type", "", "report type of the seed lines: Hail, Wind or Tornado")
+	inputPath := fs.String("input", "", "read SPC CSV lines from this file, or - for stdin, instead of the consumer topic")
+	reportType := fs.String("report-type", "", "report type of the input lines: Hail, Wind or Tornado, HistoricalHail, HistoricalWind or HistoricalTornado for the SPC database files, StormEvents for the NCEI Storm Events details files, or LSR for NWS Local Storm Report products. Taken from header rows or the file name when not set")
+	outputPath := fs.String("output", "", "write messages to this file, or - for stdout, instead of the provider topics")
+	outputFormat := fs.String("output-format", string(provider.FormatNDJSON), "format of the output file: protobuf, ndjson or csv")
+	var replayFrom, replayTo, replayTopic *string
+	if replaying {
+		replayFrom = fs.String("from", "earliest", "where to start: earliest, latest, an RFC 3339 time, or partition:offset pairs such as 0:1200,1:980")
+		replayTo = fs.String("to", "", "where to stop, exclusive: latest, an RFC 3339 time, or partition:offset pairs. Keeps reading when not set")
+		replayTopic = fs.String("output-topic", "", "topic to write the replayed reports to. Defaults to the replay topic of the config")
+	}
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	app, err := global.load(stdio.err)
+	if err != nil {
+		return err
+	}
+	cfg, logger := app.cfg, app.logger
+	logger.Info("config loaded", "path", app.configPath, "version", app.snapshot.Version)
+
+	traceProvider, err := observability.SetupTracing(ctx, cfg.Tracing)
+	if err != nil {
+		return fmt.Errorf("unable to initiate tracer: %w", err)
+	}
+	defer func() {
+		if err := traceProvider.Shutdown(context.Background()); err != nil {
+			logger.Error("failed to shut down tracer provider", "error", err)
+		}
+	}()
+
+	tracer := traceProvider.Tracer("transform")
+	ctx, span := tracer.Start(ctx, "main")
+	defer span.End()
+
+	groupID := cfg.GroupID
+	consumerTopic := cfg.Topics.Consumer
+	providerTopic := cfg.Topics.Provider
+
+	var consumerOpts []consumer.Option
+	if replaying {
+		if *brokerKind != brokerKafka || *inputPath != "" {
+			return errors.New("replay reads from the kafka consumer topic and cannot be used with -input or another broker")
+		}
+		replay, err := parseReplay(*replayFrom, *replayTo)
+		if err != nil {
+			return fmt.Errorf("invalid replay range: %w", err)
+		}
+		if *replayTopic == "" {
+			*replayTopic = cfg.Topics.Replay
+		}
+		if *outputPath == "" {
+			if *replayTopic == "" {
+				return errors.New("replay output topic is required.  Use -output-topic or env var REPLAY_TOPIC")
+			}
+			if *replayTopic == providerTopic {
+				return errors.New("replay output topic must differ from the provider topic")
+			}
+			providerTopic = *replayTopic
+		}
+		consumerOpts = append(consumerOpts, consumer.WithReplay(replay))
+	}
+
+	var newConsumer consumer.Consumer
+	var newTopicProvider func(topic string) (provider.Provider, error)
+	// async deliveries fail after GetMessage has returned and the consumer has
+	// committed the message, so the first failure is passed back to stop the
+	// service as a failed synchronous write does
+	undelivered := make(chan error, 1)
+	// the broker is only needed for whichever side is not a file
+	if *inputPath == "" || *outputPath == "" {
+		switch *brokerKind {
+		case brokerKafka:
+			if consumerTopic == "" && *inputPath == "" {
+				return errors.New("consume topic is required.  Use env var CONSUMER_TOPIC")
+			}
+			if providerTopic == "" && *outputPath == "" {
+				return errors.New("provider topic is required.  Use env var PROVIDER_TOPIC")
+			}
+			conn, err := app.kafkaConn(ctx)
+			if err != nil {
+				return err
+			}
+			if *inputPath == "" {
+				newConsumer, err = consumer.NewKConsumer(conn, consumerTopic, groupID, logger, consumerOpts...)
+				if err != nil {
+					return fmt.Errorf("unable to create consumer: %w", err)
+				}
+			}
+			onDelivery := provider.WithDeliveryCallback(func(wp provider.WriterPayload, err error) {
+				if err != nil {
+					logger.Error("message delivery failed", "type", wp.Type, "key", string(wp.Key), "size", len(wp.Body), "error", err)
+					select {
+					case undelivered <- err:
+					default:
+					}
+				}
+			})
+			newTopicProvider = func(topic string) (provider.Provider, error) {
+				return provider.NewKProvider(conn, topic, logger, provider.WithWriterConfig(cfg.Kafka.Producer), onDelivery)
+			}
+		case brokerMemory:
+			if consumerTopic == "" {
+				consumerTopic = "raw-weather-reports"
+			}
+			if providerTopic == "" {
+				providerTopic = "transformed-weather-data"
+			}
+			broker := memqueue.NewBroker()
+			if *seedFile != "" {
+				n, err := seedBroker(broker, consumerTopic, *seedFile, *seedType)
+				if err != nil {
+					return fmt.Errorf("unable to seed memory broker: %w", err)
+				}
+				logger.Info("memory broker seeded", "topic", consumerTopic, "messages", n)
+			}
+			newConsumer = memqueue.NewConsumer(broker, consumerTopic, groupID)
+			newTopicProvider = func(topic string) (provider.Provider, error) {
+				return memqueue.NewProvider(broker, topic, logger), nil
+			}
+		default:
+			return fmt.Errorf("unknown broker %q, expected %s or %s", *brokerKind, brokerKafka, brokerMemory)
+		}
+	}
+
+	if *inputPath != "" {
+		in, name, err := openInput(*inputPath, stdio.in)
+		if err != nil {
+			return err
+		}
+		fileConsumer, err := consumer.NewFileConsumer(in, name, *reportType, logger)
+		if err != nil {
+			return fmt.Errorf("unable to create file consumer: %w", err)
+		}
+		newConsumer = fileConsumer
+	}
+
+	if *outputPath != "" {
+		// CSV rows only hold reports, so summaries, events and alerts have nowhere to go
+		if provider.Format(*outputFormat) == provider.FormatCSV && !replaying &&
+			(cfg.Topics.Summary != "" || cfg.Topics.Event != "" || cfg.Topics.Alert != "") {
+			return errors.New("csv output only holds reports, unset SUMMARY_TOPIC, EVENT_TOPIC and ALERT_TOPIC or use another -output-format")
+		}
+		out, name := stdio.out, "stdout"
+		if *outputPath != "-" {
+			f, err := os.Create(*outputPath)
+			if err != nil {
+				return fmt.Errorf("unable to create output: %w", err)
+			}
+			out, name = f, *outputPath
+		}
+		// summaries, events and alerts are written alongside the reports
+		fileProvider, err := provider.NewFileProvider(out, name, provider.Format(*outputFormat), logger)
+		if err != nil {
+			return fmt.Errorf("unable to create file provider: %w", err)
+		}
+		newTopicProvider = func(string) (provider.Provider, error) {
+			return fileProvider, nil
+		}
+	}
+
+	newProvider, err := newTopicProvider(providerTopic)
+	if err != nil {
+		return fmt.Errorf("unable to create provider: %w", err)
+	}
+
+	opts, err := app.reportOptions()
+	if err != nil {
+		return err
+	}
+
+	if summaryTopic := cfg.Topics.Summary; summaryTopic != "" && !replaying {
+		aggregator, err := aggregate.NewAggregator(cfg.Aggregation.WindowSize, cfg.Aggregation.AllowedLateness)
+		if err != nil {
+			return fmt.Errorf("unable to create aggregator: %w", err)
+		}
+		summaryProvider, err := newTopicProvider(summaryTopic)
+		if err != nil {
+			return fmt.Errorf("unable to create summary provider: %w", err)
+		}
+		opts = append(opts, transformer.WithAggregator(aggregator, summaryProvider))
+	}
+
+	if eventTopic := cfg.Topics.Event; eventTopic != "" && !replaying {
+		correlator, err := correlate.NewCorrelator(cfg.Events.MaxDistanceMiles, cfg.Events.MaxGap)
+		if err != nil {
+			return fmt.Errorf("unable to create correlator: %w", err)
+		}
+		eventProvider, err := newTopicProvider(eventTopic)
+		if err != nil {
+			return fmt.Errorf("unable to create event provider: %w", err)
+		}
+		opts = append(opts, transformer.WithCorrelator(correlator, eventProvider))
+	}
+
+	alerting := cfg.Topics.Alert != "" && !replaying
+	if alerting {
+		alertProvider, err := newTopicProvider(cfg.Topics.Alert)
+		if err != nil {
+			return fmt.Errorf("unable to create alert provider: %w", err)
+		}
+		opts = append(opts, transformer.WithAlerts(app.snapshot.Rules.Alerts, alertProvider))
+	}
+
+	transformer := transformer.NewTransformer(newConsumer, newProvider, tracer, logger, opts...)
+
+	// stop reading on interrupt so summaries are still flushed, which is how a
+	// memory broker run ends once its seed lines have been transformed
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	logger.Info("Starting transform service")
+
+	admin := startAdmin(cfg.Admin.Address, transformer, logger)
+
+	// SIGHUP or an edit to the config or rule files swaps in new rules and log level
+	reloader := reload.NewReloader(app.snapshot, func() (*reload.Snapshot, error) {
+		return reload.Load(app.configPath, os.Getenv)
+	}, func(s *reload.Snapshot) error {
+		rules := s.Rules
+		if !alerting {
+			rules.Alerts = nil
+		}
+		if err := transformer.SetRules(rules); err != nil {
+			return err
+		}
+		app.level.Set(s.LogLevel)
+		return nil
+	}, logger)
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	go reloader.Run(ctx, hup, cfg.Reload.Interval)
+
+	// only throttle when following a live topic
+	throttle := *inputPath == "" && *brokerKind == brokerKafka && !replaying
+	var runErr error
+	for runErr == nil {
+		if err := transformer.GetMessage(ctx); err != nil {
+			if errors.Is(err, io.EOF) {
+				logger.Info("finished reading input", "input", *inputPath)
+				break
+			}
+			if ctx.Err() != nil {
+				break
+			}
+			runErr = fmt.Errorf("failed to collect message: %w", err)
+			break
+		}
+		select {
+		case err := <-undelivered:
+			runErr = fmt.Errorf("failed to deliver message, replay it from the consumer topic: %w", err)
+			continue
+		default:
+		}
+		// TODO: remove this - using for testing
+		if throttle {
+			time.Sleep(10 * time.Second)
+		}
+	}
+	closeCtx, closeCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer closeCancel()
+	if err := transformer.Close(closeCtx); err != nil {
+		logger.Error("failed to close transformer", "error", err)
+	}
+	// the last deliveries complete while the transformer is flushed
+	select {
+	case err := <-undelivered:
+		runErr = cmp.Or(runErr, fmt.Errorf("failed to deliver message, replay it from the consumer topic: %w", err))
+	default:
+	}
+	if admin != nil {
+		if err := admin.Shutdown(closeCtx); err != nil {
+			logger.Error("failed to stop admin server", "error", err)
+		}
+	}
+	logger.Info("transform service stopped", "dropped", transformer.DroppedCounts(), "skipped", transformer.SkippedCount(), "qc", transformer.QCCounts())
+	return runErr
+}
+
+// parseReplay parses the -from and -to replay positions.
+func parseReplay(from, to string) (consumer.Replay, error) {
+	start, err := consumer.ParsePosition(from)
+	if err != nil {
+		return consumer.Replay{}, err
+	}
+	end, err := consumer.ParsePosition(to)
+	if err != nil {
+		return consumer.Replay{}, err
+	}
+	r := consumer.Replay{Start: start, End: end}
+	return r, r.Validate()
+}
+
+// seedBroker publishes each non-empty line of the file to the topic with the
+// report type header set, returning the number of lines published.
+func seedBroker(b *memqueue.Broker, topic, path, reportType string) (int, error) {
+	rptType, err := collector.FromString(reportType)
+	if err != nil {
+		return 0, fmt.Errorf("invalid seed report type %q: %w", reportType, err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open seed file: %w", err)
+	}
+	defer f.Close()
+
+	var n int
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		b.Publish(topic, nil, []byte(line), []consumer.ReaderHeader{{Key: memqueue.ReportTypeHeader, Value: []byte(rptType.String())}})
+		n++
+	}
+	if err := scanner.Err(); err != nil {
+		return n, fmt.Errorf("failed to read seed file: %w", err)
+	}
+	return n, nil
+}
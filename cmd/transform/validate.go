@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/stormsync/transformer"
+	"github.com/stormsync/transformer/consumer"
+	"github.com/stormsync/transformer/qc"
+	"github.com/stormsync/transformer/report"
+)
+
+// validateCommand reports each line of the SPC CSV files that cannot be parsed or
+// fails a quality check, as file:line: problem, followed by a summary. It fails
+// when any line has a problem.
+func validateCommand(ctx context.Context, args []string, stdio stdio) error {
+	fs, global := newFlagSet("validate", "[file|-]...", stdio)
+	reportType := fs.String("report-type", "", "report type of the lines: Hail, Wind or Tornado, HistoricalHail, HistoricalWind or HistoricalTornado for the SPC database files, StormEvents for the NCEI Storm Events details files, or LSR for NWS Local Storm Report products. Taken from header rows or the file names when not set")
+	if err := parseFlags(fs, args, -1); err != nil {
+		return err
+	}
+	paths := fs.Args()
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+
+	app, err := global.load(stdio.err)
+	if err != nil {
+		return err
+	}
+	v := &validator{
+		checker:  app.snapshot.Rules.QC,
+		remarks:  app.cfg.Parsing.Remarks,
+		infer:    app.cfg.Parsing.InferMagnitude,
+		out:      stdio.out,
+		failures: make(map[string]int),
+	}
+	for _, path := range paths {
+		in, name, err := openInput(path, stdio.in)
+		if err != nil {
+			return err
+		}
+		cons, err := consumer.NewFileConsumer(in, name, *reportType, app.logger)
+		if err != nil {
+			return err
+		}
+		err = v.validate(ctx, cons)
+		cons.Close(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	v.summarize()
+	if v.problems > 0 {
+		return errProblems
+	}
+	return nil
+}
+
+// validator checks report lines, counting and reporting their problems.
+type validator struct {
+	checker *qc.Checker
+	remarks bool
+	infer   bool
+	out     io.Writer
+
+	lines    int
+	problems int
+	failures map[string]int // lines by the parse error or check that failed
+}
+
+// validate checks every line read from cons.
+func (v *validator) validate(ctx context.Context, cons *consumer.FileConsumer) error {
+	for {
+		msg, err := cons.ReadMessage(ctx)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			// the rest of the file cannot be read either, such as when its type is unknown
+			v.problems++
+			v.failures["unreadable"]++
+			fmt.Fprintf(v.out, "%s: %v\n", cons.Name, err)
+			return nil
+		}
+		r, err := transformer.ParseReport(msg.Headers, msg.Value)
+		if errors.Is(err, report.ErrUnsupportedEvent) {
+			// events such as floods are not reports, so are neither checked nor counted
+			continue
+		}
+		v.lines++
+		if err != nil {
+			v.problems++
+			v.failures["parse"]++
+			fmt.Fprintf(v.out, "%s:%d: %v\n", cons.Name, msg.Offset, err)
+			continue
+		}
+		// magnitudes inferred from the remarks are checked as they would be when transformed
+		if v.remarks {
+			report.ApplyRemarks(r)
+		}
+		if v.infer {
+			report.ApplyInference(r)
+		}
+		if flags := v.checker.Check(r); flags != 0 {
+			checks := qc.Names(flags)
+			v.problems++
+			for _, name := range checks {
+				v.failures[name]++
+			}
+			fmt.Fprintf(v.out, "%s:%d: failed quality checks: %s\n", cons.Name, msg.Offset, strings.Join(checks, ", "))
+		}
+	}
+}
+
+// summarize writes the number of lines checked and how many failed each check.
+func (v *validator) summarize() {
+	fmt.Fprintf(v.out, "%d lines checked, %d with problems\n", v.lines, v.problems)
+	names := make([]string, 0, len(v.failures))
+	for name := range v.failures {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		fmt.Fprintf(v.out, "  %s: %d\n", name, v.failures[name])
+	}
+}
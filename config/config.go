@@ -1,107 +1,391 @@
+// Package config loads the transformer's settings from a YAML file, applies
+// overrides from the environment and validates the result.
+//
+// Settings come from, in order of precedence, the environment, the file, and the
+// defaults returned by Default, so the service can still be run from environment
+// variables alone.
 package config
 
 import (
-	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
-	vault "github.com/hashicorp/vault/api"
+	"gopkg.in/yaml.v3"
+
+	"github.com/stormsync/transformer/kafkaconn"
+	"github.com/stormsync/transformer/observability"
+	"github.com/stormsync/transformer/provider"
+	"github.com/stormsync/transformer/report"
 )
 
+// ParsingMode decides what happens to lines that cannot be parsed.
+type ParsingMode string
+
+const (
+	// ParsingStrict stops the transformer on the first line that cannot be parsed.
+	ParsingStrict ParsingMode = "strict"
+	// ParsingLenient logs and skips lines that cannot be parsed.
+	ParsingLenient ParsingMode = "lenient"
+)
+
+// Config is the transformer's configuration.
 type Config struct {
-	Services struct {
-		Collector struct {
-			VaultPath      string   `yaml:"vault-path"`
-			Interval       string   `yaml:"interval"`
-			CollectionURLs []string `yaml:"collection-urls"`
-		} `yaml:"collector"`
-		Kafka struct {
-			Host     string `yaml:"host"`
-			Port     string `yaml:"port"`
-			Topic    string `yaml:"topic"`
-			User     string `yaml:"user"`
-			Password string `yaml:"password"`
-		} `yaml:"kafka"`
-		Redis struct {
-			Host     string `yaml:"host"`
-			Port     string `yaml:"port"`
-			User     string `yaml:"user"`
-			Password string `yaml:"password"`
-			DB       int    `yaml:db"`
-		} `yaml:"redis"`
-		Vault struct {
-			Host     string `yaml:"host"`
-			Port     string `yaml:"port"`
-			Protocol string `yaml:"protocol"`
-			Path     string `yaml:"path"`
-		} `yaml:"vault"`
-	}
-}
-
-func NewConfig(ctx context.Context, vaultToken string) (*Config, error) {
-
-	c := &Config{}
-
-	// TODO remoge this hack
-	var fn string
-	if os.Getenv("ENV") != "dev" {
-		fn = "/etc/collector-config.yml"
-	} else {
-		fn = "../../configs/upstash-development.yaml"
-	}
-	f, err := os.Open(fn)
+	Kafka       Kafka                       `yaml:"kafka"`
+	Secrets     Secrets                     `yaml:"secrets"`
+	Topics      Topics                      `yaml:"topics"`
+	GroupID     string                      `yaml:"group-id"`
+	Parsing     Parsing                     `yaml:"parsing"`
+	Rules       Rules                       `yaml:"rules"`
+	Aggregation Aggregation                 `yaml:"aggregation"`
+	Events      Events                      `yaml:"events"`
+	Tracing     observability.TracingConfig `yaml:"tracing"`
+	Admin       Admin                       `yaml:"admin"`
+	Log         Log                         `yaml:"log"`
+	Reload      Reload                      `yaml:"reload"`
+}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to open configuration file: %w", err)
-	}
+// Kafka holds the cluster connection and the producer tuning. The user and
+// password may be secret references, see SecretRefPrefix.
+type Kafka struct {
+	kafkaconn.Options `yaml:",inline"`
+	Producer          provider.WriterConfig `yaml:"producer"`
+}
 
-	defer f.Close()
+// Topics names the topics read and written. Summaries, events and alerts are only
+// produced when their topic is set.
+type Topics struct {
+	Consumer string `yaml:"consumer"`
+	Provider string `yaml:"provider"`
+	Summary  string `yaml:"summary"`
+	Event    string `yaml:"event"`
+	Alert    string `yaml:"alert"`
+	Replay   string `yaml:"replay"`
+}
 
-	dec := yaml.NewDecoder(f)
+// Parsing selects the parsing mode and the optional enrichment of parsed reports.
+type Parsing struct {
+	Mode           ParsingMode `yaml:"mode"`
+	Remarks        bool        `yaml:"remarks"`
+	InferMagnitude bool        `yaml:"infer-magnitude"`
+	Classification bool        `yaml:"classification"`
+	// Thresholds are the magnitudes reports are classified severe or significant at.
+	Thresholds report.Thresholds `yaml:"thresholds"`
+	// Geocode fills in the county, state FIPS and CWA of each report from the
+	// boundary files of the rules. It is implied when both files are set, and
+	// otherwise needs boundaries embedded at build time.
+	Geocode bool `yaml:"geocode"`
+}
 
-	if err := dec.Decode(&c); err != nil {
-		return nil, fmt.Errorf("unable to decode config file: %w", err)
-	}
+// Rules are the paths of the rule and boundary files. Empty paths use the
+// built-in defaults, or disable the stage when it has none.
+type Rules struct {
+	Filter   string `yaml:"filter"`
+	Alerts   string `yaml:"alerts"`
+	QC       string `yaml:"qc"`
+	Counties string `yaml:"counties"`
+	CWAs     string `yaml:"cwas"`
+}
+
+// Aggregation sizes the summary windows.
+type Aggregation struct {
+	WindowSize      time.Duration `yaml:"window-size"`
+	AllowedLateness time.Duration `yaml:"allowed-lateness"`
+}
 
-	vaultAddr := fmt.Sprintf("%s://%s:%s", c.Services.Vault.Protocol, c.Services.Vault.Host, c.Services.Vault.Port)
+// Events bounds how far apart in time and distance reports of one event can be.
+type Events struct {
+	MaxGap           time.Duration `yaml:"max-gap"`
+	MaxDistanceMiles float64       `yaml:"max-distance-miles"`
+}
 
-	vConfig := vault.DefaultConfig()
-	vConfig.Address = vaultAddr
-	client, err := vault.NewClient(vConfig)
-	if err != nil {
-		return nil, fmt.Errorf("unable to access vault address: %w", err)
+// Admin configures the admin HTTP server. It is disabled when Address is empty.
+type Admin struct {
+	Address string `yaml:"address"`
+}
+
+// Log configures logging. The level can be changed without a restart by reloading.
+type Log struct {
+	Level         slog.Level                  `yaml:"level"`
+	Format        string                      `yaml:"format"`
+	ErrorSampling observability.ErrorSampling `yaml:"error-sampling"`
+}
+
+// Reload configures how changes to the filter rules, alert rules and log level
+// are picked up. A SIGHUP always reloads them. Topics are only read at startup.
+type Reload struct {
+	// Interval is how often the config and rule files are checked for changes.
+	// Zero disables the check.
+	Interval time.Duration `yaml:"interval"`
+}
+
+// Default returns the configuration used for anything not set in the file or environment.
+func Default() Config {
+	return Config{
+		Kafka: Kafka{
+			Options: kafkaconn.Options{
+				Mechanism: kafkaconn.MechanismScram256,
+				TLS:       kafkaconn.TLS{Enabled: true},
+			},
+			Producer: provider.DefaultWriterConfig(),
+		},
+		Secrets: Secrets{TTL: DefaultSecretTTL},
+		GroupID: "transform-consume",
+		Parsing: Parsing{
+			Mode:           ParsingStrict,
+			Remarks:        true,
+			InferMagnitude: true,
+			Classification: true,
+			Thresholds:     report.DefaultThresholds(),
+		},
+		Aggregation: Aggregation{
+			WindowSize:      time.Hour,
+			AllowedLateness: 10 * time.Minute,
+		},
+		Events: Events{
+			MaxGap:           30 * time.Minute,
+			MaxDistanceMiles: 10,
+		},
+		Tracing: observability.DefaultTracingConfig(),
+		Log: Log{
+			Level:         slog.LevelInfo,
+			Format:        observability.LogFormatText,
+			ErrorSampling: observability.ErrorSampling{Burst: 10, Window: time.Minute},
+		},
+		Reload: Reload{Interval: 30 * time.Second},
 	}
+}
 
-	client.SetToken(vaultToken)
+// Load reads the config file at path, when one is given, over the defaults,
+// applies the environment overrides looked up with getenv, and validates the
+// result. Every problem found is returned together.
+func Load(path string, getenv func(string) string) (Config, error) {
+	cfg := Default()
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return cfg, fmt.Errorf("failed to open config file: %w", err)
+		}
+		defer f.Close()
+		if err := decode(f, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+	errs := []error{cfg.applyEnv(getenv), cfg.Validate()}
+	return cfg, errors.Join(errs...)
+}
 
-	secret, err := client.KVv2("secret").Get(ctx, "stormsync/collector/upstash-development")
-	if err != nil {
-		return nil, fmt.Errorf("Unable to read the secret from  vault: %w", err)
+// decode reads the YAML in r into cfg, rejecting unknown keys so typos are not ignored.
+func decode(r io.Reader, cfg *Config) error {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("unable to decode config file: %w", err)
 	}
+	return nil
+}
 
-	ku, ok := secret.Data["kafka-user"].(string)
-	if !ok {
-		return nil, errors.New("unable to read the secret for the key value")
+// Validate returns every problem found with the config. The Kafka connection is
+// left to Kafka.Validate since runs without a cluster do not need one.
+func (c Config) Validate() error {
+	var errs []error
+	errs = append(errs, c.validateSecretRefs())
+	if err := c.Kafka.Producer.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid producer settings: %w", err))
+	}
+	if c.Topics.Replay != "" && c.Topics.Replay == c.Topics.Provider {
+		errs = append(errs, errors.New("replay topic must differ from the provider topic"))
+	}
+	if c.Topics.Alert != "" && c.Rules.Alerts == "" {
+		errs = append(errs, errors.New("alert rules are required when an alert topic is set"))
+	}
+	if c.GroupID == "" {
+		errs = append(errs, errors.New("group id cannot be empty"))
+	}
+	switch c.Parsing.Mode {
+	case ParsingStrict, ParsingLenient:
+	default:
+		errs = append(errs, fmt.Errorf("unknown parsing mode %q, expected strict or lenient", c.Parsing.Mode))
+	}
+	if err := c.Parsing.Thresholds.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid classification thresholds: %w", err))
+	}
+	if c.Aggregation.WindowSize <= 0 {
+		errs = append(errs, errors.New("aggregation window size must be positive"))
 	}
-	c.Services.Kafka.User = ku
+	if c.Aggregation.AllowedLateness < 0 {
+		errs = append(errs, errors.New("aggregation allowed lateness cannot be negative"))
+	}
+	if c.Events.MaxGap <= 0 {
+		errs = append(errs, errors.New("event max gap must be positive"))
+	}
+	if c.Events.MaxDistanceMiles <= 0 {
+		errs = append(errs, errors.New("event max distance must be positive"))
+	}
+	if err := c.Tracing.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid tracing settings: %w", err))
+	}
+	if c.Admin.Address != "" {
+		if _, _, err := net.SplitHostPort(c.Admin.Address); err != nil {
+			errs = append(errs, fmt.Errorf("invalid admin address: %w", err))
+		}
+	}
+	switch c.Log.Format {
+	case observability.LogFormatText, observability.LogFormatJSON:
+	default:
+		errs = append(errs, fmt.Errorf("unknown log format %q, expected text or json", c.Log.Format))
+	}
+	if c.Log.ErrorSampling.Burst < 0 || c.Log.ErrorSampling.Window < 0 {
+		errs = append(errs, errors.New("log error sampling burst and window cannot be negative"))
+	}
+	if c.Reload.Interval < 0 {
+		errs = append(errs, errors.New("reload interval cannot be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// applyEnv overrides the config with the environment variables that are set.
+func (c *Config) applyEnv(getenv func(string) string) error {
+	e := &env{getenv: getenv}
+
+	if v := getenv("KAFKA_ADDRESS"); v != "" {
+		c.Kafka.Brokers = strings.Split(v, ",")
+	}
+	parse(e, "KAFKA_BROKERS", &c.Kafka.Brokers, func(v string) ([]string, error) { return strings.Split(v, ","), nil })
+	parse(e, "KAFKA_SASL_MECHANISM", &c.Kafka.Mechanism, func(v string) (kafkaconn.Mechanism, error) { return kafkaconn.Mechanism(v), nil })
+	e.string("KAFKA_USER", &c.Kafka.User)
+	e.string("KAFKA_PASSWORD", &c.Kafka.Password)
+	e.bool("KAFKA_TLS", &c.Kafka.TLS.Enabled)
+	e.string("KAFKA_TLS_CA_FILE", &c.Kafka.TLS.CAFile)
+	e.string("KAFKA_TLS_CERT_FILE", &c.Kafka.TLS.CertFile)
+	e.string("KAFKA_TLS_KEY_FILE", &c.Kafka.TLS.KeyFile)
+	e.string("KAFKA_TLS_SERVER_NAME", &c.Kafka.TLS.ServerName)
+	e.bool("KAFKA_TLS_INSECURE_SKIP_VERIFY", &c.Kafka.TLS.InsecureSkipVerify)
+
+	e.string("SECRETS_DIR", &c.Secrets.Dir)
+	e.duration("SECRETS_TTL", &c.Secrets.TTL)
+	e.string("VAULT_ADDR", &c.Secrets.Vault.Address)
+	e.string("VAULT_MOUNT", &c.Secrets.Vault.Mount)
+	e.string("VAULT_TOKEN_FILE", &c.Secrets.Vault.TokenFile)
+
+	p := &c.Kafka.Producer
+	parse(e, "PRODUCER_BATCH_SIZE", &p.BatchSize, strconv.Atoi)
+	parse(e, "PRODUCER_BATCH_BYTES", &p.BatchBytes, func(v string) (int64, error) { return strconv.ParseInt(v, 10, 64) })
+	e.duration("PRODUCER_LINGER", &p.Linger)
+	e.string("PRODUCER_COMPRESSION", &p.Compression)
+	e.string("PRODUCER_REQUIRED_ACKS", &p.RequiredAcks)
+	parse(e, "PRODUCER_MAX_ATTEMPTS", &p.MaxAttempts, strconv.Atoi)
+	e.bool("PRODUCER_ASYNC", &p.Async)
+	parse(e, "PRODUCER_QUEUE_SIZE", &p.QueueSize, strconv.Atoi)
+
+	e.string("CONSUMER_TOPIC", &c.Topics.Consumer)
+	e.string("PROVIDER_TOPIC", &c.Topics.Provider)
+	e.string("SUMMARY_TOPIC", &c.Topics.Summary)
+	e.string("EVENT_TOPIC", &c.Topics.Event)
+	e.string("ALERT_TOPIC", &c.Topics.Alert)
+	e.string("REPLAY_TOPIC", &c.Topics.Replay)
+	e.string("GROUP_ID", &c.GroupID)
+
+	parse(e, "PARSING_MODE", &c.Parsing.Mode, func(v string) (ParsingMode, error) { return ParsingMode(v), nil })
+	e.bool("GEOCODE", &c.Parsing.Geocode)
+	th := &c.Parsing.Thresholds
+	parse(e, "SEVERE_HAIL", &th.SevereHail, parseInt32)
+	parse(e, "SIGNIFICANT_HAIL", &th.SignificantHail, parseInt32)
+	parse(e, "SEVERE_WIND", &th.SevereWind, parseInt32)
+	parse(e, "SIGNIFICANT_WIND", &th.SignificantWind, parseInt32)
+	parse(e, "SIGNIFICANT_TORNADO", &th.SignificantTornado, parseInt32)
 
-	kp, ok := secret.Data["kafka-password"].(string)
-	if !ok {
-		return nil, errors.New("unable to read the secret for the key value")
+	e.string("FILTER_RULES", &c.Rules.Filter)
+	e.string("ALERT_RULES", &c.Rules.Alerts)
+	e.string("QC_CONFIG", &c.Rules.QC)
+	e.string("GEO_COUNTIES", &c.Rules.Counties)
+	e.string("GEO_CWAS", &c.Rules.CWAs)
+
+	e.duration("WINDOW_SIZE", &c.Aggregation.WindowSize)
+	e.duration("ALLOWED_LATENESS", &c.Aggregation.AllowedLateness)
+	e.duration("EVENT_MAX_GAP", &c.Events.MaxGap)
+	parse(e, "EVENT_MAX_DISTANCE_MILES", &c.Events.MaxDistanceMiles, func(v string) (float64, error) { return strconv.ParseFloat(v, 64) })
+
+	// TRACING_ENABLED predates the exporter setting, false still turns tracing off
+	if enabled, err := strconv.ParseBool(getenv("TRACING_ENABLED")); err == nil && !enabled {
+		c.Tracing.Exporter = observability.ExporterNone
 	}
-	c.Services.Kafka.Password = kp
+	e.string("TRACING_EXPORTER", &c.Tracing.Exporter)
+	e.string("TRACING_ENDPOINT", &c.Tracing.Endpoint)
+	e.bool("TRACING_INSECURE", &c.Tracing.Insecure)
+	e.keyValues("TRACING_HEADERS", &c.Tracing.Headers)
+	e.string("TRACING_SERVICE_NAME", &c.Tracing.ServiceName)
+	e.keyValues("TRACING_ATTRIBUTES", &c.Tracing.Attributes)
+	parse(e, "TRACING_SAMPLE_RATIO", &c.Tracing.SampleRatio, func(v string) (float64, error) { return strconv.ParseFloat(v, 64) })
+	parse(e, "TRACING_PROPAGATORS", &c.Tracing.Propagators, func(v string) ([]string, error) { return strings.Split(v, ","), nil })
+
+	e.string("ADMIN_ADDRESS", &c.Admin.Address)
+	parse(e, "LOG_LEVEL", &c.Log.Level, func(v string) (slog.Level, error) {
+		var level slog.Level
+		err := level.UnmarshalText([]byte(v))
+		return level, err
+	})
+	e.string("LOG_FORMAT", &c.Log.Format)
+	parse(e, "LOG_ERROR_BURST", &c.Log.ErrorSampling.Burst, strconv.Atoi)
+	e.duration("LOG_ERROR_WINDOW", &c.Log.ErrorSampling.Window)
+	e.duration("RELOAD_INTERVAL", &c.Reload.Interval)
+	return errors.Join(e.errs...)
+}
 
-	ru, ok := secret.Data["redis-user"].(string)
-	if !ok {
-		return nil, errors.New("unable to read the secret for the key value")
+// env reads overrides from the environment, collecting the values that fail to parse.
+type env struct {
+	getenv func(string) string
+	errs   []error
+}
+
+// parse sets dst from the environment variable key when it is set.
+func parse[T any](e *env, key string, dst *T, fn func(string) (T, error)) {
+	v := e.getenv(key)
+	if v == "" {
+		return
 	}
-	c.Services.Redis.User = ru
-	rp, ok := secret.Data["redis-password"].(string)
-	if !ok {
-		return nil, errors.New("unable to read the secret for the key value")
+	parsed, err := fn(v)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("invalid %s: %w", key, err))
+		return
 	}
-	c.Services.Redis.Password = rp
+	*dst = parsed
+}
+
+func (e *env) string(key string, dst *string) {
+	parse(e, key, dst, func(v string) (string, error) { return v, nil })
+}
+
+func (e *env) bool(key string, dst *bool) {
+	parse(e, key, dst, strconv.ParseBool)
+}
+
+func (e *env) duration(key string, dst *time.Duration) {
+	parse(e, key, dst, time.ParseDuration)
+}
+
+// keyValues parses comma separated key=value pairs, as in OTEL_RESOURCE_ATTRIBUTES.
+func (e *env) keyValues(key string, dst *map[string]string) {
+	parse(e, key, dst, func(v string) (map[string]string, error) {
+		m := make(map[string]string)
+		for _, pair := range strings.Split(v, ",") {
+			k, val, ok := strings.Cut(pair, "=")
+			if !ok || strings.TrimSpace(k) == "" {
+				return nil, fmt.Errorf("%q is not a key=value pair", pair)
+			}
+			m[strings.TrimSpace(k)] = strings.TrimSpace(val)
+		}
+		return m, nil
+	})
+}
 
-	return c, nil
+func parseInt32(v string) (int32, error) {
+	n, err := strconv.ParseInt(v, 10, 32)
+	return int32(n), err
 }
@@ -0,0 +1,225 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stormsync/transformer/kafkaconn"
+	"github.com/stormsync/transformer/observability"
+	"github.com/stormsync/transformer/provider"
+	"github.com/stormsync/transformer/report"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "transformer.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func mapEnv(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load("", mapEnv(nil))
+	require.NoError(t, err)
+	assert.Equal(t, Default(), cfg)
+	assert.Equal(t, provider.DefaultWriterConfig(), cfg.Kafka.Producer)
+	assert.Error(t, cfg.Kafka.Validate(), "brokers are required to use kafka")
+}
+
+func TestLoad_FileAndEnv(t *testing.T) {
+	path := writeConfig(t, `
+kafka:
+  brokers: [kafka-1:9092, kafka-2:9092]
+  sasl-mechanism: plain
+  user: file-user
+  password: file-password
+  producer:
+    compression: zstd
+    linger: 50ms
+    async: true
+topics:
+  consumer: raw
+  provider: transformed
+group-id: file-group
+parsing:
+  mode: lenient
+  infer-magnitude: false
+  thresholds:
+    severe-hail: 75
+aggregation:
+  window-size: 30m
+admin:
+  address: ":8088"
+log:
+  level: debug
+  error-sampling:
+    burst: 3
+    window: 10s
+`)
+	cfg, err := Load(path, mapEnv(map[string]string{
+		"KAFKA_PASSWORD":       "env-password",
+		"PROVIDER_TOPIC":       "env-transformed",
+		"PRODUCER_BATCH_SIZE":  "500",
+		"EVENT_MAX_GAP":        "1h",
+		"TRACING_SAMPLE_RATIO": "0.25",
+		"TRACING_EXPORTER":     "otlp-grpc",
+		"TRACING_ATTRIBUTES":   "deployment.environment=staging, team=storms",
+		"RELOAD_INTERVAL":      "1m",
+		"LOG_FORMAT":           "json",
+		"SIGNIFICANT_WIND":     "70",
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"kafka-1:9092", "kafka-2:9092"}, cfg.Kafka.Brokers)
+	assert.Equal(t, kafkaconn.MechanismPlain, cfg.Kafka.Mechanism)
+	assert.Equal(t, "file-user", cfg.Kafka.User)
+	assert.Equal(t, "env-password", cfg.Kafka.Password)
+	assert.True(t, cfg.Kafka.TLS.Enabled, "defaults are kept for keys not in the file")
+	assert.NoError(t, cfg.Kafka.Validate())
+
+	assert.Equal(t, provider.CompressionZstd, cfg.Kafka.Producer.Compression)
+	assert.Equal(t, 50*time.Millisecond, cfg.Kafka.Producer.Linger)
+	assert.Equal(t, 500, cfg.Kafka.Producer.BatchSize)
+	assert.True(t, cfg.Kafka.Producer.Async)
+
+	assert.Equal(t, Topics{Consumer: "raw", Provider: "env-transformed"}, cfg.Topics)
+	assert.Equal(t, "file-group", cfg.GroupID)
+	thresholds := report.DefaultThresholds()
+	thresholds.SevereHail, thresholds.SignificantWind = 75, 70
+	assert.Equal(t, Parsing{Mode: ParsingLenient, Remarks: true, InferMagnitude: false, Classification: true, Thresholds: thresholds}, cfg.Parsing)
+	assert.Equal(t, 30*time.Minute, cfg.Aggregation.WindowSize)
+	assert.Equal(t, time.Hour, cfg.Events.MaxGap)
+	assert.Equal(t, 0.25, cfg.Tracing.SampleRatio)
+	assert.Equal(t, observability.ExporterOTLPGRPC, cfg.Tracing.Exporter)
+	assert.Equal(t, map[string]string{"deployment.environment": "staging", "team": "storms"}, cfg.Tracing.Attributes)
+	assert.Equal(t, ":8088", cfg.Admin.Address)
+	assert.Equal(t, Log{Level: slog.LevelDebug, Format: observability.LogFormatJSON, ErrorSampling: observability.ErrorSampling{Burst: 3, Window: 10 * time.Second}}, cfg.Log)
+	assert.Equal(t, time.Minute, cfg.Reload.Interval)
+}
+
+func TestLoad_BrokersEnv(t *testing.T) {
+	cfg, err := Load("", mapEnv(map[string]string{"KAFKA_ADDRESS": "old:9092"}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"old:9092"}, cfg.Kafka.Brokers)
+
+	cfg, err = Load("", mapEnv(map[string]string{"KAFKA_ADDRESS": "old:9092", "KAFKA_BROKERS": "a:9092,b:9092"}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a:9092", "b:9092"}, cfg.Kafka.Brokers)
+}
+
+func TestLoad_TracingEnabledEnv(t *testing.T) {
+	cfg, err := Load("", mapEnv(map[string]string{"TRACING_ENABLED": "false"}))
+	require.NoError(t, err)
+	assert.Equal(t, observability.ExporterNone, cfg.Tracing.Exporter)
+
+	cfg, err = Load("", mapEnv(map[string]string{"TRACING_ENABLED": "true"}))
+	require.NoError(t, err)
+	assert.Equal(t, observability.ExporterOTLPHTTP, cfg.Tracing.Exporter)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		env     map[string]string
+		wantErr []string
+	}{
+		{
+			name:    "unknown key",
+			file:    "topics:\n  consumer: raw\n  procuder: transformed\n",
+			wantErr: []string{"procuder"},
+		},
+		{
+			name:    "malformed yaml",
+			file:    "topics: [",
+			wantErr: []string{"unable to decode config file"},
+		},
+		{
+			name: "unparseable env",
+			env: map[string]string{
+				"KAFKA_TLS":            "maybe",
+				"PRODUCER_BATCH_SIZE":  "lots",
+				"WINDOW_SIZE":          "an hour",
+				"TRACING_SAMPLE_RATIO": "half",
+				"LOG_LEVEL":            "chatty",
+				"TRACING_HEADERS":      "authorization",
+			},
+			wantErr: []string{"KAFKA_TLS", "PRODUCER_BATCH_SIZE", "WINDOW_SIZE", "TRACING_SAMPLE_RATIO", "LOG_LEVEL", "TRACING_HEADERS"},
+		},
+		{
+			name: "every invalid value",
+			file: `
+kafka:
+  producer:
+    compression: brotli
+topics:
+  provider: transformed
+  replay: transformed
+  alert: alerts
+group-id: ""
+parsing:
+  mode: relaxed
+  thresholds:
+    severe-wind: 70
+aggregation:
+  window-size: 0s
+  allowed-lateness: -1m
+events:
+  max-gap: 0s
+  max-distance-miles: -1
+tracing:
+  exporter: zipkin
+  service-name: ""
+  sample-ratio: 2
+  propagators: [tracecontext, xray]
+admin:
+  address: "8088"
+log:
+  format: logfmt
+  error-sampling:
+    burst: -1
+reload:
+  interval: -1s
+`,
+			wantErr: []string{
+				"compression codec", "replay topic", "alert rules", "group id", "parsing mode", "classification thresholds",
+				"window size", "allowed lateness", "max gap", "max distance",
+				"exporter", "service name", "sample ratio", "propagator", "admin address", "log format", "error sampling", "reload interval",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := ""
+			if tt.file != "" {
+				path = writeConfig(t, tt.file)
+			}
+			_, err := Load(path, mapEnv(tt.env))
+			require.Error(t, err)
+			for _, want := range tt.wantErr {
+				assert.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.yaml"), mapEnv(nil))
+	assert.ErrorContains(t, err, "failed to open config file")
+}
+
+func TestLoad_ExampleConfig(t *testing.T) {
+	cfg, err := Load("../configs/transformer.yaml", mapEnv(nil))
+	require.NoError(t, err)
+	assert.NoError(t, cfg.Kafka.Validate())
+	assert.Equal(t, "transformed-weather-data", cfg.Topics.Provider)
+}
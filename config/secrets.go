@@ -0,0 +1,288 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+
+	"github.com/stormsync/transformer/kafkaconn"
+)
+
+// SecretRefPrefix starts a value that refers to a secret instead of holding it,
+// written secret://<backend>/<key>, for example
+//
+//	secret://vault/stormsync/transformer#kafka-password
+//	secret://file/kafka-password
+//	secret://env/KAFKA_PASSWORD
+//
+// Vault keys are a KV v2 path and a field of the secret joined by #, file keys
+// are file names in the secrets directory, and env keys are variable names.
+const SecretRefPrefix = "secret://"
+
+// Secret backends that can be named in a secret reference.
+const (
+	BackendVault = "vault"
+	BackendFile  = "file"
+	BackendEnv   = "env"
+)
+
+// Secrets configures the backends secret references are resolved from.
+type Secrets struct {
+	Vault Vault `yaml:"vault"`
+	// Dir is the directory of mounted secret files, such as a Kubernetes secret volume.
+	Dir string `yaml:"dir"`
+	// TTL is how long a resolved value is used before the backend is read again,
+	// which is how rotated credentials are picked up.
+	TTL time.Duration `yaml:"ttl"`
+}
+
+// Vault configures the Vault KV v2 backend. The token is read from TokenFile
+// before each read when it is set, otherwise from env var VAULT_TOKEN.
+type Vault struct {
+	Address   string `yaml:"address"`
+	Mount     string `yaml:"mount"`
+	TokenFile string `yaml:"token-file"`
+}
+
+// SecretsProvider looks up the current value of a secret by key.
+type SecretsProvider interface {
+	Secret(ctx context.Context, key string) (string, error)
+}
+
+// IsSecretRef reports whether the value is a secret reference.
+func IsSecretRef(value string) bool {
+	return strings.HasPrefix(value, SecretRefPrefix)
+}
+
+// parseSecretRef splits a secret reference into its backend and key.
+func parseSecretRef(ref string) (backend, key string, err error) {
+	backend, key, ok := strings.Cut(strings.TrimPrefix(ref, SecretRefPrefix), "/")
+	if !ok || backend == "" || key == "" {
+		return "", "", fmt.Errorf("secret reference %q must be %s<backend>/<key>", ref, SecretRefPrefix)
+	}
+	return backend, key, nil
+}
+
+// EnvSecrets reads secrets from environment variables.
+type EnvSecrets struct {
+	getenv func(string) string
+}
+
+// NewEnvSecrets returns a provider of the variables looked up with getenv.
+func NewEnvSecrets(getenv func(string) string) *EnvSecrets {
+	return &EnvSecrets{getenv: getenv}
+}
+
+// Secret returns the value of the environment variable named by key.
+func (s *EnvSecrets) Secret(_ context.Context, key string) (string, error) {
+	v := s.getenv(key)
+	if v == "" {
+		return "", fmt.Errorf("environment variable %s is not set", key)
+	}
+	return v, nil
+}
+
+// FileSecrets reads secrets from files in a directory, one secret per file.
+type FileSecrets struct {
+	dir string
+}
+
+// NewFileSecrets returns a provider of the files in dir.
+func NewFileSecrets(dir string) *FileSecrets {
+	return &FileSecrets{dir: dir}
+}
+
+// Secret returns the contents of the file named by key, without trailing newlines.
+func (s *FileSecrets) Secret(_ context.Context, key string) (string, error) {
+	if !filepath.IsLocal(key) {
+		return "", fmt.Errorf("secret file %q must be inside the secrets directory", key)
+	}
+	b, err := os.ReadFile(filepath.Join(s.dir, key))
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+// VaultSecrets reads secrets from a Vault KV v2 mount.
+type VaultSecrets struct {
+	client    *vault.Client
+	mount     string
+	tokenFile string
+}
+
+// NewVaultSecrets returns a provider of the secrets in the Vault mount, "secret" when not set.
+func NewVaultSecrets(cfg Vault) (*VaultSecrets, error) {
+	vConfig := vault.DefaultConfig()
+	if cfg.Address != "" {
+		vConfig.Address = cfg.Address
+	}
+	client, err := vault.NewClient(vConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to access vault address: %w", err)
+	}
+	mount := cfg.Mount
+	if mount == "" {
+		mount = "secret"
+	}
+	return &VaultSecrets{client: client, mount: mount, tokenFile: cfg.TokenFile}, nil
+}
+
+// Secret returns the field of the secret at the path, given as path#field.
+func (s *VaultSecrets) Secret(ctx context.Context, key string) (string, error) {
+	path, field, ok := strings.Cut(key, "#")
+	if !ok || path == "" || field == "" {
+		return "", fmt.Errorf("vault secret key %q must be <path>#<field>", key)
+	}
+	if s.tokenFile != "" {
+		token, err := os.ReadFile(s.tokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read vault token: %w", err)
+		}
+		s.client.SetToken(strings.TrimSpace(string(token)))
+	}
+
+	secret, err := s.client.KVv2(s.mount).Get(ctx, path)
+	if err != nil {
+		return "", fmt.Errorf("unable to read secret %s from vault: %w", path, err)
+	}
+	v, ok := secret.Data[field].(string)
+	if !ok {
+		return "", fmt.Errorf("vault secret %s has no string field %s", path, field)
+	}
+	return v, nil
+}
+
+// DefaultSecretTTL is used when the secrets TTL is not set.
+const DefaultSecretTTL = 5 * time.Minute
+
+type cachedSecret struct {
+	value   string
+	expires time.Time
+}
+
+// Resolver resolves secret references with the configured backends, caching each
+// value for the TTL.
+type Resolver struct {
+	backends map[string]SecretsProvider
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedSecret
+}
+
+// NewResolver returns a resolver using the env backend, the file backend when a
+// secrets directory is set, and the vault backend when a vault address is set.
+func NewResolver(cfg Secrets, getenv func(string) string) (*Resolver, error) {
+	backends := map[string]SecretsProvider{BackendEnv: NewEnvSecrets(getenv)}
+	if cfg.Dir != "" {
+		backends[BackendFile] = NewFileSecrets(cfg.Dir)
+	}
+	if cfg.Vault.Address != "" {
+		v, err := NewVaultSecrets(cfg.Vault)
+		if err != nil {
+			return nil, err
+		}
+		backends[BackendVault] = v
+	}
+	return NewResolverWith(backends, cfg.TTL), nil
+}
+
+// NewResolverWith returns a resolver using the backends, keyed by the name used in
+// secret references. DefaultSecretTTL is used when ttl is not positive.
+func NewResolverWith(backends map[string]SecretsProvider, ttl time.Duration) *Resolver {
+	if ttl <= 0 {
+		ttl = DefaultSecretTTL
+	}
+	return &Resolver{
+		backends: backends,
+		ttl:      ttl,
+		now:      time.Now,
+		cache:    make(map[string]cachedSecret),
+	}
+}
+
+// Resolve returns the secret the value refers to, or the value itself when it is
+// not a secret reference.
+func (r *Resolver) Resolve(ctx context.Context, value string) (string, error) {
+	if !IsSecretRef(value) {
+		return value, nil
+	}
+	backend, key, err := parseSecretRef(value)
+	if err != nil {
+		return "", err
+	}
+	provider, ok := r.backends[backend]
+	if !ok {
+		return "", fmt.Errorf("secret backend %q of %s is not configured", backend, value)
+	}
+
+	r.mu.Lock()
+	cached, ok := r.cache[value]
+	r.mu.Unlock()
+	if ok && r.now().Before(cached.expires) {
+		return cached.value, nil
+	}
+
+	secret, err := provider.Secret(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", value, err)
+	}
+	r.mu.Lock()
+	r.cache[value] = cachedSecret{value: secret, expires: r.now().Add(r.ttl)}
+	r.mu.Unlock()
+	return secret, nil
+}
+
+// KafkaCredentials returns credentials that resolve the user and password, either
+// of which may be a secret reference, each time a connection authenticates.
+func (r *Resolver) KafkaCredentials(user, password string) kafkaconn.Credentials {
+	return func(ctx context.Context) (string, string, error) {
+		u, err := r.Resolve(ctx, user)
+		if err != nil {
+			return "", "", err
+		}
+		p, err := r.Resolve(ctx, password)
+		if err != nil {
+			return "", "", err
+		}
+		return u, p, nil
+	}
+}
+
+// validateSecretRefs returns a problem for every secret reference in the config
+// that is malformed or names a backend that is not configured.
+func (c Config) validateSecretRefs() error {
+	var errs []error
+	for _, ref := range []string{c.Kafka.User, c.Kafka.Password} {
+		if !IsSecretRef(ref) {
+			continue
+		}
+		backend, _, err := parseSecretRef(ref)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		switch {
+		case backend == BackendEnv:
+		case backend == BackendFile && c.Secrets.Dir == "":
+			errs = append(errs, fmt.Errorf("%s refers to a secret file but no secrets directory is set", ref))
+		case backend == BackendVault && c.Secrets.Vault.Address == "":
+			errs = append(errs, fmt.Errorf("%s refers to vault but no vault address is set", ref))
+		case backend != BackendFile && backend != BackendVault:
+			errs = append(errs, fmt.Errorf("%s names unknown secret backend %q, expected vault, file or env", ref, backend))
+		}
+	}
+	if c.Secrets.TTL < 0 {
+		errs = append(errs, errors.New("secrets ttl cannot be negative"))
+	}
+	return errors.Join(errs...)
+}
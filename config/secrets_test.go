@@ -0,0 +1,192 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVault serves KV v2 reads of the secrets under the "secret" mount.
+func fakeVault(t *testing.T, token string, secrets map[string]map[string]any) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != token {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		data, ok := secrets[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[]}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"data": data, "metadata": map[string]any{"version": 1}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestVaultSecrets(t *testing.T) {
+	srv := fakeVault(t, "s.token", map[string]map[string]any{
+		"/v1/secret/data/stormsync/transformer": {"kafka-password": "hunter2"},
+	})
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("s.token\n"), 0o600))
+
+	v, err := NewVaultSecrets(Vault{Address: srv.URL, TokenFile: tokenFile})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	got, err := v.Secret(ctx, "stormsync/transformer#kafka-password")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", got)
+
+	_, err = v.Secret(ctx, "stormsync/transformer#kafka-user")
+	assert.ErrorContains(t, err, "no string field kafka-user")
+	_, err = v.Secret(ctx, "stormsync/missing#kafka-password")
+	assert.Error(t, err)
+	_, err = v.Secret(ctx, "stormsync/transformer")
+	assert.ErrorContains(t, err, "<path>#<field>")
+
+	require.NoError(t, os.WriteFile(tokenFile, []byte("s.revoked"), 0o600))
+	_, err = v.Secret(ctx, "stormsync/transformer#kafka-password")
+	assert.Error(t, err, "the token file is read again before each read")
+}
+
+func TestFileSecrets(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kafka-password"), []byte("hunter2\n"), 0o600))
+	f := NewFileSecrets(dir)
+	ctx := context.Background()
+
+	got, err := f.Secret(ctx, "kafka-password")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", got)
+
+	_, err = f.Secret(ctx, "missing")
+	assert.Error(t, err)
+	_, err = f.Secret(ctx, "../etc/passwd")
+	assert.ErrorContains(t, err, "inside the secrets directory")
+}
+
+func TestEnvSecrets(t *testing.T) {
+	e := NewEnvSecrets(mapEnv(map[string]string{"KAFKA_PASSWORD": "hunter2"}))
+	got, err := e.Secret(context.Background(), "KAFKA_PASSWORD")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", got)
+	_, err = e.Secret(context.Background(), "KAFKA_USER")
+	assert.Error(t, err)
+}
+
+// countingSecrets returns the current value and counts the reads.
+type countingSecrets struct {
+	value string
+	err   error
+	reads int
+}
+
+func (s *countingSecrets) Secret(context.Context, string) (string, error) {
+	s.reads++
+	return s.value, s.err
+}
+
+func TestResolver(t *testing.T) {
+	backend := &countingSecrets{value: "old"}
+	r := NewResolverWith(map[string]SecretsProvider{"test": backend}, time.Minute)
+	now := time.Date(2024, 5, 17, 12, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	got, err := r.Resolve(ctx, "plain-password")
+	require.NoError(t, err)
+	assert.Equal(t, "plain-password", got, "values that are not references are returned as is")
+	assert.Equal(t, 0, backend.reads)
+
+	got, err = r.Resolve(ctx, "secret://test/password")
+	require.NoError(t, err)
+	assert.Equal(t, "old", got)
+
+	// rotated in the backend, but the cached value is still fresh
+	backend.value = "rotated"
+	got, _ = r.Resolve(ctx, "secret://test/password")
+	assert.Equal(t, "old", got)
+	assert.Equal(t, 1, backend.reads)
+
+	now = now.Add(time.Minute)
+	got, err = r.Resolve(ctx, "secret://test/password")
+	require.NoError(t, err)
+	assert.Equal(t, "rotated", got)
+	assert.Equal(t, 2, backend.reads)
+
+	_, err = r.Resolve(ctx, "secret://vault/stormsync#password")
+	assert.ErrorContains(t, err, "not configured")
+	_, err = r.Resolve(ctx, "secret://test")
+	assert.ErrorContains(t, err, "must be secret://<backend>/<key>")
+
+	now = now.Add(time.Minute)
+	backend.err = errors.New("permission denied")
+	_, err = r.Resolve(ctx, "secret://test/password")
+	assert.ErrorContains(t, err, "permission denied")
+}
+
+func TestResolver_KafkaCredentials(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kafka-password"), []byte("hunter2"), 0o600))
+	r, err := NewResolver(Secrets{Dir: dir, TTL: time.Nanosecond}, mapEnv(map[string]string{"KAFKA_USER": "transformer"}))
+	require.NoError(t, err)
+
+	creds := r.KafkaCredentials("secret://env/KAFKA_USER", "secret://file/kafka-password")
+	user, password, err := creds(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "transformer", user)
+	assert.Equal(t, "hunter2", password)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kafka-password"), []byte("rotated"), 0o600))
+	time.Sleep(time.Millisecond)
+	_, password, err = creds(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "rotated", password)
+}
+
+func TestLoad_SecretRefs(t *testing.T) {
+	path := writeConfig(t, `
+kafka:
+  user: secret://env/KAFKA_USER
+  password: secret://vault/stormsync/transformer#kafka-password
+secrets:
+  vault:
+    address: http://vault:8200
+`)
+	cfg, err := Load(path, mapEnv(nil))
+	require.NoError(t, err)
+	assert.Equal(t, "secret://vault/stormsync/transformer#kafka-password", cfg.Kafka.Password, "references are resolved when connecting")
+	assert.Equal(t, DefaultSecretTTL, cfg.Secrets.TTL)
+
+	path = writeConfig(t, `
+kafka:
+  user: secret://file/kafka-user
+  password: secret://vault/stormsync/transformer#kafka-password
+secrets:
+  ttl: -1s
+`)
+	_, err = Load(path, mapEnv(nil))
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "no secrets directory")
+	assert.ErrorContains(t, err, "no vault address")
+	assert.ErrorContains(t, err, "ttl cannot be negative")
+
+	_, err = Load("", mapEnv(map[string]string{"KAFKA_PASSWORD": "secret://aws/kafka"}))
+	assert.ErrorContains(t, err, "unknown secret backend")
+}
@@ -2,19 +2,28 @@ package consumer
 
 import (
 	"context"
-	"crypto/tls"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/segmentio/kafka-go"
-	"github.com/segmentio/kafka-go/sasl/scram"
+
+	"github.com/stormsync/transformer/kafkaconn"
 )
 
 type Consumer interface {
 	ReadMessage(ctx context.Context) (ReaderResponse, error)
+	// Close stops the consumer and releases its connections. It is safe to call
+	// more than once; later calls return the result of the first.
+	Close(ctx context.Context) error
 }
 
+// ErrClosed is returned when reading from a consumer that has been closed.
+var ErrClosed = errors.New("consumer is closed")
+
 type ReaderResponse struct {
 	Topic         string
 	Queue         string
@@ -33,49 +42,88 @@ type ReaderHeader struct {
 }
 
 type KConsumer struct {
-	Reader   *kafka.Reader
-	Topic    string
-	Address  string
-	user     string
-	password string
-	logger   *slog.Logger
+	Reader  *kafka.Reader
+	Topic   string
+	Address string
+	logger  *slog.Logger
+
+	dialer  *kafka.Dialer
+	brokers []string
+
+	replay     *Replay
+	replayOnce sync.Once
+	replayErr  error
+	replaying  *replayReader
+
+	closeOnce sync.Once
+	closeErr  error
 }
 
-func NewKConsumer(address, topic, user, pw, groupID string, logger *slog.Logger) (*KConsumer, error) {
-	mechanism, err := scram.Mechanism(scram.SHA256, user, pw)
+// Option configures optional behavior of a KConsumer.
+type Option func(*KConsumer)
+
+// WithReplay reads the topic between the replay's start and end positions instead
+// of resuming from the group's committed offsets, which are left untouched.
+func WithReplay(r Replay) Option {
+	return func(c *KConsumer) {
+		c.replay = &r
+	}
+}
+
+// NewKConsumer creates a consumer in the group that reads from the topic on the
+// brokers in conn, authenticating and dialing TLS as conn describes.
+func NewKConsumer(conn kafkaconn.Options, topic, groupID string, logger *slog.Logger, opts ...Option) (*KConsumer, error) {
+	dialer, err := conn.Dialer()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create scram.Mechanism for auth: %w", err)
+		return nil, fmt.Errorf("invalid kafka connection options: %w", err)
+	}
+	c := &KConsumer{
+		Topic:   topic,
+		Address: strings.Join(conn.Brokers, ","),
+		logger:  logger,
+		dialer:  dialer,
+		brokers: conn.Brokers,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.replay != nil {
+		if err := c.replay.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid replay: %w", err)
+		}
+		return c, nil
+	}
+
 	readerConfig := kafka.ReaderConfig{
 		GroupID:        groupID,
 		CommitInterval: time.Second,
-		Brokers:        []string{address},
+		Brokers:        conn.Brokers,
 		Topic:          topic,
-		Dialer: &kafka.Dialer{
-			SASLMechanism: mechanism,
-			TLS:           &tls.Config{},
-		}}
-
-	reader := kafka.NewReader(readerConfig)
-	if err != nil {
-		return nil, err
+		Dialer:         dialer,
 	}
-	return &KConsumer{
-		Reader:   reader,
-		Topic:    topic,
-		Address:  address,
-		user:     user,
-		password: pw,
-		logger:   logger,
-	}, nil
+	c.Reader = kafka.NewReader(readerConfig)
+	return c, nil
 }
 
 // ReadMessage allows for the consumer to read a message from a topic,
 // commit the message, and return a readerResponse struct.
+// When replaying, nothing is committed and an error wrapping io.EOF is
+// returned once the replay reaches its end.
 func (c *KConsumer) ReadMessage(ctx context.Context) (ReaderResponse, error) {
 	var readerResponse ReaderResponse
 	var err error
-	message, err := c.Reader.ReadMessage(ctx)
+	var message kafka.Message
+	if c.replay != nil {
+		c.replayOnce.Do(func() {
+			c.replaying, c.replayErr = c.startReplay()
+		})
+		if c.replayErr != nil {
+			return readerResponse, c.replayErr
+		}
+		message, err = c.replaying.next(ctx)
+	} else {
+		message, err = c.Reader.ReadMessage(ctx)
+	}
 	if err != nil {
 		return readerResponse, fmt.Errorf("failed to read message from topic %s: %w", c.Topic, err)
 	}
@@ -83,6 +131,37 @@ func (c *KConsumer) ReadMessage(ctx context.Context) (ReaderResponse, error) {
 	return messageToReaderResponse(message), nil
 }
 
+// Close stops reading and closes the connections to the brokers. A consumer in
+// a group commits its last offsets and leaves the group. Close returns early with
+// the context's error if the context is done first.
+func (c *KConsumer) Close(ctx context.Context) error {
+	c.closeOnce.Do(func() {
+		// keep a replay from starting once closed
+		c.replayOnce.Do(func() { c.replayErr = ErrClosed })
+
+		done := make(chan error, 1)
+		go func() {
+			switch {
+			case c.replaying != nil:
+				done <- c.replaying.close()
+			case c.Reader != nil:
+				done <- c.Reader.Close()
+			default:
+				done <- nil
+			}
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				c.closeErr = fmt.Errorf("failed to close consumer of topic %s: %w", c.Topic, err)
+			}
+		case <-ctx.Done():
+			c.closeErr = fmt.Errorf("failed to close consumer of topic %s: %w", c.Topic, ctx.Err())
+		}
+	})
+	return c.closeErr
+}
+
 func messageToReaderResponse(msg kafka.Message) ReaderResponse {
 	var rhs []ReaderHeader
 	for _, h := range msg.Headers {
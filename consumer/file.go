@@ -0,0 +1,261 @@
+package consumer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/stormsync/collector"
+
+	"github.com/stormsync/transformer/report"
+)
+
+// headerTypes maps the magnitude column of an SPC CSV header row to its report type.
+var headerTypes = map[string]collector.ReportType{
+	"SIZE":    collector.Hail,
+	"SPEED":   collector.Wind,
+	"F_SCALE": collector.Tornado,
+}
+
+// lsrSequenceLine matches the sequence number, such as 000, that may precede the
+// WMO heading of an LSR product.
+var lsrSequenceLine = regexp.MustCompile(`^\d{3}$`)
+
+// ReportDateHeader is the header carrying the SPC convective day of the report
+// line, formatted as time.DateOnly. Lines without it are dated on the current day.
+const ReportDateHeader = "reportDate"
+
+// Headers carrying the issuing office and issuance time of the NWS Local Storm
+// Report product of an LSR, the time formatted as time.RFC3339 in the product's
+// time zone.
+const (
+	LSROfficeHeader = "lsrOffice"
+	LSRIssuedHeader = "lsrIssued"
+)
+
+// FileConsumer reads SPC CSV lines from a file or stdin, one message per line.
+// Lines of the SPC historical database files, such as 1950-2023_actual_tornadoes.csv,
+// are read as the report types in report, e.g. report.HistoricalTornado.
+//
+// The report type of each line comes from, in order of precedence, the type given
+// to NewFileConsumer, the most recent header row such as "Time,Size,Location,..." or
+// "om,yr,mo,dy,...", or the file name, e.g. 240517_rpts_hail.csv. Header rows are skipped,
+// so the combined SPC daily files with a header row per section can be read whole.
+// When the file name starts with the date of an SPC archive, each line is sent
+// with that convective day in the ReportDateHeader.
+//
+// NWS Local Storm Report text products are read whole, from their WMO heading,
+// AWIPS ID or title to their $$ line, and each of their reports is sent as a
+// message of the report.LSR type with the product's office and issuance time in
+// the LSROfficeHeader and LSRIssuedHeader.
+type FileConsumer struct {
+	Name    string
+	scanner *bufio.Scanner
+	line    int64
+	logger  *slog.Logger
+
+	fixed      bool
+	reportType string
+	known      bool
+	day        string           // convective day from the file name, if any
+	pending    []ReaderResponse // reports of the last LSR product not yet read
+
+	closer    io.Closer
+	closeOnce sync.Once
+	closeErr  error
+	closed    atomic.Bool
+}
+
+var _ Consumer = (*FileConsumer)(nil)
+
+// NewFileConsumer returns a consumer of the lines read from r. The name is used
+// as the topic of each message and, when reportType is empty, to guess the type.
+// When r is an io.Closer it is closed by Close.
+func NewFileConsumer(r io.Reader, name, reportType string, logger *slog.Logger) (*FileConsumer, error) {
+	c := &FileConsumer{
+		Name:    name,
+		scanner: bufio.NewScanner(r),
+		logger:  logger,
+	}
+	if closer, ok := r.(io.Closer); ok {
+		c.closer = closer
+	}
+	if reportType != "" {
+		if _, ok := report.DatabaseParser(reportType); !ok && reportType != report.LSR {
+			if _, err := collector.FromString(reportType); err != nil {
+				return nil, fmt.Errorf("invalid report type %q: %w", reportType, err)
+			}
+		}
+		c.reportType, c.known, c.fixed = reportType, true, true
+	} else if rptType, ok := report.DatabaseTypeFromFilename(name); ok {
+		c.reportType, c.known = rptType, true
+	} else if rptType, ok := ReportTypeFromFilename(name); ok {
+		c.reportType, c.known = rptType.String(), true
+	}
+	if day, ok := ConvectiveDayFromFilename(name); ok {
+		c.day = day.Format(time.DateOnly)
+	}
+	return c, nil
+}
+
+// ConvectiveDayFromFilename parses the convective day from the name of an SPC
+// archive file such as 240517_rpts_hail.csv.
+func ConvectiveDayFromFilename(name string) (time.Time, bool) {
+	base := strings.ToLower(filepath.Base(name))
+	date, rest, ok := strings.Cut(base, "_")
+	if !ok || len(date) != 6 || !strings.HasPrefix(rest, "rpts") {
+		return time.Time{}, false
+	}
+	day, err := time.Parse("060102", date)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return day, true
+}
+
+// ReportTypeFromFilename guesses the report type from an SPC file name such as
+// 240517_rpts_hail.csv or today_torn.csv.
+func ReportTypeFromFilename(name string) (collector.ReportType, bool) {
+	base := strings.ToLower(filepath.Base(name))
+	switch {
+	case strings.Contains(base, "hail"):
+		return collector.Hail, true
+	case strings.Contains(base, "wind"):
+		return collector.Wind, true
+	case strings.Contains(base, "torn"):
+		return collector.Tornado, true
+	}
+	return 0, false
+}
+
+// ReadMessage returns the next report line with its type in the reportType header.
+// It returns an error wrapping io.EOF once the input is exhausted.
+func (c *FileConsumer) ReadMessage(ctx context.Context) (ReaderResponse, error) {
+	for {
+		if len(c.pending) > 0 && !c.closed.Load() {
+			msg := c.pending[0]
+			c.pending = c.pending[1:]
+			return msg, nil
+		}
+		if c.closed.Load() {
+			return ReaderResponse{}, fmt.Errorf("failed to read message from %s: %w", c.Name, ErrClosed)
+		}
+		if err := ctx.Err(); err != nil {
+			return ReaderResponse{}, fmt.Errorf("failed to read message from %s: %w", c.Name, err)
+		}
+		if !c.scanner.Scan() {
+			if err := c.scanner.Err(); err != nil {
+				return ReaderResponse{}, fmt.Errorf("failed to read message from %s: %w", c.Name, err)
+			}
+			return ReaderResponse{}, fmt.Errorf("no more messages in %s: %w", c.Name, io.EOF)
+		}
+		c.line++
+
+		line := strings.TrimSpace(c.scanner.Text())
+		if line == "" {
+			continue
+		}
+		if (!c.known || c.reportType == report.LSR) && lsrSequenceLine.MatchString(line) {
+			// the sequence number sent before the WMO heading of a text product
+			continue
+		}
+		if (!c.fixed || c.reportType == report.LSR) && report.IsLSRProductStart(line) {
+			if err := c.readLSRProduct(line); err != nil {
+				return ReaderResponse{}, err
+			}
+			continue
+		}
+		if rptType, ok := headerRowType(line); ok {
+			if !c.fixed {
+				c.reportType, c.known = rptType, true
+			}
+			continue
+		}
+		if !c.known {
+			return ReaderResponse{}, errors.New("unable to determine the report type, give it explicitly or include the header row")
+		}
+
+		headers := []ReaderHeader{{Key: "reportType", Value: []byte(c.reportType)}}
+		if c.day != "" {
+			headers = append(headers, ReaderHeader{Key: ReportDateHeader, Value: []byte(c.day)})
+		}
+		return ReaderResponse{
+			Topic:   c.Name,
+			Offset:  c.line,
+			Value:   []byte(line),
+			Headers: headers,
+			Time:    time.Now(),
+		}, nil
+	}
+}
+
+// readLSRProduct reads the rest of the LSR product starting with the line just
+// read, through its $$ line, and queues a message for each of its reports.
+func (c *FileConsumer) readLSRProduct(first string) error {
+	start := c.line
+	text := []byte(first)
+	for c.scanner.Scan() {
+		c.line++
+		text = append(append(text, '\n'), c.scanner.Bytes()...)
+		if strings.TrimSpace(c.scanner.Text()) == "$$" {
+			break
+		}
+	}
+	if err := c.scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read message from %s: %w", c.Name, err)
+	}
+	product, err := report.ParseLSRProduct(text)
+	if err != nil {
+		return fmt.Errorf("invalid LSR product at line %d of %s: %w", start, c.Name, err)
+	}
+	for _, e := range product.Entries {
+		c.pending = append(c.pending, ReaderResponse{
+			Topic:  c.Name,
+			Offset: start + int64(e.Line) - 1,
+			Value:  e.Text,
+			Headers: []ReaderHeader{
+				{Key: "reportType", Value: []byte(report.LSR)},
+				{Key: LSROfficeHeader, Value: []byte(product.Office)},
+				{Key: LSRIssuedHeader, Value: []byte(product.Issued.Format(time.RFC3339))},
+			},
+			Time: time.Now(),
+		})
+	}
+	return nil
+}
+
+// Close closes the underlying reader when it is an io.Closer.
+func (c *FileConsumer) Close(_ context.Context) error {
+	c.closeOnce.Do(func() {
+		c.closed.Store(true)
+		if c.closer != nil {
+			if err := c.closer.Close(); err != nil {
+				c.closeErr = fmt.Errorf("failed to close %s: %w", c.Name, err)
+			}
+		}
+	})
+	return c.closeErr
+}
+
+// headerRowType reports whether the line is the header row of an SPC daily or
+// historical database file, and the report type of the lines below it.
+func headerRowType(line string) (string, bool) {
+	if rptType, ok := report.DatabaseHeaderType(line); ok {
+		return rptType, true
+	}
+	fields := strings.SplitN(line, ",", 3)
+	if len(fields) < 2 || !strings.EqualFold(strings.TrimSpace(fields[0]), "Time") {
+		return "", false
+	}
+	rptType, ok := headerTypes[strings.ToUpper(strings.TrimSpace(fields[1]))]
+	return rptType.String(), ok
+}
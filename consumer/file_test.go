@@ -0,0 +1,214 @@
+package consumer
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	slogenv "github.com/cbrewster/slog-env"
+	"github.com/stormsync/collector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var logger = slog.New(slogenv.NewHandler(slog.NewTextHandler(os.Stderr, nil)))
+
+const combined = `Time,F_Scale,Location,County,State,Lat,Lon,Comments
+1131,UNK,2 W Tallahassee,Leon,FL,30.44,-84.32,Tornado touched down. (TAE)
+
+Time,Speed,Location,County,State,Lat,Lon,Comments
+1835,UNK,Tallahassee,Leon,FL,30.44,-84.28,Large tree limbs down. (TAE)
+Time,Size,Location,County,State,Lat,Lon,Comments
+2132,450,9 SE Granbury,Hood,TX,32.36,-97.66,4.5 inch hail. (FWD)
+`
+
+// readAll reads messages until the consumer returns an error, which is returned.
+func readAll(t *testing.T, c *FileConsumer) ([]ReaderResponse, error) {
+	t.Helper()
+	var msgs []ReaderResponse
+	for {
+		msg, err := c.ReadMessage(context.Background())
+		if err != nil {
+			return msgs, err
+		}
+		msgs = append(msgs, msg)
+	}
+}
+
+func TestFileConsumer_HeaderRows(t *testing.T) {
+	c, err := NewFileConsumer(strings.NewReader(combined), "today.csv", "", logger)
+	require.NoError(t, err)
+
+	msgs, err := readAll(t, c)
+	assert.ErrorIs(t, err, io.EOF)
+	require.Len(t, msgs, 3)
+
+	var types []string
+	for _, m := range msgs {
+		types = append(types, string(m.Headers[0].Value))
+	}
+	assert.Equal(t, []string{"Tornado", "Wind", "Hail"}, types)
+	assert.Equal(t, "today.csv", msgs[2].Topic)
+	assert.Equal(t, int64(7), msgs[2].Offset)
+	assert.Equal(t, "2132,450,9 SE Granbury,Hood,TX,32.36,-97.66,4.5 inch hail. (FWD)", string(msgs[2].Value))
+}
+
+func TestFileConsumer_ReportType(t *testing.T) {
+	line := "1835,UNK,Tallahassee,Leon,FL,30.44,-84.28,Large tree limbs down. (TAE)\n"
+
+	c, err := NewFileConsumer(strings.NewReader(line), "/data/240517_rpts_wind.csv", "", logger)
+	require.NoError(t, err)
+	msg, err := c.ReadMessage(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []byte("Wind"), msg.Headers[0].Value, "type should come from the file name")
+	assert.Equal(t, ReaderHeader{Key: ReportDateHeader, Value: []byte("2024-05-17")}, msg.Headers[1], "date should come from the file name")
+
+	c, err = NewFileConsumer(strings.NewReader(combined), "240517_rpts_hail.csv", "Hail", logger)
+	require.NoError(t, err)
+	msgs, _ := readAll(t, c)
+	for _, m := range msgs {
+		assert.Equal(t, []byte("Hail"), m.Headers[0].Value, "an explicit type should override header rows")
+	}
+
+	c, err = NewFileConsumer(strings.NewReader(line), "-", "", logger)
+	require.NoError(t, err)
+	_, err = c.ReadMessage(context.Background())
+	assert.ErrorContains(t, err, "unable to determine the report type")
+
+	_, err = NewFileConsumer(strings.NewReader(line), "-", "hail", logger)
+	assert.Error(t, err)
+}
+
+func TestReportTypeFromFilename(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   collector.ReportType
+		wantOK bool
+	}{
+		{name: "240517_rpts_hail.csv", want: collector.Hail, wantOK: true},
+		{name: "today_wind.csv", want: collector.Wind, wantOK: true},
+		{name: "/tmp/yesterday_torn.csv", want: collector.Tornado, wantOK: true},
+		{name: "today.csv", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ReportTypeFromFilename(tt.name)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestConvectiveDayFromFilename(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{name: "240517_rpts_hail.csv", want: "2024-05-17", wantOK: true},
+		{name: "/archive/1999/990503_rpts_torn.csv", want: "1999-05-03", wantOK: true},
+		{name: "240517_rpts.csv", want: "2024-05-17", wantOK: true},
+		{name: "today_wind.csv", wantOK: false},
+		{name: "241317_rpts_hail.csv", wantOK: false},
+		{name: "240517_hail.csv", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ConvectiveDayFromFilename(tt.name)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.want, got.Format(time.DateOnly))
+			}
+		})
+	}
+}
+
+func TestFileConsumer_Database(t *testing.T) {
+	const tornadoes = `om,yr,mo,dy,date,time,tz,st,stf,stn,mag,inj,fat,loss,closs,slat,slon,elat,elon,len,wid,ns,sn,sg,f1,f2,f3,f4,fc
+1,1950,1,3,1950-01-03,11:00:00,3,MO,29,1,3,3,0,6,0,38.77,-90.22,38.83,-90.03,9.5,150,2,0,1,0,0,0,0,0
+`
+	c, err := NewFileConsumer(strings.NewReader(tornadoes), "tornadoes.csv", "", logger)
+	require.NoError(t, err)
+	msgs, err := readAll(t, c)
+	assert.ErrorIs(t, err, io.EOF)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, []ReaderHeader{{Key: "reportType", Value: []byte("HistoricalTornado")}}, msgs[0].Headers, "type should come from the header row")
+
+	c, err = NewFileConsumer(strings.NewReader("1,1955,1,9,1955-01-09,15:30:00,3,GA,13,1,1.75,0,0,0,0,33.63,-84.45,0,0,0,0,1,1,1,63,0,0,0\n"), "1955-2023_hail.csv", "", logger)
+	require.NoError(t, err)
+	msg, err := c.ReadMessage(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []byte("HistoricalHail"), msg.Headers[0].Value, "type should come from the file name")
+
+	_, err = NewFileConsumer(strings.NewReader(tornadoes), "-", "HistoricalTornado", logger)
+	assert.NoError(t, err)
+}
+
+func TestFileConsumer_LSR(t *testing.T) {
+	const products = `000
+NWUS54 KOUN 200130
+LSROUN
+
+PRELIMINARY LOCAL STORM REPORT
+NATIONAL WEATHER SERVICE NORMAN OK
+830 PM CDT SUN MAY 19 2024
+
+..TIME...   ...EVENT...      ...CITY LOCATION...     ...LAT.LON...
+..DATE...   ....MAG....      ..COUNTY LOCATION..ST.. ...SOURCE....
+            ..REMARKS..
+
+0815 PM     HAIL             2 W NORMAN              35.22N 97.48W
+05/19/2024  E1.75 INCH       CLEVELAND          OK   TRAINED SPOTTER
+
+            GOLF BALL SIZE HAIL.
+
+0820 PM     TSTM WND GST     NORMAN                  35.22N 97.44W
+05/19/2024  M70 MPH          CLEVELAND          OK   ASOS
+
+            ASOS STATION KOUN.
+
+&&
+
+$$
+
+NWUS53 KLSX 200215
+LSRLSX
+
+PRELIMINARY LOCAL STORM REPORT
+NATIONAL WEATHER SERVICE ST LOUIS MO
+915 PM CDT SUN MAY 19 2024
+
+0900 PM     TORNADO          3 SSW FESTUS            38.18N 90.42W
+05/19/2024                   JEFFERSON          MO   EMERGENCY MNGR
+
+$$
+`
+	c, err := NewFileConsumer(strings.NewReader(products), "lsr.txt", "", logger)
+	require.NoError(t, err)
+	msgs, err := readAll(t, c)
+	assert.ErrorIs(t, err, io.EOF)
+	require.Len(t, msgs, 3)
+
+	assert.Equal(t, int64(13), msgs[0].Offset)
+	assert.Equal(t, []ReaderHeader{
+		{Key: "reportType", Value: []byte("LSR")},
+		{Key: LSROfficeHeader, Value: []byte("OUN")},
+		{Key: LSRIssuedHeader, Value: []byte("2024-05-19T20:30:00-05:00")},
+	}, msgs[0].Headers)
+	assert.Equal(t, "0815 PM     HAIL             2 W NORMAN              35.22N 97.48W\n"+
+		"05/19/2024  E1.75 INCH       CLEVELAND          OK   TRAINED SPOTTER\n"+
+		"            GOLF BALL SIZE HAIL.", string(msgs[0].Value))
+	assert.Equal(t, int64(34), msgs[2].Offset)
+	assert.Equal(t, []byte("LSX"), msgs[2].Headers[1].Value)
+
+	c, err = NewFileConsumer(strings.NewReader("LSROUN\nno issuance time\n$$\n"), "-", "LSR", logger)
+	require.NoError(t, err)
+	_, err = c.ReadMessage(context.Background())
+	assert.ErrorContains(t, err, "invalid LSR product at line 1 of -")
+}
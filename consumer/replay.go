@@ -0,0 +1,305 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// PositionKind is how a replay position is given.
+type PositionKind string
+
+const (
+	// PositionNone leaves the position unset: a replay without an end keeps reading.
+	PositionNone     PositionKind = ""
+	PositionEarliest PositionKind = "earliest"
+	PositionLatest   PositionKind = "latest"
+	PositionOffsets  PositionKind = "offsets"
+	PositionTime     PositionKind = "time"
+)
+
+// Position is a point in a topic, either the earliest or latest offset, an
+// offset per partition, or the first message at or after a time.
+type Position struct {
+	Kind    PositionKind
+	Offsets map[int]int64
+	Time    time.Time
+}
+
+// ParsePosition parses "earliest", "latest", an RFC 3339 time, or a comma separated
+// list of partition:offset pairs such as "0:1200,1:980".
+func ParsePosition(s string) (Position, error) {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(s) {
+	case "":
+		return Position{}, nil
+	case string(PositionEarliest):
+		return Position{Kind: PositionEarliest}, nil
+	case string(PositionLatest):
+		return Position{Kind: PositionLatest}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return Position{Kind: PositionTime, Time: t}, nil
+	}
+
+	offsets := make(map[int]int64)
+	for _, pair := range strings.Split(s, ",") {
+		partition, offset, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			return Position{}, fmt.Errorf("invalid position %q, expected earliest, latest, an RFC 3339 time or partition:offset pairs", s)
+		}
+		p, err := strconv.Atoi(partition)
+		if err != nil || p < 0 {
+			return Position{}, fmt.Errorf("invalid partition %q in position %q", partition, s)
+		}
+		o, err := strconv.ParseInt(offset, 10, 64)
+		if err != nil || o < 0 {
+			return Position{}, fmt.Errorf("invalid offset %q in position %q", offset, s)
+		}
+		offsets[p] = o
+	}
+	return Position{Kind: PositionOffsets, Offsets: offsets}, nil
+}
+
+// Replay reads a topic from a start position, ignoring and leaving untouched the
+// committed offsets of any consumer group. When End is set the replay stops once
+// every partition reaches it, and ReadMessage returns an error wrapping io.EOF.
+// End offsets and times are exclusive, and a partition missing from an offsets
+// start position is not replayed.
+type Replay struct {
+	Start Position
+	End   Position
+}
+
+// Validate returns every problem found with the replay positions.
+func (r Replay) Validate() error {
+	var errs []error
+	switch r.Start.Kind {
+	case PositionEarliest, PositionLatest, PositionOffsets, PositionTime:
+	case PositionNone:
+		errs = append(errs, errors.New("replay requires a start position"))
+	default:
+		errs = append(errs, fmt.Errorf("unknown replay start %q", r.Start.Kind))
+	}
+	switch r.End.Kind {
+	case PositionNone, PositionLatest, PositionOffsets, PositionTime:
+	case PositionEarliest:
+		errs = append(errs, errors.New("replay cannot end at the earliest offset"))
+	default:
+		errs = append(errs, fmt.Errorf("unknown replay end %q", r.End.Kind))
+	}
+	if r.Start.Kind == PositionOffsets && len(r.Start.Offsets) == 0 {
+		errs = append(errs, errors.New("replay start offsets cannot be empty"))
+	}
+	if r.End.Kind == PositionOffsets && len(r.End.Offsets) == 0 {
+		errs = append(errs, errors.New("replay end offsets cannot be empty"))
+	}
+	if r.Start.Kind == PositionTime && r.End.Kind == PositionTime && !r.End.Time.After(r.Start.Time) {
+		errs = append(errs, errors.New("replay end time must be after the start time"))
+	}
+	return errors.Join(errs...)
+}
+
+// offsetReader looks up offsets of a partition. *kafka.Conn satisfies it.
+type offsetReader interface {
+	ReadFirstOffset() (int64, error)
+	ReadLastOffset() (int64, error)
+	ReadOffset(t time.Time) (int64, error)
+}
+
+// noEnd is the end offset of a partition that is read until the replay is closed.
+const noEnd = -1
+
+// bounds resolves the offsets to replay a partition between. The bool is false
+// when the partition is not part of the replay.
+func (r Replay) bounds(partition int, conn offsetReader) (int64, int64, bool, error) {
+	start, ok, err := resolve(r.Start, partition, conn)
+	if err != nil || !ok {
+		return 0, 0, false, err
+	}
+	end := int64(noEnd)
+	if r.End.Kind != PositionNone {
+		e, ok, err := resolve(r.End, partition, conn)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		if ok {
+			end = max(e, start)
+		} else {
+			// a partition left out of the end offsets has nothing to replay
+			end = start
+		}
+	}
+	return start, end, true, nil
+}
+
+func resolve(p Position, partition int, conn offsetReader) (int64, bool, error) {
+	switch p.Kind {
+	case PositionEarliest:
+		o, err := conn.ReadFirstOffset()
+		return o, err == nil, err
+	case PositionLatest:
+		o, err := conn.ReadLastOffset()
+		return o, err == nil, err
+	case PositionOffsets:
+		o, ok := p.Offsets[partition]
+		if !ok {
+			return 0, false, nil
+		}
+		first, err := conn.ReadFirstOffset()
+		if err != nil {
+			return 0, false, err
+		}
+		last, err := conn.ReadLastOffset()
+		if err != nil {
+			return 0, false, err
+		}
+		return min(max(o, first), last), true, nil
+	case PositionTime:
+		o, err := conn.ReadOffset(p.Time)
+		if err != nil {
+			return 0, false, err
+		}
+		if o < 0 {
+			// no message at or after the time yet
+			o, err = conn.ReadLastOffset()
+		}
+		return o, err == nil, err
+	}
+	return 0, false, fmt.Errorf("unknown position %q", p.Kind)
+}
+
+type replayEvent struct {
+	msg  kafka.Message
+	err  error
+	done bool
+}
+
+// replayReader merges the messages of one reader per replayed partition.
+type replayReader struct {
+	events    chan replayEvent
+	remaining int
+	readers   []*kafka.Reader
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// startReplay resolves the bounds of each partition and starts reading them.
+func (c *KConsumer) startReplay() (*replayReader, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	rr := &replayReader{events: make(chan replayEvent), cancel: cancel}
+
+	partitions, err := c.dialer.LookupPartitions(ctx, "tcp", c.brokers[0], c.Topic)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to look up partitions of topic %s: %w", c.Topic, err)
+	}
+	slices.SortFunc(partitions, func(a, b kafka.Partition) int { return a.ID - b.ID })
+
+	for _, p := range partitions {
+		conn, err := c.dialer.DialLeader(ctx, "tcp", c.brokers[0], c.Topic, p.ID)
+		if err != nil {
+			rr.close()
+			return nil, fmt.Errorf("failed to dial leader of partition %d: %w", p.ID, err)
+		}
+		start, end, ok, err := c.replay.bounds(p.ID, conn)
+		conn.Close()
+		if err != nil {
+			rr.close()
+			return nil, fmt.Errorf("failed to resolve replay offsets of partition %d: %w", p.ID, err)
+		}
+		if !ok || start == end {
+			continue
+		}
+
+		reader := kafka.NewReader(kafka.ReaderConfig{
+			Brokers:   c.brokers,
+			Topic:     c.Topic,
+			Partition: p.ID,
+			Dialer:    c.dialer,
+		})
+		if err := reader.SetOffset(start); err != nil {
+			reader.Close()
+			rr.close()
+			return nil, fmt.Errorf("failed to seek partition %d to offset %d: %w", p.ID, start, err)
+		}
+		c.logger.Info("replaying partition", "topic", c.Topic, "partition", p.ID, "start", start, "end", end)
+
+		rr.readers = append(rr.readers, reader)
+		rr.remaining++
+		rr.wg.Add(1)
+		go rr.read(ctx, reader, end)
+	}
+	return rr, nil
+}
+
+// read sends the messages of the partition until its end offset is reached.
+func (rr *replayReader) read(ctx context.Context, reader *kafka.Reader, end int64) {
+	defer rr.wg.Done()
+	send := func(ev replayEvent) bool {
+		select {
+		case rr.events <- ev:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	for {
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				send(replayEvent{err: err})
+			}
+			return
+		}
+		if end != noEnd && msg.Offset >= end {
+			send(replayEvent{done: true})
+			return
+		}
+		if !send(replayEvent{msg: msg}) {
+			return
+		}
+		if end != noEnd && msg.Offset+1 >= end {
+			send(replayEvent{done: true})
+			return
+		}
+	}
+}
+
+// next returns the next replayed message, or io.EOF once every partition is done.
+func (rr *replayReader) next(ctx context.Context) (kafka.Message, error) {
+	for rr.remaining > 0 {
+		select {
+		case <-ctx.Done():
+			return kafka.Message{}, ctx.Err()
+		case ev := <-rr.events:
+			switch {
+			case ev.err != nil:
+				return kafka.Message{}, ev.err
+			case ev.done:
+				rr.remaining--
+			default:
+				return ev.msg, nil
+			}
+		}
+	}
+	return kafka.Message{}, io.EOF
+}
+
+func (rr *replayReader) close() error {
+	rr.cancel()
+	rr.wg.Wait()
+	var errs []error
+	for _, r := range rr.readers {
+		errs = append(errs, r.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,129 @@
+package consumer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOffsets is a partition holding offsets first through last-1 with one
+// message a minute from base.
+type fakeOffsets struct {
+	first, last int64
+	base        time.Time
+}
+
+func (f fakeOffsets) ReadFirstOffset() (int64, error) { return f.first, nil }
+func (f fakeOffsets) ReadLastOffset() (int64, error)  { return f.last, nil }
+func (f fakeOffsets) ReadOffset(t time.Time) (int64, error) {
+	o := f.first + int64(t.Sub(f.base)/time.Minute)
+	if o >= f.last {
+		return -1, nil
+	}
+	return max(o, f.first), nil
+}
+
+func TestParsePosition(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Position
+		wantErr bool
+	}{
+		{in: "", want: Position{}},
+		{in: "Earliest", want: Position{Kind: PositionEarliest}},
+		{in: "latest", want: Position{Kind: PositionLatest}},
+		{in: "2024-05-17T18:00:00Z", want: Position{Kind: PositionTime, Time: time.Date(2024, 5, 17, 18, 0, 0, 0, time.UTC)}},
+		{in: "0:1200, 1:980", want: Position{Kind: PositionOffsets, Offsets: map[int]int64{0: 1200, 1: 980}}},
+		{in: "yesterday", wantErr: true},
+		{in: "0:-5", wantErr: true},
+		{in: "a:5", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParsePosition(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.want.Time.Equal(got.Time))
+			got.Time, tt.want.Time = time.Time{}, time.Time{}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReplay_Validate(t *testing.T) {
+	start := time.Date(2024, 5, 17, 18, 0, 0, 0, time.UTC)
+	assert.NoError(t, Replay{Start: Position{Kind: PositionEarliest}}.Validate())
+	assert.NoError(t, Replay{Start: Position{Kind: PositionTime, Time: start}, End: Position{Kind: PositionLatest}}.Validate())
+
+	err := Replay{End: Position{Kind: PositionEarliest}}.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires a start position")
+	assert.Contains(t, err.Error(), "cannot end at the earliest")
+
+	err = Replay{Start: Position{Kind: PositionTime, Time: start}, End: Position{Kind: PositionTime, Time: start}}.Validate()
+	assert.ErrorContains(t, err, "end time must be after the start time")
+}
+
+func TestReplay_bounds(t *testing.T) {
+	base := time.Date(2024, 5, 17, 18, 0, 0, 0, time.UTC)
+	partition := fakeOffsets{first: 100, last: 200, base: base}
+	tests := []struct {
+		name      string
+		replay    Replay
+		wantStart int64
+		wantEnd   int64
+		wantOK    bool
+	}{
+		{
+			name:      "should read from earliest without an end",
+			replay:    Replay{Start: Position{Kind: PositionEarliest}},
+			wantStart: 100, wantEnd: noEnd, wantOK: true,
+		},
+		{
+			name:      "should stop at the latest offset when the replay started",
+			replay:    Replay{Start: Position{Kind: PositionEarliest}, End: Position{Kind: PositionLatest}},
+			wantStart: 100, wantEnd: 200, wantOK: true,
+		},
+		{
+			name:      "should seek to a time and stop at another",
+			replay:    Replay{Start: Position{Kind: PositionTime, Time: base.Add(10 * time.Minute)}, End: Position{Kind: PositionTime, Time: base.Add(30 * time.Minute)}},
+			wantStart: 110, wantEnd: 130, wantOK: true,
+		},
+		{
+			name:      "should start at latest for a time after the last message",
+			replay:    Replay{Start: Position{Kind: PositionTime, Time: base.Add(time.Hour * 5)}},
+			wantStart: 200, wantEnd: noEnd, wantOK: true,
+		},
+		{
+			name:      "should clamp offsets to the partition",
+			replay:    Replay{Start: Position{Kind: PositionOffsets, Offsets: map[int]int64{0: 5}}, End: Position{Kind: PositionOffsets, Offsets: map[int]int64{0: 5000}}},
+			wantStart: 100, wantEnd: 200, wantOK: true,
+		},
+		{
+			name:   "should skip a partition without a start offset",
+			replay: Replay{Start: Position{Kind: PositionOffsets, Offsets: map[int]int64{1: 150}}},
+			wantOK: false,
+		},
+		{
+			name:      "should not read a partition without an end offset",
+			replay:    Replay{Start: Position{Kind: PositionEarliest}, End: Position{Kind: PositionOffsets, Offsets: map[int]int64{1: 150}}},
+			wantStart: 100, wantEnd: 100, wantOK: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, ok, err := tt.replay.bounds(0, partition)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.wantStart, start)
+				assert.Equal(t, tt.wantEnd, end)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+// Package correlate clusters reports of the same type that are close together in
+// space and time into events, so several spotter reports of one storm can be
+// tied together.
+package correlate
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/stormsync/transformer/geo"
+	report "github.com/stormsync/transformer/proto"
+	rpt "github.com/stormsync/transformer/report"
+)
+
+// EventType is the report type used when producing event messages.
+const EventType = "Event"
+
+type member struct {
+	time     int64
+	lat, lon float64
+}
+
+type event struct {
+	msg     *report.EventMsg
+	members []member
+	sumLat  float64
+	sumLon  float64
+}
+
+// Correlator assigns reports to events. A report joins an event when it is within
+// the distance and time thresholds of any report already in that event, and the
+// closest such event is chosen when more than one qualifies. Events that can no
+// longer gain members, because the latest report seen is more than the time
+// threshold past their last member, are forgotten.
+type Correlator struct {
+	maxDistance float64
+	maxGap      int64
+
+	mu      sync.Mutex
+	maxTime int64
+	events  map[string]*event
+}
+
+// NewCorrelator returns a Correlator using the distance threshold in miles and the time threshold.
+func NewCorrelator(maxDistanceMiles float64, maxGap time.Duration) (*Correlator, error) {
+	if maxDistanceMiles <= 0 {
+		return nil, errors.New("max distance must be positive")
+	}
+	if maxGap < time.Second {
+		return nil, errors.New("max gap must be at least one second")
+	}
+	return &Correlator{
+		maxDistance: maxDistanceMiles,
+		maxGap:      int64(maxGap / time.Second),
+		events:      make(map[string]*event),
+	}, nil
+}
+
+// Correlate assigns the report to an event, recording the event ID on the report,
+// and returns a copy of the event as updated by the report.
+func (c *Correlator) Correlate(r rpt.Report) (*report.EventMsg, error) {
+	lat, lon, err := rpt.LatLon(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to correlate report: %w", err)
+	}
+	m := member{time: r.GetTime(), lat: lat, lon: lon}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := c.closest(rpt.Kind(r), m)
+	if e == nil {
+		e = &event{msg: &report.EventMsg{
+			EventID:   eventID(r),
+			Type:      rpt.Kind(r),
+			FirstTime: m.time,
+			LastTime:  m.time,
+			MinLat:    lat,
+			MinLon:    lon,
+			MaxLat:    lat,
+			MaxLon:    lon,
+		}}
+		c.events[e.msg.EventID] = e
+	}
+	e.add(r, m)
+
+	switch msg := r.(type) {
+	case *report.HailMsg:
+		msg.EventId = e.msg.EventID
+	case *report.WindMsg:
+		msg.EventId = e.msg.EventID
+	case *report.TornadoMsg:
+		msg.EventId = e.msg.EventID
+	case *report.LSRMsg:
+		msg.EventId = e.msg.EventID
+	}
+
+	c.maxTime = max(c.maxTime, m.time)
+	c.expire()
+
+	return proto.Clone(e.msg).(*report.EventMsg), nil
+}
+
+// Open returns the number of events that can still gain members.
+func (c *Correlator) Open() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.events)
+}
+
+func (c *Correlator) closest(rtype string, m member) *event {
+	var best *event
+	bestDistance := c.maxDistance
+	for _, e := range c.events {
+		if e.msg.Type != rtype {
+			continue
+		}
+		for _, o := range e.members {
+			if abs(o.time-m.time) > c.maxGap {
+				continue
+			}
+			if d := geo.DistanceMiles(o.lat, o.lon, m.lat, m.lon); d <= bestDistance {
+				best, bestDistance = e, d
+			}
+		}
+	}
+	return best
+}
+
+func (c *Correlator) expire() {
+	for id, e := range c.events {
+		if e.msg.LastTime+c.maxGap < c.maxTime {
+			delete(c.events, id)
+		}
+	}
+}
+
+func (e *event) add(r rpt.Report, m member) {
+	e.members = append(e.members, m)
+	e.sumLat += m.lat
+	e.sumLon += m.lon
+
+	msg := e.msg
+	msg.Count++
+	msg.MaxMagnitude = max(msg.MaxMagnitude, rpt.Magnitude(r))
+	msg.FirstTime = min(msg.FirstTime, m.time)
+	msg.LastTime = max(msg.LastTime, m.time)
+	msg.MinLat = min(msg.MinLat, m.lat)
+	msg.MinLon = min(msg.MinLon, m.lon)
+	msg.MaxLat = max(msg.MaxLat, m.lat)
+	msg.MaxLon = max(msg.MaxLon, m.lon)
+	msg.CentroidLat = e.sumLat / float64(len(e.members))
+	msg.CentroidLon = e.sumLon / float64(len(e.members))
+	msg.States = addUnique(msg.States, strings.ToUpper(strings.TrimSpace(r.GetState())))
+	msg.Counties = addUnique(msg.Counties, strings.TrimSpace(r.GetCounty()))
+}
+
+// eventID is derived from the first report of an event so replaying the same
+// reports produces the same IDs.
+func eventID(r rpt.Report) string {
+	h := fnv.New64a()
+	kind := rpt.Kind(r)
+	fmt.Fprintf(h, "%s|%d|%s|%s", kind, r.GetTime(), r.GetLat(), r.GetLon())
+	return fmt.Sprintf("%s-%016x", strings.ReplaceAll(strings.ToLower(kind), " ", "-"), h.Sum64())
+}
+
+func addUnique(list []string, s string) []string {
+	if s == "" || slices.Contains(list, s) {
+		return list
+	}
+	list = append(list, s)
+	slices.Sort(list)
+	return list
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,101 @@
+package correlate
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	report "github.com/stormsync/transformer/proto"
+)
+
+const base = int64(1715968800)
+
+func TestCorrelator_Correlate(t *testing.T) {
+	c, err := NewCorrelator(5, 20*time.Minute)
+	require.NoError(t, err)
+
+	first := &report.HailMsg{Type: "Hail", Time: base, Size: 100, State: "TX", County: "Hood", Lat: "32.36", Lon: "-97.66"}
+	ev, err := c.Correlate(first)
+	require.NoError(t, err)
+	assert.NotEmpty(t, first.GetEventId())
+	assert.Equal(t, first.GetEventId(), ev.GetEventID())
+	assert.Equal(t, int32(1), ev.GetCount())
+
+	// about a mile away and ten minutes later joins the same event
+	second := &report.HailMsg{Type: "Hail", Time: base + 600, Size: 175, State: "TX", County: "Johnson", Lat: "32.38", Lon: "-97.64"}
+	ev, err = c.Correlate(second)
+	require.NoError(t, err)
+	assert.Equal(t, first.GetEventId(), second.GetEventId())
+	assert.Equal(t, int32(2), ev.GetCount())
+	assert.Equal(t, int32(175), ev.GetMaxMagnitude())
+	assert.Equal(t, base, ev.GetFirstTime())
+	assert.Equal(t, base+600, ev.GetLastTime())
+	assert.Equal(t, []string{"Hood", "Johnson"}, ev.GetCounties())
+	assert.Equal(t, []string{"TX"}, ev.GetStates())
+	assert.InDelta(t, 32.37, ev.GetCentroidLat(), 0.001)
+
+	// a wind report at the same spot is a different event
+	wind := &report.WindMsg{Type: "Wind", Time: base + 600, State: "TX", Lat: "32.37", Lon: "-97.65"}
+	_, err = c.Correlate(wind)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.GetEventId(), wind.GetEventId())
+
+	// too far away starts a new event
+	far := &report.HailMsg{Type: "Hail", Time: base + 700, State: "TX", Lat: "32.75", Lon: "-97.33"}
+	_, err = c.Correlate(far)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.GetEventId(), far.GetEventId())
+
+	// too long after the last member starts a new event
+	later := &report.HailMsg{Type: "Hail", Time: base + 600 + 1800, State: "TX", Lat: "32.38", Lon: "-97.64"}
+	_, err = c.Correlate(later)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.GetEventId(), later.GetEventId())
+
+	// only the latest event is recent enough to gain members
+	assert.Equal(t, 1, c.Open())
+}
+
+func TestCorrelator_EventIDIsStable(t *testing.T) {
+	a, err := NewCorrelator(5, 20*time.Minute)
+	require.NoError(t, err)
+	b, err := NewCorrelator(5, 20*time.Minute)
+	require.NoError(t, err)
+
+	ra := &report.TornadoMsg{Type: "Tornado", Time: base, Lat: "30.35", Lon: "-83.83"}
+	rb := &report.TornadoMsg{Type: "Tornado", Time: base, Lat: "30.35", Lon: "-83.83"}
+	_, err = a.Correlate(ra)
+	require.NoError(t, err)
+	_, err = b.Correlate(rb)
+	require.NoError(t, err)
+	assert.Equal(t, ra.GetEventId(), rb.GetEventId())
+}
+
+func TestCorrelator_RequiresCoordinates(t *testing.T) {
+	c, err := NewCorrelator(5, 20*time.Minute)
+	require.NoError(t, err)
+
+	_, err = c.Correlate(&report.HailMsg{Type: "Hail", Time: base, Lat: "UNK"})
+	assert.Error(t, err)
+}
+
+func TestCorrelator_LSREventTypes(t *testing.T) {
+	c, err := NewCorrelator(10, 30*time.Minute)
+	require.NoError(t, err)
+
+	flood := &report.LSRMsg{Type: "LSR", EventType: "Flood", Time: base, State: "OK", Lat: "35.35", Lon: "-97.48"}
+	snow := &report.LSRMsg{Type: "LSR", EventType: "Snow", Time: base + 300, State: "OK", Lat: "35.36", Lon: "-97.47"}
+	floodEvent, err := c.Correlate(flood)
+	require.NoError(t, err)
+	snowEvent, err := c.Correlate(snow)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, flood.GetEventId(), snow.GetEventId(), "floods and snow side by side are separate events")
+	assert.Equal(t, "Flood", floodEvent.GetType())
+	assert.Equal(t, "Snow", snowEvent.GetType())
+	assert.True(t, strings.HasPrefix(flood.GetEventId(), "flood-"), flood.GetEventId())
+	assert.Equal(t, int32(1), snowEvent.GetCount())
+}
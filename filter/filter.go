@@ -0,0 +1,340 @@
+// Package filter is used to drop reports that fall outside of an area of interest
+// or below configured thresholds before they are sent to the transformed topic.
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/stormsync/collector"
+	"gopkg.in/yaml.v3"
+
+	"github.com/stormsync/transformer/report"
+)
+
+// Op is the comparison a rule applies to a report field.
+type Op string
+
+const (
+	OpEq       Op = "eq"
+	OpNe       Op = "ne"
+	OpLt       Op = "lt"
+	OpLte      Op = "lte"
+	OpGt       Op = "gt"
+	OpGte      Op = "gte"
+	OpIn       Op = "in"
+	OpNotIn    Op = "not-in"
+	OpContains Op = "contains"
+	OpInside   Op = "inside"
+	OpOutside  Op = "outside"
+)
+
+// Fields that can be referenced by a rule.
+const (
+	FieldTime      = "time"
+	FieldMagnitude = "magnitude"
+	FieldDistance  = "distance"
+	FieldDirection = "direction"
+	FieldLocation  = "location"
+	FieldCounty    = "county"
+	FieldState     = "state"
+	FieldLat       = "lat"
+	FieldLon       = "lon"
+	FieldRemarks   = "remarks"
+	FieldType      = "type"
+)
+
+var numericFields = map[string]struct{}{
+	FieldTime:      {},
+	FieldMagnitude: {},
+	FieldDistance:  {},
+	FieldLat:       {},
+	FieldLon:       {},
+}
+
+var stringFields = map[string]struct{}{
+	FieldDirection: {},
+	FieldLocation:  {},
+	FieldCounty:    {},
+	FieldState:     {},
+	FieldRemarks:   {},
+	FieldType:      {},
+}
+
+// BoundingBox is a lat/lon rectangle used by the inside and outside operators.
+type BoundingBox struct {
+	MinLat float64 `yaml:"min-lat"`
+	MinLon float64 `yaml:"min-lon"`
+	MaxLat float64 `yaml:"max-lat"`
+	MaxLon float64 `yaml:"max-lon"`
+}
+
+// Contains reports whether the point lies within the box, edges included.
+func (b BoundingBox) Contains(lat, lon float64) bool {
+	return lat >= b.MinLat && lat <= b.MaxLat && lon >= b.MinLon && lon <= b.MaxLon
+}
+
+// Rule describes a condition that, when matched, causes a report to be dropped.
+// A rule limited by Types only applies to those report types; an empty list
+// applies the rule to every report.
+//
+// Magnitudes are compared in the units of the source column: hail size in hundredths
+// of an inch (1.00" is 100), wind speed in knots, and the tornado F/EF scale.
+// Hail and wind reports with an UNK magnitude, and reports without a magnitude
+// column, never match a magnitude rule. An UNK tornado cannot be told apart
+// from an F0/EF0 one and is compared as zero.
+type Rule struct {
+	Name   string       `yaml:"name"`
+	Types  []string     `yaml:"types"`
+	Field  string       `yaml:"field"`
+	Op     Op           `yaml:"op"`
+	Value  string       `yaml:"value"`
+	Values []string     `yaml:"values"`
+	BBox   *BoundingBox `yaml:"bbox"`
+}
+
+// rule is the validated form of a Rule with its value already parsed.
+type rule struct {
+	Rule
+	number float64
+}
+
+// Filter evaluates a set of rules against reports and keeps track of how many
+// reports each rule has dropped.
+type Filter struct {
+	rules []rule
+
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+// NewFilter validates the rules and returns a Filter that evaluates them in order.
+// All problems found with the rules are returned together.
+func NewFilter(rules []Rule) (*Filter, error) {
+	f := &Filter{counts: make(map[string]int64, len(rules))}
+	seen := make(map[string]struct{}, len(rules))
+
+	var errs []error
+	for i, r := range rules {
+		if r.Name == "" {
+			errs = append(errs, fmt.Errorf("rule %d: name cannot be empty", i))
+		} else if _, ok := seen[r.Name]; ok {
+			errs = append(errs, fmt.Errorf("rule %d: duplicate name %q", i, r.Name))
+		}
+		seen[r.Name] = struct{}{}
+
+		compiled, err := compileRule(r)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rule %q: %w", r.Name, err))
+			continue
+		}
+		f.rules = append(f.rules, compiled)
+		f.counts[r.Name] = 0
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// LoadRules decodes a YAML list of rules.
+func LoadRules(r io.Reader) ([]Rule, error) {
+	var rules []Rule
+	if err := yaml.NewDecoder(r).Decode(&rules); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("unable to decode filter rules: %w", err)
+	}
+	return rules, nil
+}
+
+func compileRule(r Rule) (rule, error) {
+	c := rule{Rule: r}
+	for _, t := range r.Types {
+		if _, err := collector.FromString(t); err != nil {
+			return c, fmt.Errorf("unknown report type %q", t)
+		}
+	}
+
+	_, numeric := numericFields[r.Field]
+	_, str := stringFields[r.Field]
+
+	switch r.Op {
+	case OpInside, OpOutside:
+		if r.BBox == nil {
+			return c, fmt.Errorf("op %q requires a bbox", r.Op)
+		}
+		if r.BBox.MinLat > r.BBox.MaxLat || r.BBox.MinLon > r.BBox.MaxLon {
+			return c, errors.New("bbox min values must not exceed max values")
+		}
+		return c, nil
+	case OpEq, OpNe, OpLt, OpLte, OpGt, OpGte:
+		if !numeric && !str {
+			return c, fmt.Errorf("unknown field %q", r.Field)
+		}
+		if !numeric {
+			if r.Op != OpEq && r.Op != OpNe {
+				return c, fmt.Errorf("op %q requires a numeric field, %q is not", r.Op, r.Field)
+			}
+			return c, nil
+		}
+		n, err := strconv.ParseFloat(r.Value, 64)
+		if err != nil {
+			return c, fmt.Errorf("value %q is not a number", r.Value)
+		}
+		c.number = n
+		return c, nil
+	case OpIn, OpNotIn, OpContains:
+		if !str {
+			return c, fmt.Errorf("op %q requires a text field, %q is not", r.Op, r.Field)
+		}
+		if r.Op == OpContains && r.Value == "" {
+			return c, errors.New("op contains requires a value")
+		}
+		if r.Op != OpContains && len(r.Values) == 0 {
+			return c, fmt.Errorf("op %q requires values", r.Op)
+		}
+		return c, nil
+	default:
+		return c, fmt.Errorf("unknown op %q", r.Op)
+	}
+}
+
+// Match returns the name of the first rule that matches the report.
+// The bool is false when no rule matched and the report should be kept.
+func (f *Filter) Match(r report.Report) (string, bool) {
+	for _, rl := range f.rules {
+		if rl.matches(r) {
+			return rl.Name, true
+		}
+	}
+	return "", false
+}
+
+// Drop reports whether the report should be dropped, counting the drop against
+// the rule that matched. The name of that rule is returned.
+func (f *Filter) Drop(r report.Report) (string, bool) {
+	name, ok := f.Match(r)
+	if !ok {
+		return "", false
+	}
+	f.mu.Lock()
+	f.counts[name]++
+	f.mu.Unlock()
+	return name, true
+}
+
+// Counts returns a copy of the number of reports dropped by each rule.
+func (f *Filter) Counts() map[string]int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	counts := make(map[string]int64, len(f.counts))
+	for k, v := range f.counts {
+		counts[k] = v
+	}
+	return counts
+}
+
+func (r rule) matches(rpt report.Report) bool {
+	if len(r.Types) > 0 && !slices.ContainsFunc(r.Types, func(t string) bool { return strings.EqualFold(t, rpt.GetType()) }) {
+		return false
+	}
+
+	switch r.Op {
+	case OpInside, OpOutside:
+		lat, lon, err := report.LatLon(rpt)
+		// a report without usable coordinates is never inside the box
+		inside := err == nil && r.BBox.Contains(lat, lon)
+		return inside == (r.Op == OpInside)
+	}
+
+	if _, ok := numericFields[r.Field]; ok {
+		n, ok := numberField(rpt, r.Field)
+		if !ok {
+			return false
+		}
+		switch r.Op {
+		case OpEq:
+			return n == r.number
+		case OpNe:
+			return n != r.number
+		case OpLt:
+			return n < r.number
+		case OpLte:
+			return n <= r.number
+		case OpGt:
+			return n > r.number
+		case OpGte:
+			return n >= r.number
+		}
+		return false
+	}
+
+	s := stringField(rpt, r.Field)
+	switch r.Op {
+	case OpEq:
+		return strings.EqualFold(s, r.Value)
+	case OpNe:
+		return !strings.EqualFold(s, r.Value)
+	case OpContains:
+		return strings.Contains(strings.ToLower(s), strings.ToLower(r.Value))
+	case OpIn:
+		return containsFold(r.Values, s)
+	case OpNotIn:
+		return !containsFold(r.Values, s)
+	}
+	return false
+}
+
+func containsFold(values []string, s string) bool {
+	s = strings.TrimSpace(s)
+	return slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, s) })
+}
+
+func numberField(r report.Report, field string) (float64, bool) {
+	switch field {
+	case FieldTime:
+		return float64(r.GetTime()), true
+	case FieldMagnitude:
+		m := report.Magnitude(r)
+		// hail and wind are never zero, so a zero there was UNK and the report
+		// is not compared, or lt and lte rules would drop every unknown one
+		if m == 0 && r.GetType() != collector.Tornado.String() {
+			return 0, false
+		}
+		return float64(m), true
+	case FieldDistance:
+		return float64(r.GetDistance()), true
+	case FieldLat, FieldLon:
+		lat, lon, err := report.LatLon(r)
+		if err != nil {
+			return 0, false
+		}
+		if field == FieldLat {
+			return lat, true
+		}
+		return lon, true
+	}
+	return 0, false
+}
+
+func stringField(r report.Report, field string) string {
+	switch field {
+	case FieldDirection:
+		return r.GetDirection()
+	case FieldLocation:
+		return r.GetLocation()
+	case FieldCounty:
+		return r.GetCounty()
+	case FieldState:
+		return r.GetState()
+	case FieldRemarks:
+		return r.GetRemarks()
+	case FieldType:
+		return r.GetType()
+	}
+	return ""
+}
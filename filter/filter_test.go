@@ -0,0 +1,149 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	report "github.com/stormsync/transformer/proto"
+	rpt "github.com/stormsync/transformer/report"
+)
+
+func TestFilter_Drop(t *testing.T) {
+	rules := []Rule{
+		{Name: "small-hail", Types: []string{"Hail"}, Field: FieldMagnitude, Op: OpLt, Value: "100"},
+		{Name: "outside-states", Field: FieldState, Op: OpNotIn, Values: []string{"TX", "OK", "KS"}},
+		{Name: "outside-box", Op: OpOutside, BBox: &BoundingBox{MinLat: 30, MinLon: -105, MaxLat: 40, MaxLon: -94}},
+	}
+	f, err := NewFilter(rules)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		report   *report.HailMsg
+		wantRule string
+		wantDrop bool
+	}{
+		{
+			name:     "should keep large hail inside the area",
+			report:   &report.HailMsg{Type: "Hail", Size: 175, State: "TX", Lat: "32.36", Lon: "-97.66"},
+			wantDrop: false,
+		},
+		{
+			name:     "should drop hail below the size threshold",
+			report:   &report.HailMsg{Type: "Hail", Size: 75, State: "TX", Lat: "32.36", Lon: "-97.66"},
+			wantRule: "small-hail",
+			wantDrop: true,
+		},
+		{
+			name:     "should drop reports outside of the listed states",
+			report:   &report.HailMsg{Type: "Hail", Size: 200, State: "NE", Lat: "32.36", Lon: "-97.66"},
+			wantRule: "outside-states",
+			wantDrop: true,
+		},
+		{
+			name:     "should drop reports outside of the bounding box",
+			report:   &report.HailMsg{Type: "Hail", Size: 200, State: "tx", Lat: "41.21", Lon: "-96.08"},
+			wantRule: "outside-box",
+			wantDrop: true,
+		},
+		{
+			name:     "should drop reports without usable coordinates",
+			report:   &report.HailMsg{Type: "Hail", Size: 200, State: "KS", Lat: "", Lon: "-96.08"},
+			wantRule: "outside-box",
+			wantDrop: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, drop := f.Drop(tt.report)
+			assert.Equal(t, tt.wantDrop, drop)
+			assert.Equal(t, tt.wantRule, rule)
+		})
+	}
+
+	assert.Equal(t, map[string]int64{"small-hail": 1, "outside-states": 1, "outside-box": 2}, f.Counts())
+}
+
+func TestFilter_TypesLimitRule(t *testing.T) {
+	f, err := NewFilter([]Rule{{Name: "small-hail", Types: []string{"Hail"}, Field: FieldMagnitude, Op: OpLt, Value: "100"}})
+	require.NoError(t, err)
+
+	_, drop := f.Drop(&report.WindMsg{Type: "Wind", Speed: 50})
+	assert.False(t, drop)
+}
+
+func TestFilter_UnknownMagnitude(t *testing.T) {
+	f, err := NewFilter([]Rule{{Name: "weak", Field: FieldMagnitude, Op: OpLte, Value: "1"}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		report   rpt.Report
+		wantDrop bool
+	}{
+		{name: "should keep hail of unknown size", report: &report.HailMsg{Type: "Hail"}},
+		{name: "should keep wind of unknown speed", report: &report.WindMsg{Type: "Wind"}},
+		{name: "should keep LSR messages", report: &report.LSRMsg{Type: "LSR", Magnitude: 0.5}},
+		{name: "should drop F0/EF0 tornadoes", report: &report.TornadoMsg{Type: "Tornado"}, wantDrop: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, drop := f.Drop(tt.report)
+			assert.Equal(t, tt.wantDrop, drop)
+		})
+	}
+}
+
+func TestNewFilter_Validation(t *testing.T) {
+	_, err := NewFilter([]Rule{
+		{Name: "", Field: FieldState, Op: OpEq, Value: "TX"},
+		{Name: "dup", Field: FieldState, Op: OpEq, Value: "TX"},
+		{Name: "dup", Field: FieldState, Op: OpEq, Value: "OK"},
+		{Name: "bad-number", Field: FieldMagnitude, Op: OpGt, Value: "large"},
+		{Name: "bad-type", Types: []string{"Snow"}, Field: FieldState, Op: OpEq, Value: "TX"},
+		{Name: "no-bbox", Op: OpInside},
+		{Name: "bad-op", Field: FieldState, Op: "like", Value: "TX"},
+		{Name: "text-compare", Field: FieldState, Op: OpLt, Value: "TX"},
+	})
+	require.Error(t, err)
+	for _, want := range []string{
+		"rule 0: name cannot be empty",
+		`rule 2: duplicate name "dup"`,
+		`rule "bad-number": value "large" is not a number`,
+		`rule "bad-type": unknown report type "Snow"`,
+		`rule "no-bbox": op "inside" requires a bbox`,
+		`rule "bad-op": unknown op "like"`,
+		`rule "text-compare": op "lt" requires a numeric field, "state" is not`,
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	in := `
+- name: small-hail
+  types: [Hail]
+  field: magnitude
+  op: lt
+  value: "100"
+- name: area
+  op: outside
+  bbox:
+    min-lat: 30
+    min-lon: -105
+    max-lat: 40
+    max-lon: -94
+`
+	rules, err := LoadRules(strings.NewReader(in))
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{
+		{Name: "small-hail", Types: []string{"Hail"}, Field: FieldMagnitude, Op: OpLt, Value: "100"},
+		{Name: "area", Op: OpOutside, BBox: &BoundingBox{MinLat: 30, MinLon: -105, MaxLat: 40, MaxLon: -94}},
+	}, rules)
+
+	_, err = NewFilter(rules)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,16 @@
+package geo
+
+import "math"
+
+// earthRadiusMiles is the mean radius of the earth.
+const earthRadiusMiles = 3958.8
+
+// DistanceMiles returns the great circle distance between two points in statute miles.
+func DistanceMiles(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(d float64) float64 { return d * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMiles * math.Asin(math.Sqrt(a))
+}
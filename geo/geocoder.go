@@ -0,0 +1,139 @@
+// Package geo reverse geocodes report coordinates against county and NWS county
+// warning area (CWA) boundaries.
+//
+// The boundaries are GeoJSON FeatureCollections. County features carry the
+// GEOID, STATEFP and NAME properties of the Census cartographic boundary files
+// and CWA features carry the CWA property of the NWS w_* shapefiles. Both can be
+// converted with ogr2ogr, for example:
+//
+//	ogr2ogr -f GeoJSON -lco COORDINATE_PRECISION=4 -select GEOID,STATEFP,NAME data/counties.geojson cb_us_county_20m.shp
+//	ogr2ogr -f GeoJSON -lco COORDINATE_PRECISION=4 -select CWA data/cwas.geojson w_cwa.shp
+//
+// The files in the data directory are embedded into the binary and used by
+// NewEmbeddedGeocoder. The repository carries empty collections; generate the
+// files before building to ship boundaries with the binary, or load them at
+// runtime with NewGeocoder. The commands refuse to geocode with empty collections.
+package geo
+
+import (
+	"bytes"
+	"cmp"
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+
+	report "github.com/stormsync/transformer/proto"
+	rpt "github.com/stormsync/transformer/report"
+)
+
+// Property names read from the boundary features.
+const (
+	PropCountyFIPS = "GEOID"
+	PropStateFIPS  = "STATEFP"
+	PropCountyName = "NAME"
+	PropCWA        = "CWA"
+)
+
+var (
+	//go:embed data/counties.geojson
+	embeddedCounties []byte
+
+	//go:embed data/cwas.geojson
+	embeddedCWAs []byte
+)
+
+// Result is what is known about a point after reverse geocoding.
+// Fields are empty when the point is not within any boundary.
+type Result struct {
+	CountyFIPS string
+	StateFIPS  string
+	County     string
+	CWA        string
+}
+
+// Geocoder finds the county and CWA that contain a point.
+type Geocoder struct {
+	counties *Index
+	cwas     *Index
+}
+
+// NewGeocoder builds a Geocoder from county and CWA GeoJSON boundaries.
+func NewGeocoder(counties, cwas io.Reader) (*Geocoder, error) {
+	cf, err := LoadFeatures(counties)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load county boundaries: %w", err)
+	}
+	wf, err := LoadFeatures(cwas)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cwa boundaries: %w", err)
+	}
+	return &Geocoder{
+		counties: NewIndex(cf, DefaultCellSize),
+		cwas:     NewIndex(wf, DefaultCellSize),
+	}, nil
+}
+
+// NewEmbeddedGeocoder builds a Geocoder from the boundaries embedded in the binary.
+func NewEmbeddedGeocoder() (*Geocoder, error) {
+	return NewGeocoder(bytes.NewReader(embeddedCounties), bytes.NewReader(embeddedCWAs))
+}
+
+// Counts returns the number of county and CWA boundaries loaded.
+func (g *Geocoder) Counts() (int, int) {
+	return g.counties.Len(), g.cwas.Len()
+}
+
+// Lookup returns the county and CWA containing the point.
+func (g *Geocoder) Lookup(lat, lon float64) Result {
+	var res Result
+	if f, ok := g.counties.Find(lat, lon); ok {
+		res.CountyFIPS = f.Properties[PropCountyFIPS]
+		res.StateFIPS = f.Properties[PropStateFIPS]
+		res.County = f.Properties[PropCountyName]
+		if res.StateFIPS == "" && len(res.CountyFIPS) == 5 {
+			res.StateFIPS = res.CountyFIPS[:2]
+		}
+	}
+	if f, ok := g.cwas.Find(lat, lon); ok {
+		res.CWA = f.Properties[PropCWA]
+	}
+	return res
+}
+
+// Enrich reverse geocodes the report's coordinates and fills in its county_fips,
+// state_fips and cwa fields. FIPS codes the report already carries, as the
+// historical database records do, are kept when the point is in no county. The report is flagged with state_mismatch when the
+// state column does not match the state of the county containing the point.
+// The bool reports whether the state column disagreed with the geometry.
+func (g *Geocoder) Enrich(r rpt.Report) (Result, bool, error) {
+	lat, lon, err := rpt.LatLon(r)
+	if err != nil {
+		return Result{}, false, fmt.Errorf("unable to geocode report: %w", err)
+	}
+	res := g.Lookup(lat, lon)
+
+	mismatch := false
+	if res.StateFIPS != "" {
+		fips, ok := StateFIPS(r.GetState())
+		mismatch = !ok || !strings.EqualFold(fips, res.StateFIPS)
+	}
+
+	switch m := r.(type) {
+	case *report.HailMsg:
+		m.CountyFips, m.StateFips = cmp.Or(res.CountyFIPS, m.CountyFips), cmp.Or(res.StateFIPS, m.StateFips)
+		m.Cwa, m.StateMismatch = res.CWA, mismatch
+	case *report.WindMsg:
+		m.CountyFips, m.StateFips = cmp.Or(res.CountyFIPS, m.CountyFips), cmp.Or(res.StateFIPS, m.StateFips)
+		m.Cwa, m.StateMismatch = res.CWA, mismatch
+	case *report.TornadoMsg:
+		m.CountyFips, m.StateFips = cmp.Or(res.CountyFIPS, m.CountyFips), cmp.Or(res.StateFIPS, m.StateFips)
+		m.Cwa, m.StateMismatch = res.CWA, mismatch
+	case *report.LSRMsg:
+		m.CountyFips, m.StateFips = cmp.Or(res.CountyFIPS, m.CountyFips), cmp.Or(res.StateFIPS, m.StateFips)
+		m.Cwa, m.StateMismatch = res.CWA, mismatch
+	default:
+		return res, mismatch, fmt.Errorf("unable to geocode unknown report type %T", r)
+	}
+	return res, mismatch, nil
+}
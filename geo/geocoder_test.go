@@ -0,0 +1,141 @@
+package geo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	report "github.com/stormsync/transformer/proto"
+)
+
+// testCounties are two made up square counties side by side. The Tarrant square has
+// a hole cut out of it that is covered by a third county.
+const testCounties = `{
+  "type": "FeatureCollection",
+  "features": [
+    {"type": "Feature", "properties": {"GEOID": "48439", "STATEFP": "48", "NAME": "Tarrant"},
+     "geometry": {"type": "Polygon", "coordinates": [
+       [[-98, 32], [-97, 32], [-97, 33], [-98, 33], [-98, 32]],
+       [[-97.6, 32.4], [-97.4, 32.4], [-97.4, 32.6], [-97.6, 32.6], [-97.6, 32.4]]
+     ]}},
+    {"type": "Feature", "properties": {"GEOID": "48113", "STATEFP": "48", "NAME": "Dallas"},
+     "geometry": {"type": "MultiPolygon", "coordinates": [
+       [[[-97, 32], [-96, 32], [-96, 33], [-97, 33], [-97, 32]]]
+     ]}},
+    {"type": "Feature", "properties": {"GEOID": "40001", "STATEFP": "40", "NAME": "Island"},
+     "geometry": {"type": "Polygon", "coordinates": [
+       [[-97.6, 32.4], [-97.4, 32.4], [-97.4, 32.6], [-97.6, 32.6], [-97.6, 32.4]]
+     ]}}
+  ]
+}`
+
+const testCWAs = `{
+  "type": "FeatureCollection",
+  "features": [
+    {"type": "Feature", "properties": {"CWA": "FWD"},
+     "geometry": {"type": "Polygon", "coordinates": [
+       [[-99, 31], [-95, 31], [-95, 34], [-99, 34], [-99, 31]]
+     ]}}
+  ]
+}`
+
+func newTestGeocoder(t *testing.T) *Geocoder {
+	g, err := NewGeocoder(strings.NewReader(testCounties), strings.NewReader(testCWAs))
+	require.NoError(t, err)
+	return g
+}
+
+func TestGeocoder_Lookup(t *testing.T) {
+	g := newTestGeocoder(t)
+
+	tests := []struct {
+		name     string
+		lat, lon float64
+		want     Result
+	}{
+		{
+			name: "should find the county and cwa for a point",
+			lat:  32.75, lon: -97.33,
+			want: Result{CountyFIPS: "48439", StateFIPS: "48", County: "Tarrant", CWA: "FWD"},
+		},
+		{
+			name: "should find a county from a multipolygon",
+			lat:  32.78, lon: -96.8,
+			want: Result{CountyFIPS: "48113", StateFIPS: "48", County: "Dallas", CWA: "FWD"},
+		},
+		{
+			name: "should not match a county through a hole in its polygon",
+			lat:  32.5, lon: -97.5,
+			want: Result{CountyFIPS: "40001", StateFIPS: "40", County: "Island", CWA: "FWD"},
+		},
+		{
+			name: "should return an empty result outside of all boundaries",
+			lat:  0, lon: 0,
+			want: Result{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, g.Lookup(tt.lat, tt.lon))
+		})
+	}
+}
+
+func TestGeocoder_Enrich(t *testing.T) {
+	g := newTestGeocoder(t)
+
+	msg := &report.HailMsg{State: "TX", Lat: "32.36", Lon: "-97.66"}
+	_, mismatch, err := g.Enrich(msg)
+	require.NoError(t, err)
+	assert.False(t, mismatch)
+	assert.Equal(t, "48439", msg.GetCountyFips())
+	assert.Equal(t, "48", msg.GetStateFips())
+	assert.Equal(t, "FWD", msg.GetCwa())
+	assert.False(t, msg.GetStateMismatch())
+
+	wind := &report.WindMsg{State: "OK", Lat: "32.78", Lon: "-96.8"}
+	_, mismatch, err = g.Enrich(wind)
+	require.NoError(t, err)
+	assert.True(t, mismatch)
+	assert.True(t, wind.GetStateMismatch())
+
+	_, _, err = g.Enrich(&report.TornadoMsg{State: "TX", Lat: "UNK", Lon: "-96.8"})
+	assert.Error(t, err)
+
+	tornado := &report.TornadoMsg{State: "TX", Lat: "27.0", Lon: "-94.0", CountyFips: "48167", StateFips: "48"}
+	_, _, err = g.Enrich(tornado)
+	require.NoError(t, err)
+	assert.Equal(t, "48167", tornado.GetCountyFips(), "FIPS codes are kept when the point is in no county")
+}
+
+func TestLoadFeatures_Errors(t *testing.T) {
+	_, err := LoadFeatures(strings.NewReader(`{"type": "Feature"}`))
+	assert.EqualError(t, err, `expected a FeatureCollection, got "Feature"`)
+
+	_, err = LoadFeatures(strings.NewReader(`{"type": "FeatureCollection", "features": [{"geometry": {"type": "Point", "coordinates": [1, 2]}}]}`))
+	assert.EqualError(t, err, `feature 0: unsupported geometry type "Point"`)
+}
+
+func TestNewEmbeddedGeocoder(t *testing.T) {
+	_, err := NewEmbeddedGeocoder()
+	assert.NoError(t, err)
+}
+
+func TestDistanceMiles(t *testing.T) {
+	// DFW to Oklahoma City is roughly 175 miles
+	assert.InDelta(t, 175, DistanceMiles(32.90, -97.04, 35.39, -97.60), 5)
+	assert.Zero(t, DistanceMiles(32.36, -97.66, 32.36, -97.66))
+}
+
+func TestStateBounds(t *testing.T) {
+	b, ok := StateBounds("ok")
+	require.True(t, ok)
+	assert.True(t, b.Contains(35.26, -97.44))
+	assert.False(t, b.Contains(37.5, -97.44))
+	assert.True(t, b.Expand(50).Contains(37.5, -97.44))
+
+	_, ok = StateBounds("XX")
+	assert.False(t, ok)
+}
@@ -0,0 +1,166 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Bounds is the lat/lon rectangle that encloses a feature.
+type Bounds struct {
+	MinLat, MinLon, MaxLat, MaxLon float64
+}
+
+func emptyBounds() Bounds {
+	return Bounds{MinLat: math.Inf(1), MinLon: math.Inf(1), MaxLat: math.Inf(-1), MaxLon: math.Inf(-1)}
+}
+
+// Contains reports whether the point lies within the bounds, edges included.
+func (b Bounds) Contains(lat, lon float64) bool {
+	return lat >= b.MinLat && lat <= b.MaxLat && lon >= b.MinLon && lon <= b.MaxLon
+}
+
+func (b *Bounds) extend(lat, lon float64) {
+	b.MinLat = math.Min(b.MinLat, lat)
+	b.MinLon = math.Min(b.MinLon, lon)
+	b.MaxLat = math.Max(b.MaxLat, lat)
+	b.MaxLon = math.Max(b.MaxLon, lon)
+}
+
+// ring is a closed line of [lon, lat] positions as found in GeoJSON.
+type ring [][2]float64
+
+// polygon is an outer ring followed by any holes.
+type polygon []ring
+
+// Feature is a boundary, such as a county or forecast office area, and the
+// properties that describe it.
+type Feature struct {
+	Properties map[string]string
+	Bounds     Bounds
+	polygons   []polygon
+}
+
+// Contains reports whether the point lies within the feature.
+func (f *Feature) Contains(lat, lon float64) bool {
+	if !f.Bounds.Contains(lat, lon) {
+		return false
+	}
+	for _, p := range f.polygons {
+		if p.contains(lat, lon) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p polygon) contains(lat, lon float64) bool {
+	if len(p) == 0 || !p[0].contains(lat, lon) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if hole.contains(lat, lon) {
+			return false
+		}
+	}
+	return true
+}
+
+// contains uses ray casting to determine if the point is inside the ring.
+func (r ring) contains(lat, lon float64) bool {
+	inside := false
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		xi, yi := r[i][0], r[i][1]
+		xj, yj := r[j][0], r[j][1]
+		if (yi > lat) != (yj > lat) && lon < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+type featureCollection struct {
+	Type     string `json:"type"`
+	Features []struct {
+		Properties map[string]any `json:"properties"`
+		Geometry   struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+	} `json:"features"`
+}
+
+// LoadFeatures decodes a GeoJSON FeatureCollection of Polygon and MultiPolygon
+// features. Property values are converted to strings.
+func LoadFeatures(r io.Reader) ([]*Feature, error) {
+	var fc featureCollection
+	if err := json.NewDecoder(r).Decode(&fc); err != nil {
+		return nil, fmt.Errorf("unable to decode geojson: %w", err)
+	}
+	if fc.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("expected a FeatureCollection, got %q", fc.Type)
+	}
+
+	features := make([]*Feature, 0, len(fc.Features))
+	for i, f := range fc.Features {
+		var polygons []polygon
+		switch f.Geometry.Type {
+		case "Polygon":
+			var p polygon
+			if err := json.Unmarshal(f.Geometry.Coordinates, &p); err != nil {
+				return nil, fmt.Errorf("feature %d: invalid polygon: %w", i, err)
+			}
+			polygons = []polygon{p}
+		case "MultiPolygon":
+			if err := json.Unmarshal(f.Geometry.Coordinates, &polygons); err != nil {
+				return nil, fmt.Errorf("feature %d: invalid multipolygon: %w", i, err)
+			}
+		default:
+			return nil, fmt.Errorf("feature %d: unsupported geometry type %q", i, f.Geometry.Type)
+		}
+
+		feature := &Feature{
+			Properties: make(map[string]string, len(f.Properties)),
+			Bounds:     emptyBounds(),
+			polygons:   polygons,
+		}
+		for k, v := range f.Properties {
+			if v != nil {
+				feature.Properties[k] = fmt.Sprint(v)
+			}
+		}
+		for _, p := range polygons {
+			if len(p) == 0 {
+				continue
+			}
+			for _, pos := range p[0] {
+				feature.Bounds.extend(pos[1], pos[0])
+			}
+		}
+		if math.IsInf(feature.Bounds.MinLat, 1) {
+			return nil, fmt.Errorf("feature %d: geometry has no positions", i)
+		}
+		features = append(features, feature)
+	}
+	return features, nil
+}
+
+// NewPolygonFeature returns a feature for a single polygon without holes given as
+// [lat, lon] vertices. The ring is closed automatically.
+func NewPolygonFeature(vertices [][2]float64, props map[string]string) *Feature {
+	r := make(ring, 0, len(vertices)+1)
+	b := emptyBounds()
+	for _, v := range vertices {
+		r = append(r, [2]float64{v[1], v[0]})
+		b.extend(v[0], v[1])
+	}
+	if len(r) > 0 && r[0] != r[len(r)-1] {
+		r = append(r, r[0])
+	}
+	return &Feature{
+		Properties: props,
+		Bounds:     b,
+		polygons:   []polygon{{r}},
+	}
+}
@@ -0,0 +1,63 @@
+package geo
+
+import "math"
+
+// DefaultCellSize is the size, in degrees, of the grid cells used by an Index.
+const DefaultCellSize = 1.0
+
+type cell struct {
+	x, y int
+}
+
+// Index is a uniform grid spatial index over features. Each feature is registered
+// in every cell its bounds overlap so a lookup only tests the few features whose
+// bounds could contain the point.
+type Index struct {
+	cellSize float64
+	features []*Feature
+	cells    map[cell][]int
+}
+
+// NewIndex builds an index over the features using cells of cellSize degrees.
+// A cellSize that is not positive uses DefaultCellSize.
+func NewIndex(features []*Feature, cellSize float64) *Index {
+	if cellSize <= 0 {
+		cellSize = DefaultCellSize
+	}
+	idx := &Index{
+		cellSize: cellSize,
+		features: features,
+		cells:    make(map[cell][]int),
+	}
+	for i, f := range features {
+		minX, minY := idx.cellFor(f.Bounds.MinLat, f.Bounds.MinLon)
+		maxX, maxY := idx.cellFor(f.Bounds.MaxLat, f.Bounds.MaxLon)
+		for x := minX; x <= maxX; x++ {
+			for y := minY; y <= maxY; y++ {
+				c := cell{x: x, y: y}
+				idx.cells[c] = append(idx.cells[c], i)
+			}
+		}
+	}
+	return idx
+}
+
+// Len returns the number of features in the index.
+func (idx *Index) Len() int {
+	return len(idx.features)
+}
+
+// Find returns the first feature that contains the point.
+func (idx *Index) Find(lat, lon float64) (*Feature, bool) {
+	x, y := idx.cellFor(lat, lon)
+	for _, i := range idx.cells[cell{x: x, y: y}] {
+		if idx.features[i].Contains(lat, lon) {
+			return idx.features[i], true
+		}
+	}
+	return nil, false
+}
+
+func (idx *Index) cellFor(lat, lon float64) (int, int) {
+	return int(math.Floor(lon / idx.cellSize)), int(math.Floor(lat / idx.cellSize))
+}
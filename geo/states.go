@@ -0,0 +1,156 @@
+package geo
+
+import (
+	"math"
+	"strings"
+)
+
+// stateFIPS maps USPS state and territory codes to their two digit FIPS codes.
+var stateFIPS = map[string]string{
+	"AL": "01",
+	"AK": "02",
+	"AZ": "04",
+	"AR": "05",
+	"CA": "06",
+	"CO": "08",
+	"CT": "09",
+	"DE": "10",
+	"DC": "11",
+	"FL": "12",
+	"GA": "13",
+	"HI": "15",
+	"ID": "16",
+	"IL": "17",
+	"IN": "18",
+	"IA": "19",
+	"KS": "20",
+	"KY": "21",
+	"LA": "22",
+	"ME": "23",
+	"MD": "24",
+	"MA": "25",
+	"MI": "26",
+	"MN": "27",
+	"MS": "28",
+	"MO": "29",
+	"MT": "30",
+	"NE": "31",
+	"NV": "32",
+	"NH": "33",
+	"NJ": "34",
+	"NM": "35",
+	"NY": "36",
+	"NC": "37",
+	"ND": "38",
+	"OH": "39",
+	"OK": "40",
+	"OR": "41",
+	"PA": "42",
+	"RI": "44",
+	"SC": "45",
+	"SD": "46",
+	"TN": "47",
+	"TX": "48",
+	"UT": "49",
+	"VT": "50",
+	"VA": "51",
+	"WA": "53",
+	"WV": "54",
+	"WI": "55",
+	"WY": "56",
+	"AS": "60",
+	"GU": "66",
+	"MP": "69",
+	"PR": "72",
+	"VI": "78",
+}
+
+// StateFIPS returns the FIPS code for a USPS state code such as TX.
+// The bool is false when the code is not a known state or territory.
+func StateFIPS(usps string) (string, bool) {
+	fips, ok := stateFIPS[strings.ToUpper(strings.TrimSpace(usps))]
+	return fips, ok
+}
+
+// stateBounds are the approximate bounding boxes of each state and territory.
+// They are meant for catching coordinates that are far from the listed state,
+// not for deciding which side of a border a point is on.
+var stateBounds = map[string]Bounds{
+	"AL": {MinLat: 30.14, MinLon: -88.48, MaxLat: 35.01, MaxLon: -84.89},
+	"AK": {MinLat: 51.21, MinLon: -179.99, MaxLat: 71.44, MaxLon: -129.98},
+	"AZ": {MinLat: 31.33, MinLon: -114.82, MaxLat: 37.00, MaxLon: -109.04},
+	"AR": {MinLat: 33.00, MinLon: -94.62, MaxLat: 36.50, MaxLon: -89.64},
+	"CA": {MinLat: 32.53, MinLon: -124.41, MaxLat: 42.01, MaxLon: -114.13},
+	"CO": {MinLat: 36.99, MinLon: -109.06, MaxLat: 41.00, MaxLon: -102.04},
+	"CT": {MinLat: 40.95, MinLon: -73.73, MaxLat: 42.05, MaxLon: -71.79},
+	"DE": {MinLat: 38.45, MinLon: -75.79, MaxLat: 39.84, MaxLon: -75.05},
+	"DC": {MinLat: 38.79, MinLon: -77.12, MaxLat: 38.99, MaxLon: -76.91},
+	"FL": {MinLat: 24.40, MinLon: -87.63, MaxLat: 31.00, MaxLon: -79.97},
+	"GA": {MinLat: 30.36, MinLon: -85.61, MaxLat: 35.00, MaxLon: -80.84},
+	"HI": {MinLat: 18.91, MinLon: -160.25, MaxLat: 22.24, MaxLon: -154.81},
+	"ID": {MinLat: 41.99, MinLon: -117.24, MaxLat: 49.00, MaxLon: -111.04},
+	"IL": {MinLat: 36.97, MinLon: -91.51, MaxLat: 42.51, MaxLon: -87.02},
+	"IN": {MinLat: 37.77, MinLon: -88.10, MaxLat: 41.76, MaxLon: -84.78},
+	"IA": {MinLat: 40.38, MinLon: -96.64, MaxLat: 43.50, MaxLon: -90.14},
+	"KS": {MinLat: 36.99, MinLon: -102.05, MaxLat: 40.00, MaxLon: -94.59},
+	"KY": {MinLat: 36.50, MinLon: -89.57, MaxLat: 39.15, MaxLon: -81.96},
+	"LA": {MinLat: 28.93, MinLon: -94.04, MaxLat: 33.02, MaxLon: -88.82},
+	"ME": {MinLat: 43.06, MinLon: -71.08, MaxLat: 47.46, MaxLon: -66.95},
+	"MD": {MinLat: 37.91, MinLon: -79.49, MaxLat: 39.72, MaxLon: -75.05},
+	"MA": {MinLat: 41.24, MinLon: -73.51, MaxLat: 42.89, MaxLon: -69.93},
+	"MI": {MinLat: 41.70, MinLon: -90.42, MaxLat: 48.31, MaxLon: -82.41},
+	"MN": {MinLat: 43.50, MinLon: -97.24, MaxLat: 49.38, MaxLon: -89.49},
+	"MS": {MinLat: 30.17, MinLon: -91.66, MaxLat: 35.00, MaxLon: -88.10},
+	"MO": {MinLat: 35.99, MinLon: -95.77, MaxLat: 40.61, MaxLon: -89.10},
+	"MT": {MinLat: 44.36, MinLon: -116.05, MaxLat: 49.00, MaxLon: -104.04},
+	"NE": {MinLat: 40.00, MinLon: -104.05, MaxLat: 43.00, MaxLon: -95.31},
+	"NV": {MinLat: 35.00, MinLon: -120.01, MaxLat: 42.00, MaxLon: -114.04},
+	"NH": {MinLat: 42.70, MinLon: -72.56, MaxLat: 45.31, MaxLon: -70.61},
+	"NJ": {MinLat: 38.93, MinLon: -75.56, MaxLat: 41.36, MaxLon: -73.89},
+	"NM": {MinLat: 31.33, MinLon: -109.05, MaxLat: 37.00, MaxLon: -103.00},
+	"NY": {MinLat: 40.50, MinLon: -79.76, MaxLat: 45.02, MaxLon: -71.86},
+	"NC": {MinLat: 33.84, MinLon: -84.32, MaxLat: 36.59, MaxLon: -75.46},
+	"ND": {MinLat: 45.94, MinLon: -104.05, MaxLat: 49.00, MaxLon: -96.55},
+	"OH": {MinLat: 38.40, MinLon: -84.82, MaxLat: 41.98, MaxLon: -80.52},
+	"OK": {MinLat: 33.62, MinLon: -103.00, MaxLat: 37.00, MaxLon: -94.43},
+	"OR": {MinLat: 41.99, MinLon: -124.57, MaxLat: 46.29, MaxLon: -116.46},
+	"PA": {MinLat: 39.72, MinLon: -80.52, MaxLat: 42.27, MaxLon: -74.69},
+	"RI": {MinLat: 41.15, MinLon: -71.86, MaxLat: 42.02, MaxLon: -71.12},
+	"SC": {MinLat: 32.03, MinLon: -83.35, MaxLat: 35.22, MaxLon: -78.54},
+	"SD": {MinLat: 42.48, MinLon: -104.06, MaxLat: 45.95, MaxLon: -96.44},
+	"TN": {MinLat: 34.98, MinLon: -90.31, MaxLat: 36.68, MaxLon: -81.65},
+	"TX": {MinLat: 25.84, MinLon: -106.65, MaxLat: 36.50, MaxLon: -93.51},
+	"UT": {MinLat: 37.00, MinLon: -114.05, MaxLat: 42.00, MaxLon: -109.04},
+	"VT": {MinLat: 42.73, MinLon: -73.44, MaxLat: 45.02, MaxLon: -71.46},
+	"VA": {MinLat: 36.54, MinLon: -83.68, MaxLat: 39.47, MaxLon: -75.24},
+	"WA": {MinLat: 45.54, MinLon: -124.85, MaxLat: 49.00, MaxLon: -116.92},
+	"WV": {MinLat: 37.20, MinLon: -82.64, MaxLat: 40.64, MaxLon: -77.72},
+	"WI": {MinLat: 42.49, MinLon: -92.89, MaxLat: 47.31, MaxLon: -86.25},
+	"WY": {MinLat: 40.99, MinLon: -111.06, MaxLat: 45.01, MaxLon: -104.05},
+	"AS": {MinLat: -14.55, MinLon: -171.10, MaxLat: -11.04, MaxLon: -168.10},
+	"GU": {MinLat: 13.23, MinLon: 144.62, MaxLat: 13.65, MaxLon: 144.96},
+	"MP": {MinLat: 14.10, MinLon: 144.90, MaxLat: 20.60, MaxLon: 146.10},
+	"PR": {MinLat: 17.88, MinLon: -67.95, MaxLat: 18.52, MaxLon: -65.22},
+	"VI": {MinLat: 17.67, MinLon: -65.09, MaxLat: 18.42, MaxLon: -64.56},
+}
+
+// StateBounds returns the approximate bounding box of a state given its USPS code.
+func StateBounds(usps string) (Bounds, bool) {
+	b, ok := stateBounds[strings.ToUpper(strings.TrimSpace(usps))]
+	return b, ok
+}
+
+// Expand returns the bounds grown by the distance in miles on every side.
+func (b Bounds) Expand(miles float64) Bounds {
+	const milesPerDegreeLat = 69.0
+	latPad := miles / milesPerDegreeLat
+	// use the latitude closest to the equator so the padding is never too small
+	lat := math.Min(math.Abs(b.MinLat), math.Abs(b.MaxLat))
+	lonPad := miles / (milesPerDegreeLat * math.Cos(lat*math.Pi/180))
+	return Bounds{
+		MinLat: b.MinLat - latPad,
+		MinLon: b.MinLon - lonPad,
+		MaxLat: b.MaxLat + latPad,
+		MaxLon: b.MaxLon + lonPad,
+	}
+}
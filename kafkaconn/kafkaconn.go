@@ -0,0 +1,213 @@
+// Package kafkaconn holds the connection options shared by the Kafka consumer
+// and provider: the brokers to dial, the SASL mechanism and the TLS settings.
+package kafkaconn
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+// Mechanism is the SASL mechanism used to authenticate with the brokers.
+type Mechanism string
+
+const (
+	MechanismNone     Mechanism = "none"
+	MechanismPlain    Mechanism = "plain"
+	MechanismScram256 Mechanism = "scram-256"
+	MechanismScram512 Mechanism = "scram-512"
+)
+
+// TLS configures the TLS connection to the brokers. CAFile adds a private CA
+// bundle to the system roots, and CertFile and KeyFile together enable mTLS.
+type TLS struct {
+	Enabled            bool   `yaml:"enabled"`
+	CAFile             string `yaml:"ca-file"`
+	CertFile           string `yaml:"cert-file"`
+	KeyFile            string `yaml:"key-file"`
+	ServerName         string `yaml:"server-name"`
+	InsecureSkipVerify bool   `yaml:"insecure-skip-verify"`
+}
+
+// Credentials returns the current SASL user and password.
+type Credentials func(ctx context.Context) (user, password string, err error)
+
+// Options are the connection settings for a Kafka cluster.
+//
+// When Credentials is set it is used instead of User and Password, and is called
+// for every new connection so rotated credentials are picked up without a restart.
+type Options struct {
+	Brokers     []string    `yaml:"brokers"`
+	Mechanism   Mechanism   `yaml:"sasl-mechanism"`
+	User        string      `yaml:"user"`
+	Password    string      `yaml:"password"`
+	TLS         TLS         `yaml:"tls"`
+	Credentials Credentials `yaml:"-"`
+}
+
+// Validate returns every problem found with the options.
+func (o Options) Validate() error {
+	var errs []error
+	if len(o.Brokers) == 0 || slices.Contains(o.Brokers, "") {
+		errs = append(errs, errors.New("at least one broker is required and brokers cannot be empty"))
+	}
+	switch o.Mechanism {
+	case "", MechanismNone:
+	case MechanismPlain, MechanismScram256, MechanismScram512:
+		if o.Credentials == nil && (o.User == "" || o.Password == "") {
+			errs = append(errs, fmt.Errorf("sasl mechanism %q requires a user and password", o.Mechanism))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown sasl mechanism %q", o.Mechanism))
+	}
+	if !o.TLS.Enabled && (o.TLS.CAFile != "" || o.TLS.CertFile != "" || o.TLS.KeyFile != "" || o.TLS.ServerName != "" || o.TLS.InsecureSkipVerify) {
+		errs = append(errs, errors.New("tls settings are given but tls is not enabled"))
+	}
+	if (o.TLS.CertFile == "") != (o.TLS.KeyFile == "") {
+		errs = append(errs, errors.New("tls cert and key files must be given together"))
+	}
+	return errors.Join(errs...)
+}
+
+// SASL returns the SASL mechanism for the options, or nil when authentication is disabled.
+func (o Options) SASL() (sasl.Mechanism, error) {
+	switch o.Mechanism {
+	case "", MechanismNone:
+		return nil, nil
+	case MechanismPlain, MechanismScram256, MechanismScram512:
+	default:
+		return nil, fmt.Errorf("unknown sasl mechanism %q", o.Mechanism)
+	}
+	if o.Credentials != nil {
+		return rotatingMechanism{mechanism: o.Mechanism, credentials: o.Credentials}, nil
+	}
+	return newMechanism(o.Mechanism, o.User, o.Password)
+}
+
+// saslNames are the names the brokers know each mechanism by.
+var saslNames = map[Mechanism]string{
+	MechanismPlain:    "PLAIN",
+	MechanismScram256: "SCRAM-SHA-256",
+	MechanismScram512: "SCRAM-SHA-512",
+}
+
+func newMechanism(mechanism Mechanism, user, password string) (sasl.Mechanism, error) {
+	if mechanism == MechanismPlain {
+		return plain.Mechanism{Username: user, Password: password}, nil
+	}
+	algo := scram.SHA256
+	if mechanism == MechanismScram512 {
+		algo = scram.SHA512
+	}
+	m, err := scram.Mechanism(algo, user, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scram.Mechanism for auth: %w", err)
+	}
+	return m, nil
+}
+
+// rotatingMechanism looks up the credentials each time a connection authenticates.
+type rotatingMechanism struct {
+	mechanism   Mechanism
+	credentials Credentials
+}
+
+func (m rotatingMechanism) Name() string {
+	return saslNames[m.mechanism]
+}
+
+func (m rotatingMechanism) Start(ctx context.Context) (sasl.StateMachine, []byte, error) {
+	user, password, err := m.credentials(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get kafka credentials: %w", err)
+	}
+	mechanism, err := newMechanism(m.mechanism, user, password)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mechanism.Start(ctx)
+}
+
+// TLSConfig returns the TLS config for the options, or nil when TLS is disabled.
+func (o Options) TLSConfig() (*tls.Config, error) {
+	if !o.TLS.Enabled {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		ServerName:         o.TLS.ServerName,
+		InsecureSkipVerify: o.TLS.InsecureSkipVerify,
+	}
+	if o.TLS.CAFile != "" {
+		pem, err := os.ReadFile(o.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in ca file %s", o.TLS.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if o.TLS.CertFile != "" || o.TLS.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.TLS.CertFile, o.TLS.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// Dialer returns a dialer for kafka.Reader that authenticates with the options.
+func (o Options) Dialer() (*kafka.Dialer, error) {
+	mechanism, tlsConfig, err := o.build()
+	if err != nil {
+		return nil, err
+	}
+	return &kafka.Dialer{
+		Timeout:       10 * time.Second,
+		DualStack:     true,
+		SASLMechanism: mechanism,
+		TLS:           tlsConfig,
+	}, nil
+}
+
+// Transport returns a transport for kafka.Writer that authenticates with the options.
+func (o Options) Transport() (*kafka.Transport, error) {
+	mechanism, tlsConfig, err := o.build()
+	if err != nil {
+		return nil, err
+	}
+	return &kafka.Transport{
+		SASL: mechanism,
+		TLS:  tlsConfig,
+	}, nil
+}
+
+func (o Options) build() (sasl.Mechanism, *tls.Config, error) {
+	if err := o.Validate(); err != nil {
+		return nil, nil, err
+	}
+	mechanism, err := o.SASL()
+	if err != nil {
+		return nil, nil, err
+	}
+	tlsConfig, err := o.TLSConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	return mechanism, tlsConfig, nil
+}
@@ -0,0 +1,176 @@
+package kafkaconn
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr []string
+	}{
+		{
+			name: "should allow a plaintext cluster without auth",
+			opts: Options{Brokers: []string{"localhost:9092"}},
+		},
+		{
+			name: "should allow scram over tls",
+			opts: Options{Brokers: []string{"a:9092", "b:9092"}, Mechanism: MechanismScram512, User: "u", Password: "p", TLS: TLS{Enabled: true}},
+		},
+		{
+			name:    "should require brokers",
+			opts:    Options{},
+			wantErr: []string{"broker"},
+		},
+		{
+			name:    "should require credentials for sasl",
+			opts:    Options{Brokers: []string{"a:9092"}, Mechanism: MechanismPlain},
+			wantErr: []string{"requires a user and password"},
+		},
+		{
+			name:    "should report every problem",
+			opts:    Options{Brokers: []string{"a:9092"}, Mechanism: "gssapi", TLS: TLS{CertFile: "client.pem"}},
+			wantErr: []string{`unknown sasl mechanism "gssapi"`, "tls is not enabled", "cert and key"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, want := range tt.wantErr {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestOptions_SASL(t *testing.T) {
+	m, err := Options{}.SASL()
+	require.NoError(t, err)
+	assert.Nil(t, m)
+
+	m, err = Options{Mechanism: MechanismPlain, User: "u", Password: "p"}.SASL()
+	require.NoError(t, err)
+	assert.Equal(t, plain.Mechanism{Username: "u", Password: "p"}, m)
+
+	m, err = Options{Mechanism: MechanismScram256, User: "u", Password: "p"}.SASL()
+	require.NoError(t, err)
+	assert.Equal(t, "SCRAM-SHA-256", m.Name())
+
+	m, err = Options{Mechanism: MechanismScram512, User: "u", Password: "p"}.SASL()
+	require.NoError(t, err)
+	assert.Equal(t, "SCRAM-SHA-512", m.Name())
+}
+
+func TestOptions_SASLCredentials(t *testing.T) {
+	passwords := []string{"old", "rotated"}
+	calls := 0
+	creds := func(context.Context) (string, string, error) {
+		p := passwords[calls]
+		calls++
+		return "u", p, nil
+	}
+	opts := Options{Brokers: []string{"localhost:9092"}, Mechanism: MechanismPlain, Credentials: creds}
+	require.NoError(t, opts.Validate(), "credentials stand in for the user and password")
+
+	m, err := opts.SASL()
+	require.NoError(t, err)
+	assert.Equal(t, "PLAIN", m.Name())
+	for _, want := range passwords {
+		_, ir, err := m.Start(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "\x00u\x00"+want, string(ir), "each connection reads the current credentials")
+	}
+
+	failing := Options{Mechanism: MechanismScram256, Credentials: func(context.Context) (string, string, error) {
+		return "", "", errors.New("vault sealed")
+	}}
+	m, err = failing.SASL()
+	require.NoError(t, err)
+	assert.Equal(t, "SCRAM-SHA-256", m.Name())
+	_, _, err = m.Start(context.Background())
+	assert.ErrorContains(t, err, "vault sealed")
+}
+
+func TestOptions_TLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCert(t, dir)
+
+	cfg, err := Options{}.TLSConfig()
+	require.NoError(t, err)
+	assert.Nil(t, cfg)
+
+	cfg, err = Options{TLS: TLS{
+		Enabled:            true,
+		CAFile:             certFile,
+		CertFile:           certFile,
+		KeyFile:            keyFile,
+		ServerName:         "kafka.internal",
+		InsecureSkipVerify: true,
+	}}.TLSConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "kafka.internal", cfg.ServerName)
+	assert.True(t, cfg.InsecureSkipVerify)
+	assert.NotNil(t, cfg.RootCAs)
+	assert.Len(t, cfg.Certificates, 1)
+
+	_, err = Options{TLS: TLS{Enabled: true, CAFile: keyFile}}.TLSConfig()
+	assert.ErrorContains(t, err, "no certificates found")
+}
+
+func TestOptions_Transport(t *testing.T) {
+	tr, err := Options{Brokers: []string{"localhost:9092"}}.Transport()
+	require.NoError(t, err)
+	assert.Nil(t, tr.SASL)
+	assert.Nil(t, tr.TLS)
+
+	_, err = Options{}.Dialer()
+	assert.Error(t, err)
+}
+
+// writeCert writes a self signed certificate and its key to dir and returns their paths.
+func writeCert(t *testing.T, dir string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kafka.internal"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
@@ -0,0 +1,315 @@
+// Package memqueue is an in-process message broker with consumer.Consumer and
+// provider.Provider implementations, for tests and local runs without Kafka.
+//
+// Topics are split into partitions holding an append-only log of messages.
+// Messages with a key always land on the same partition, and messages without
+// one are spread round-robin. Consumers in the same group share the group's
+// committed offsets, so each message is read once per group.
+package memqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/stormsync/transformer/consumer"
+	"github.com/stormsync/transformer/provider"
+)
+
+// DefaultPartitions is the number of partitions of a topic created on first use.
+const DefaultPartitions = 1
+
+// ReportTypeHeader is the header the provider writes the payload type to,
+// matching the Kafka provider.
+const ReportTypeHeader = "reportType"
+
+// Message is a message stored on a topic partition.
+type Message struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Headers   []consumer.ReaderHeader
+	Time      time.Time
+}
+
+type topic struct {
+	partitions [][]Message
+	next       int // partition for the next message without a key
+}
+
+// Broker holds the topics and the committed offsets of each consumer group.
+type Broker struct {
+	mu        sync.Mutex
+	topics    map[string]*topic
+	committed map[string]map[string][]int64 // group -> topic -> partition offsets
+	written   chan struct{}                 // closed and replaced on every write
+	now       func() time.Time
+}
+
+// NewBroker returns an empty broker.
+func NewBroker() *Broker {
+	return &Broker{
+		topics:    make(map[string]*topic),
+		committed: make(map[string]map[string][]int64),
+		written:   make(chan struct{}),
+		now:       time.Now,
+	}
+}
+
+// CreateTopic creates a topic with the number of partitions. Topics used before
+// they are created get DefaultPartitions.
+func (b *Broker) CreateTopic(name string, partitions int) error {
+	if name == "" {
+		return errors.New("topic name cannot be empty")
+	}
+	if partitions <= 0 {
+		return fmt.Errorf("topic %s must have at least one partition", name)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.topics[name]; ok {
+		return fmt.Errorf("topic %s already exists", name)
+	}
+	b.topics[name] = &topic{partitions: make([][]Message, partitions)}
+	return nil
+}
+
+// topic returns the named topic, creating it when needed. b.mu must be held.
+func (b *Broker) topic(name string) *topic {
+	t, ok := b.topics[name]
+	if !ok {
+		t = &topic{partitions: make([][]Message, DefaultPartitions)}
+		b.topics[name] = t
+	}
+	return t
+}
+
+// Publish appends a message to the topic and returns it with its partition and offset set.
+func (b *Broker) Publish(topicName string, key, value []byte, headers []consumer.ReaderHeader) Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t := b.topic(topicName)
+	p := t.next
+	if len(key) > 0 {
+		h := fnv.New32a()
+		h.Write(key)
+		p = int(h.Sum32() % uint32(len(t.partitions)))
+	} else {
+		t.next = (t.next + 1) % len(t.partitions)
+	}
+
+	msg := Message{
+		Topic:     topicName,
+		Partition: p,
+		Offset:    int64(len(t.partitions[p])),
+		Key:       slices.Clone(key),
+		Value:     slices.Clone(value),
+		Headers:   slices.Clone(headers),
+		Time:      b.now(),
+	}
+	t.partitions[p] = append(t.partitions[p], msg)
+
+	close(b.written)
+	b.written = make(chan struct{})
+	return msg
+}
+
+// Messages returns a copy of every message on the topic, ordered by partition then offset.
+func (b *Broker) Messages(topicName string) []Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, ok := b.topics[topicName]
+	if !ok {
+		return nil
+	}
+	var msgs []Message
+	for _, p := range t.partitions {
+		msgs = append(msgs, p...)
+	}
+	return msgs
+}
+
+// Committed returns the next offset the group will read from the topic partition.
+func (b *Broker) Committed(group, topicName string, partition int) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	offsets := b.committed[group][topicName]
+	if partition < 0 || partition >= len(offsets) {
+		return 0
+	}
+	return offsets[partition]
+}
+
+// Lag returns the number of messages on the topic the group has not yet read.
+func (b *Broker) Lag(group, topicName string) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, ok := b.topics[topicName]
+	if !ok {
+		return 0
+	}
+	offsets := b.committed[group][topicName]
+	var lag int64
+	for i, p := range t.partitions {
+		lag += int64(len(p))
+		if i < len(offsets) {
+			lag -= offsets[i]
+		}
+	}
+	return lag
+}
+
+// Consumer reads messages from a topic as a member of a consumer group.
+// Consumers without a group each keep their own offsets.
+type Consumer struct {
+	broker *Broker
+	Topic  string
+	group  string
+	next   int // partition to try first, so partitions are read fairly
+
+	mu      sync.Mutex
+	offsets []int64 // used when there is no group
+	closed  atomic.Bool
+}
+
+var _ consumer.Consumer = (*Consumer)(nil)
+
+// NewConsumer returns a consumer of the topic in the group.
+func NewConsumer(b *Broker, topicName, group string) *Consumer {
+	return &Consumer{broker: b, Topic: topicName, group: group}
+}
+
+// ReadMessage blocks until a message is available, returns it, and commits its
+// offset for the group. It returns an error when the context is done first.
+func (c *Consumer) ReadMessage(ctx context.Context) (consumer.ReaderResponse, error) {
+	for {
+		if c.closed.Load() {
+			return consumer.ReaderResponse{}, fmt.Errorf("failed to read message from topic %s: %w", c.Topic, consumer.ErrClosed)
+		}
+		c.mu.Lock()
+		c.broker.mu.Lock()
+		msg, hwm, ok := c.fetch()
+		written := c.broker.written
+		c.broker.mu.Unlock()
+		c.mu.Unlock()
+
+		if ok {
+			return consumer.ReaderResponse{
+				Topic:         msg.Topic,
+				Partition:     msg.Partition,
+				Offset:        msg.Offset,
+				HighWaterMark: hwm,
+				Key:           msg.Key,
+				Value:         msg.Value,
+				Headers:       msg.Headers,
+				Time:          msg.Time,
+			}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return consumer.ReaderResponse{}, fmt.Errorf("failed to read message from topic %s: %w", c.Topic, ctx.Err())
+		case <-written:
+		}
+	}
+}
+
+// Close stops the consumer. Messages read before closing stay committed.
+func (c *Consumer) Close(_ context.Context) error {
+	c.closed.Store(true)
+	return nil
+}
+
+// fetch returns the next unread message and advances the committed offset.
+// Both c.mu and the broker's mu must be held.
+func (c *Consumer) fetch() (Message, int64, bool) {
+	t := c.broker.topic(c.Topic)
+	offsets := c.committedOffsets(len(t.partitions))
+	for i := range t.partitions {
+		p := (c.next + i) % len(t.partitions)
+		if offsets[p] < int64(len(t.partitions[p])) {
+			msg := t.partitions[p][offsets[p]]
+			offsets[p]++
+			c.next = (p + 1) % len(t.partitions)
+			return msg, int64(len(t.partitions[p])), true
+		}
+	}
+	return Message{}, 0, false
+}
+
+// committedOffsets returns the offsets to read from, sized to the partitions.
+// Both c.mu and the broker's mu must be held.
+func (c *Consumer) committedOffsets(partitions int) []int64 {
+	if c.group == "" {
+		if len(c.offsets) < partitions {
+			c.offsets = append(c.offsets, make([]int64, partitions-len(c.offsets))...)
+		}
+		return c.offsets
+	}
+	topics, ok := c.broker.committed[c.group]
+	if !ok {
+		topics = make(map[string][]int64)
+		c.broker.committed[c.group] = topics
+	}
+	if len(topics[c.Topic]) < partitions {
+		topics[c.Topic] = append(topics[c.Topic], make([]int64, partitions-len(topics[c.Topic]))...)
+	}
+	return topics[c.Topic]
+}
+
+// Provider writes messages to a topic the same way the Kafka provider does,
+// with the payload type in the reportType header followed by the payload headers.
+type Provider struct {
+	broker *Broker
+	Topic  string
+	logger *slog.Logger
+	closed atomic.Bool
+}
+
+var _ provider.Provider = (*Provider)(nil)
+
+// NewProvider returns a provider that writes to the topic.
+func NewProvider(b *Broker, topicName string, logger *slog.Logger) *Provider {
+	return &Provider{broker: b, Topic: topicName, logger: logger}
+}
+
+// WriteMessage appends the payload to the topic.
+func (p *Provider) WriteMessage(_ context.Context, wp provider.WriterPayload) error {
+	if p.closed.Load() {
+		return fmt.Errorf("failed to write message to topic %s: %w", p.Topic, provider.ErrClosed)
+	}
+	if wp.Type == "" {
+		return errors.New("payload type cannot be empty")
+	}
+	if wp.Body == nil {
+		return errors.New("payload body cannot be nil")
+	}
+
+	headers := []consumer.ReaderHeader{{Key: ReportTypeHeader, Value: []byte(wp.Type)}}
+	for _, h := range wp.Headers {
+		headers = append(headers, consumer.ReaderHeader{Key: h.Key, Value: h.Value})
+	}
+	msg := p.broker.Publish(p.Topic, wp.Key, wp.Body, headers)
+	p.logger.Debug("message written", "topic", p.Topic, "partition", msg.Partition, "offset", msg.Offset, "type", wp.Type)
+	return nil
+}
+
+// Flush returns immediately since messages are on the topic once written.
+func (p *Provider) Flush(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Close stops the provider from accepting further messages.
+func (p *Provider) Close(_ context.Context) error {
+	p.closed.Store(true)
+	return nil
+}
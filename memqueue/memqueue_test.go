@@ -0,0 +1,113 @@
+package memqueue
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	slogenv "github.com/cbrewster/slog-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stormsync/transformer/consumer"
+	"github.com/stormsync/transformer/provider"
+)
+
+var logger = slog.New(slogenv.NewHandler(slog.NewTextHandler(os.Stderr, nil)))
+
+func TestProvider_WriteMessage(t *testing.T) {
+	b := NewBroker()
+	require.NoError(t, b.CreateTopic("reports", 3))
+	p := NewProvider(b, "reports", logger)
+
+	for _, key := range []string{"a", "b", "a", ""} {
+		require.NoError(t, p.WriteMessage(context.Background(), provider.WriterPayload{
+			Key:     []byte(key),
+			Body:    []byte("body"),
+			Type:    "Hail",
+			Headers: []provider.WriterHeader{{Key: "severity", Value: []byte("severe")}},
+		}))
+	}
+
+	msgs := b.Messages("reports")
+	require.Len(t, msgs, 4)
+	var keyed []Message
+	for _, m := range msgs {
+		if string(m.Key) == "a" {
+			keyed = append(keyed, m)
+		}
+	}
+	require.Len(t, keyed, 2)
+	assert.Equal(t, keyed[0].Partition, keyed[1].Partition, "messages with the same key should share a partition")
+	assert.Less(t, keyed[0].Offset, keyed[1].Offset)
+	assert.Equal(t, []consumer.ReaderHeader{
+		{Key: ReportTypeHeader, Value: []byte("Hail")},
+		{Key: "severity", Value: []byte("severe")},
+	}, msgs[0].Headers)
+
+	assert.Error(t, p.WriteMessage(context.Background(), provider.WriterPayload{Body: []byte("x")}))
+	assert.Error(t, p.WriteMessage(context.Background(), provider.WriterPayload{Type: "Hail"}))
+}
+
+func TestConsumer_Groups(t *testing.T) {
+	b := NewBroker()
+	require.NoError(t, b.CreateTopic("raw", 2))
+	for _, v := range []string{"1", "2", "3", "4"} {
+		b.Publish("raw", nil, []byte(v), nil)
+	}
+
+	ctx := context.Background()
+	first, second := NewConsumer(b, "raw", "transform"), NewConsumer(b, "raw", "transform")
+	other := NewConsumer(b, "raw", "audit")
+
+	var got []string
+	for _, c := range []*Consumer{first, second, first, second} {
+		res, err := c.ReadMessage(ctx)
+		require.NoError(t, err)
+		got = append(got, string(res.Value))
+	}
+	assert.ElementsMatch(t, []string{"1", "2", "3", "4"}, got, "consumers in a group should share the messages")
+	assert.Equal(t, int64(2), b.Committed("transform", "raw", 0))
+	assert.Equal(t, int64(2), b.Committed("transform", "raw", 1))
+	assert.Equal(t, int64(0), b.Lag("transform", "raw"))
+
+	res, err := other.ReadMessage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "1", string(res.Value), "another group should read from the start")
+	assert.Equal(t, int64(3), b.Lag("audit", "raw"))
+}
+
+func TestConsumer_ReadMessageBlocks(t *testing.T) {
+	b := NewBroker()
+	c := NewConsumer(b, "raw", "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := c.ReadMessage(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var res consumer.ReaderResponse
+	go func() {
+		defer wg.Done()
+		res, err = c.ReadMessage(context.Background())
+	}()
+	b.Publish("raw", []byte("k"), []byte("late"), nil)
+	wg.Wait()
+
+	require.NoError(t, err)
+	assert.Equal(t, "late", string(res.Value))
+	assert.Equal(t, int64(1), res.HighWaterMark)
+}
+
+func TestBroker_CreateTopic(t *testing.T) {
+	b := NewBroker()
+	assert.NoError(t, b.CreateTopic("raw", 1))
+	assert.Error(t, b.CreateTopic("raw", 1))
+	assert.Error(t, b.CreateTopic("zero", 0))
+	assert.Error(t, b.CreateTopic("", 1))
+}
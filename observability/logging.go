@@ -0,0 +1,224 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Log formats accepted by NewLogHandler.
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// Attribute keys added to log records by LogHandler.
+const (
+	TraceIDKey    = "trace_id"
+	SpanIDKey     = "span_id"
+	TopicKey      = "topic"
+	PartitionKey  = "partition"
+	OffsetKey     = "offset"
+	SuppressedKey = "suppressed"
+)
+
+// Message identifies the message being processed, so it can be logged with
+// every record written while handling it.
+type Message struct {
+	Topic     string
+	Partition int
+	Offset    int64
+}
+
+type messageKey struct{}
+
+// ContextWithMessage returns a copy of ctx carrying msg.
+func ContextWithMessage(ctx context.Context, msg Message) context.Context {
+	return context.WithValue(ctx, messageKey{}, msg)
+}
+
+// MessageFromContext returns the message carried by ctx, if any.
+func MessageFromContext(ctx context.Context) (Message, bool) {
+	msg, ok := ctx.Value(messageKey{}).(Message)
+	return msg, ok
+}
+
+// ErrorSampling limits how often an identical warning or error is logged. Within
+// each Window the first Burst identical records are written and the rest dropped;
+// the next one written after the window reports how many were dropped. Errors are
+// identical when their message and error are, warnings when their message is, as
+// they usually carry the input that caused them. Sampling is off when Burst is zero.
+type ErrorSampling struct {
+	Burst  int           `yaml:"burst"`
+	Window time.Duration `yaml:"window"`
+}
+
+// LogHandler wraps a slog.Handler, adding the trace and span ids and the message
+// being processed from the record's context, and sampling repeated warnings and
+// errors.
+// Only the ...Context logging methods pass a context through to it.
+type LogHandler struct {
+	next    slog.Handler
+	sampler *sampler
+}
+
+// LogOption customizes a LogHandler.
+type LogOption func(*LogHandler)
+
+// WithErrorSampling samples identical warnings and errors as described by
+// ErrorSampling.
+func WithErrorSampling(s ErrorSampling) LogOption {
+	return func(h *LogHandler) {
+		if s.Burst > 0 && s.Window > 0 {
+			h.sampler = newSampler(s, time.Now)
+		}
+	}
+}
+
+// NewLogHandler returns a LogHandler writing to w in the text or JSON format.
+// The options apply to the inner handler, typically to set the level.
+func NewLogHandler(w io.Writer, format string, handlerOpts *slog.HandlerOptions, opts ...LogOption) (*LogHandler, error) {
+	var next slog.Handler
+	switch format {
+	case LogFormatText, "":
+		next = slog.NewTextHandler(w, handlerOpts)
+	case LogFormatJSON:
+		next = slog.NewJSONHandler(w, handlerOpts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q, expected %s or %s", format, LogFormatText, LogFormatJSON)
+	}
+	return WrapLogHandler(next, opts...), nil
+}
+
+// WrapLogHandler returns a LogHandler passing records on to next.
+func WrapLogHandler(next slog.Handler, opts ...LogOption) *LogHandler {
+	h := &LogHandler{next: next}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Enabled reports whether the inner handler handles records at level.
+func (h *LogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle adds the context's trace and message attributes to r and passes it on,
+// unless the inner handler is not enabled for its level or it is a warning or
+// error being sampled out.
+func (h *LogHandler) Handle(ctx context.Context, r slog.Record) error {
+	// wrapping handlers such as slog-env do not always ask Enabled first
+	if !h.next.Enabled(ctx, r.Level) {
+		return nil
+	}
+	if h.sampler != nil && r.Level >= slog.LevelWarn {
+		write, suppressed := h.sampler.allow(sampleKey(r))
+		if !write {
+			return nil
+		}
+		if suppressed > 0 {
+			r = r.Clone()
+			r.AddAttrs(slog.Int(SuppressedKey, suppressed))
+		}
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String(TraceIDKey, sc.TraceID().String()), slog.String(SpanIDKey, sc.SpanID().String()))
+	}
+	if msg, ok := MessageFromContext(ctx); ok {
+		r.AddAttrs(slog.String(TopicKey, msg.Topic), slog.Int(PartitionKey, msg.Partition), slog.Int64(OffsetKey, msg.Offset))
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs returns a handler with the attributes added, sharing the error sampling.
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LogHandler{next: h.next.WithAttrs(attrs), sampler: h.sampler}
+}
+
+// WithGroup returns a handler with the group added, sharing the error sampling.
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{next: h.next.WithGroup(name), sampler: h.sampler}
+}
+
+// sampleKey identifies identical errors by their message and error attribute,
+// and identical warnings by their message.
+func sampleKey(r slog.Record) string {
+	key := r.Message
+	if r.Level < slog.LevelError {
+		return key
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == "error" || a.Key == "err" {
+			key += "\x00" + a.Value.String()
+			return false
+		}
+		return true
+	})
+	return key
+}
+
+// maxSampled bounds the errors tracked, expired windows are dropped beyond it.
+const maxSampled = 1000
+
+type sampler struct {
+	burst  int
+	window time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	windows map[string]*sampleWindow
+}
+
+type sampleWindow struct {
+	start      time.Time
+	written    int
+	suppressed int
+}
+
+func newSampler(s ErrorSampling, now func() time.Time) *sampler {
+	return &sampler{burst: s.Burst, window: s.Window, now: now, windows: make(map[string]*sampleWindow)}
+}
+
+// allow reports whether an error with key should be written and, when it
+// starts a new window, how many were dropped in the previous one.
+func (s *sampler) allow(key string) (bool, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+
+	w, ok := s.windows[key]
+	if !ok {
+		if len(s.windows) >= maxSampled {
+			s.prune(now)
+		}
+		w = &sampleWindow{start: now}
+		s.windows[key] = w
+	}
+	var suppressed int
+	if now.Sub(w.start) >= s.window {
+		suppressed = w.suppressed
+		*w = sampleWindow{start: now}
+	}
+	if w.written >= s.burst {
+		w.suppressed++
+		return false, 0
+	}
+	w.written++
+	return true, suppressed
+}
+
+// prune drops the expired windows. s.mu must be held.
+func (s *sampler) prune(now time.Time) {
+	for key, w := range s.windows {
+		if now.Sub(w.start) >= s.window {
+			delete(s.windows, key)
+		}
+	}
+}
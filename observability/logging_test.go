@@ -0,0 +1,125 @@
+package observability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// records decodes the JSON lines written by a handler.
+func records(t *testing.T, out *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var recs []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &rec))
+		recs = append(recs, rec)
+	}
+	return recs
+}
+
+func TestLogHandler_Context(t *testing.T) {
+	var out bytes.Buffer
+	h, err := NewLogHandler(&out, LogFormatJSON, nil)
+	require.NoError(t, err)
+	logger := slog.New(h).With("component", "test")
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+	ctx = ContextWithMessage(ctx, Message{Topic: "raw-weather-report", Partition: 2, Offset: 1200})
+
+	logger.InfoContext(ctx, "report parsed")
+	logger.Info("no context")
+
+	recs := records(t, &out)
+	require.Len(t, recs, 2)
+	assert.Equal(t, sc.TraceID().String(), recs[0][TraceIDKey])
+	assert.Equal(t, sc.SpanID().String(), recs[0][SpanIDKey])
+	assert.Equal(t, "raw-weather-report", recs[0][TopicKey])
+	assert.Equal(t, 2.0, recs[0][PartitionKey])
+	assert.Equal(t, 1200.0, recs[0][OffsetKey])
+	assert.Equal(t, "test", recs[0]["component"])
+
+	assert.NotContains(t, recs[1], TraceIDKey)
+	assert.NotContains(t, recs[1], TopicKey)
+}
+
+func TestNewLogHandler_Formats(t *testing.T) {
+	var out bytes.Buffer
+	h, err := NewLogHandler(&out, LogFormatText, &slog.HandlerOptions{Level: slog.LevelWarn})
+	require.NoError(t, err)
+	logger := slog.New(h)
+	logger.Info("hidden")
+	logger.Warn("shown", "report type", "Hail")
+	assert.NotContains(t, out.String(), "hidden")
+	assert.Contains(t, out.String(), `msg=shown "report type"=Hail`)
+
+	// the level is applied even when an outer handler does not check it
+	out.Reset()
+	require.NoError(t, h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "hidden", 0)))
+	assert.Empty(t, out.String())
+
+	_, err = NewLogHandler(&out, "logfmt", nil)
+	assert.ErrorContains(t, err, `unknown log format "logfmt"`)
+}
+
+func TestLogHandler_ErrorSampling(t *testing.T) {
+	var out bytes.Buffer
+	h, err := NewLogHandler(&out, LogFormatJSON, nil, WithErrorSampling(ErrorSampling{Burst: 2, Window: time.Minute}))
+	require.NoError(t, err)
+	now := time.Date(2024, 5, 17, 12, 0, 0, 0, time.UTC)
+	h.sampler.now = func() time.Time { return now }
+	logger := slog.New(h)
+
+	for i := 0; i < 5; i++ {
+		logger.Error("failed to write message", "error", errors.New("broker unavailable"))
+	}
+	logger.Error("failed to write message", "error", errors.New("message too large"))
+	logger.With("component", "test").Error("failed to write message", "error", errors.New("broker unavailable"))
+	logger.Info("failed to write message", "error", errors.New("broker unavailable"))
+	for i := 0; i < 3; i++ {
+		logger.Warn("skipping line that could not be parsed", "error", fmt.Errorf("bad line %d", i), "line", i)
+	}
+
+	recs := records(t, &out)
+	require.Len(t, recs, 6, "identical errors and warnings beyond the burst are dropped, other errors and levels are not")
+	assert.Equal(t, "message too large", recs[2]["error"])
+	assert.Equal(t, "INFO", recs[3]["level"])
+	assert.Equal(t, "bad line 1", recs[5]["error"], "warnings are sampled by their message alone")
+
+	out.Reset()
+	now = now.Add(time.Minute)
+	logger.Error("failed to write message", "error", errors.New("broker unavailable"))
+	recs = records(t, &out)
+	require.Len(t, recs, 1)
+	assert.Equal(t, 4.0, recs[0][SuppressedKey], "the first error of the next window counts the ones dropped")
+}
+
+func TestSampler_Prune(t *testing.T) {
+	now := time.Date(2024, 5, 17, 12, 0, 0, 0, time.UTC)
+	s := newSampler(ErrorSampling{Burst: 1, Window: time.Minute}, func() time.Time { return now })
+	for i := 0; i < maxSampled; i++ {
+		s.allow(strings.Repeat("x", i))
+	}
+	now = now.Add(time.Minute)
+	write, _ := s.allow("new")
+	assert.True(t, write)
+	assert.Len(t, s.windows, 1, "expired windows are dropped once the limit is reached")
+}
@@ -0,0 +1,232 @@
+// Package observability sets up tracing for the transformer.
+package observability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/contrib/propagators/b3"
+	jaegerPropagator "go.opentelemetry.io/contrib/propagators/jaeger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
+)
+
+// Exporters accepted by TracingConfig.
+const (
+	ExporterOTLPHTTP = "otlp-http"
+	ExporterOTLPGRPC = "otlp-grpc"
+	ExporterStdout   = "stdout"
+	ExporterNone     = "none"
+)
+
+// Propagators accepted by TracingConfig.
+const (
+	PropagatorTraceContext = "tracecontext" // W3C trace context
+	PropagatorBaggage      = "baggage"      // W3C baggage
+	PropagatorJaeger       = "jaeger"
+	PropagatorB3           = "b3"      // single b3 header
+	PropagatorB3Multi      = "b3multi" // X-B3-* headers
+)
+
+// TracingConfig configures the trace exporter, sampling and propagation.
+//
+// Endpoint is a host:port, or a URL for the OTLP exporters, and is left to the
+// exporter's default, or the OTEL_EXPORTER_OTLP_* environment variables, when empty.
+// Spans are sampled with a parent-based ratio sampler, so SampleRatio only applies
+// to traces that start in the transformer.
+type TracingConfig struct {
+	Exporter    string            `yaml:"exporter"`
+	Endpoint    string            `yaml:"endpoint"`
+	Insecure    bool              `yaml:"insecure"`
+	Headers     map[string]string `yaml:"headers"`
+	ServiceName string            `yaml:"service-name"`
+	Attributes  map[string]string `yaml:"attributes"`
+	SampleRatio float64           `yaml:"sample-ratio"`
+	Propagators []string          `yaml:"propagators"`
+}
+
+// DefaultTracingConfig returns an OTLP/HTTP exporter to a local collector that
+// samples every trace and propagates both W3C and Jaeger headers. The endpoint is
+// left empty so that each OTLP exporter uses its own port, 4318 or 4317.
+func DefaultTracingConfig() TracingConfig {
+	return TracingConfig{
+		Exporter:    ExporterOTLPHTTP,
+		Insecure:    true,
+		ServiceName: "transformer",
+		SampleRatio: 1,
+		Propagators: []string{PropagatorTraceContext, PropagatorBaggage, PropagatorJaeger},
+	}
+}
+
+// Validate returns every problem found with the config.
+func (c TracingConfig) Validate() error {
+	var errs []error
+	switch c.Exporter {
+	case ExporterOTLPHTTP, ExporterOTLPGRPC, ExporterStdout, ExporterNone:
+	default:
+		errs = append(errs, fmt.Errorf("unknown exporter %q, expected one of %s, %s, %s or %s",
+			c.Exporter, ExporterOTLPHTTP, ExporterOTLPGRPC, ExporterStdout, ExporterNone))
+	}
+	if c.ServiceName == "" {
+		errs = append(errs, errors.New("service name cannot be empty"))
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("sample ratio %v must be between 0 and 1", c.SampleRatio))
+	}
+	for _, p := range c.Propagators {
+		if _, ok := propagators[p]; !ok {
+			errs = append(errs, fmt.Errorf("unknown propagator %q, expected one of %s, %s, %s, %s or %s",
+				p, PropagatorTraceContext, PropagatorBaggage, PropagatorJaeger, PropagatorB3, PropagatorB3Multi))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+var propagators = map[string]propagation.TextMapPropagator{
+	PropagatorTraceContext: propagation.TraceContext{},
+	PropagatorBaggage:      propagation.Baggage{},
+	PropagatorJaeger:       jaegerPropagator.Jaeger{},
+	PropagatorB3:           b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)),
+	PropagatorB3Multi:      b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)),
+}
+
+// Option customizes SetupTracing.
+type Option func(*setup)
+
+type setup struct {
+	exporter sdktrace.SpanExporter
+	stdout   io.Writer
+}
+
+// WithExporter sends spans to exporter in place of the configured one, which is
+// how tests collect spans in memory.
+func WithExporter(exporter sdktrace.SpanExporter) Option {
+	return func(s *setup) {
+		s.exporter = exporter
+	}
+}
+
+// WithStdout sets where the stdout exporter writes, os.Stdout by default.
+func WithStdout(w io.Writer) Option {
+	return func(s *setup) {
+		s.stdout = w
+	}
+}
+
+// SetupTracing builds a tracer provider from cfg and installs it, along with the
+// configured propagators, as the global tracer provider and propagator. The
+// returned provider must be shut down to flush the spans still buffered.
+//
+// With the none exporter spans are still created, so trace ids are propagated,
+// but nothing is recorded.
+func SetupTracing(ctx context.Context, cfg TracingConfig, opts ...Option) (*sdktrace.TracerProvider, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid tracing config: %w", err)
+	}
+	s := setup{stdout: os.Stdout}
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	res, err := newResource(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	exporter := s.exporter
+	if exporter == nil {
+		if exporter, err = newExporter(ctx, cfg, s.stdout); err != nil {
+			return nil, fmt.Errorf("failed to create %s trace exporter: %w", cfg.Exporter, err)
+		}
+	}
+
+	tpOpts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+	if exporter != nil {
+		tpOpts = append(tpOpts,
+			sdktrace.WithBatcher(exporter),
+			sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		)
+	} else {
+		tpOpts = append(tpOpts, sdktrace.WithSampler(sdktrace.NeverSample()))
+	}
+	tp := sdktrace.NewTracerProvider(tpOpts...)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(newPropagator(cfg.Propagators))
+	return tp, nil
+}
+
+// newExporter returns the configured exporter, or nil for the none exporter.
+func newExporter(ctx context.Context, cfg TracingConfig, stdout io.Writer) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case ExporterOTLPHTTP:
+		var opts []otlptracehttp.Option
+		if strings.Contains(cfg.Endpoint, "://") {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+		} else if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	case ExporterOTLPGRPC:
+		var opts []otlptracegrpc.Option
+		if strings.Contains(cfg.Endpoint, "://") {
+			opts = append(opts, otlptracegrpc.WithEndpointURL(cfg.Endpoint))
+		} else if cfg.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(cfg.Headers))
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(stdout))
+	}
+	return nil, nil
+}
+
+// newResource describes the service with the configured name and attributes,
+// which take precedence over OTEL_RESOURCE_ATTRIBUTES and OTEL_SERVICE_NAME.
+func newResource(ctx context.Context, cfg TracingConfig) (*resource.Resource, error) {
+	attrs := []attribute.KeyValue{semconv.ServiceName(cfg.ServiceName)}
+	for k, v := range cfg.Attributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	res, err := resource.New(ctx,
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+		resource.WithAttributes(attrs...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+	return res, nil
+}
+
+// newPropagator combines the named propagators in order.
+func newPropagator(names []string) propagation.TextMapPropagator {
+	var ps []propagation.TextMapPropagator
+	for _, name := range names {
+		ps = append(ps, propagators[name])
+	}
+	return propagation.NewCompositeTextMapPropagator(ps...)
+}
@@ -0,0 +1,120 @@
+package observability
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracingConfig_Validate(t *testing.T) {
+	assert.NoError(t, DefaultTracingConfig().Validate())
+
+	cfg := TracingConfig{Exporter: "zipkin", SampleRatio: -0.5, Propagators: []string{PropagatorB3, "xray"}}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `unknown exporter "zipkin"`)
+	assert.ErrorContains(t, err, "service name")
+	assert.ErrorContains(t, err, "sample ratio")
+	assert.ErrorContains(t, err, `unknown propagator "xray"`)
+	assert.NotContains(t, err.Error(), `"b3"`)
+}
+
+func TestSetupTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	cfg := DefaultTracingConfig()
+	cfg.Attributes = map[string]string{"deployment.environment": "test"}
+	cfg.Propagators = []string{PropagatorTraceContext, PropagatorB3Multi}
+	tp, err := SetupTracing(context.Background(), cfg, WithExporter(exporter))
+	require.NoError(t, err)
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+
+	ctx, span := otel.Tracer("test").Start(context.Background(), "parse")
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	span.End()
+	require.NoError(t, tp.ForceFlush(context.Background()))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	attrs := map[string]string{}
+	for _, kv := range spans[0].Resource.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	assert.Equal(t, "transformer", attrs["service.name"])
+	assert.Equal(t, "test", attrs["deployment.environment"])
+
+	assert.NotEmpty(t, carrier.Get("traceparent"))
+	assert.Equal(t, span.SpanContext().TraceID().String(), carrier.Get("x-b3-traceid"))
+	assert.Empty(t, carrier.Get("uber-trace-id"), "only the configured propagators are used")
+}
+
+func TestSetupTracing_Sampling(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	cfg := DefaultTracingConfig()
+	cfg.SampleRatio = 0
+	tp, err := SetupTracing(context.Background(), cfg, WithExporter(exporter))
+	require.NoError(t, err)
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+	tracer := tp.Tracer("test")
+
+	_, root := tracer.Start(context.Background(), "root")
+	root.End()
+
+	// a sampled parent from upstream is followed despite the ratio
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	_, child := tracer.Start(trace.ContextWithRemoteSpanContext(context.Background(), parent), "child")
+	child.End()
+	require.NoError(t, tp.ForceFlush(context.Background()))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "child", spans[0].Name)
+}
+
+func TestSetupTracing_Exporters(t *testing.T) {
+	var out bytes.Buffer
+	cfg := DefaultTracingConfig()
+	cfg.Exporter = ExporterStdout
+	tp, err := SetupTracing(context.Background(), cfg, WithStdout(&out))
+	require.NoError(t, err)
+	_, span := tp.Tracer("test").Start(context.Background(), "parse")
+	span.End()
+	require.NoError(t, tp.Shutdown(context.Background()))
+	assert.Contains(t, out.String(), `"Name":"parse"`)
+
+	cfg.Exporter = ExporterNone
+	tp, err = SetupTracing(context.Background(), cfg)
+	require.NoError(t, err)
+	_, span = tp.Tracer("test").Start(context.Background(), "parse")
+	assert.False(t, span.IsRecording())
+	assert.True(t, span.SpanContext().IsValid(), "trace ids are still propagated")
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	// the default endpoint is left to each exporter, so otlp-grpc gets its own port
+	assert.Empty(t, DefaultTracingConfig().Endpoint)
+	for _, exporter := range []string{ExporterOTLPHTTP, ExporterOTLPGRPC} {
+		for _, endpoint := range []string{"", "http://localhost:4318"} {
+			cfg.Exporter = exporter
+			cfg.Endpoint = endpoint
+			tp, err = SetupTracing(context.Background(), cfg)
+			require.NoError(t, err, exporter)
+			assert.NoError(t, tp.Shutdown(context.Background()), "nothing to flush")
+		}
+	}
+
+	cfg.Exporter = "zipkin"
+	_, err = SetupTracing(context.Background(), cfg)
+	assert.ErrorContains(t, err, "invalid tracing config")
+}
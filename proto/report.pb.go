@@ -21,26 +21,126 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-type HailMsg struct {
+// Severity is the impact category of a report based on its magnitude.
+type Severity int32
+
+const (
+	Severity_SEVERITY_UNKNOWN     Severity = 0
+	Severity_SEVERITY_SUB_SEVERE  Severity = 1
+	Severity_SEVERITY_SEVERE      Severity = 2
+	Severity_SEVERITY_SIGNIFICANT Severity = 3
+)
+
+// Enum value maps for Severity.
+var (
+	Severity_name = map[int32]string{
+		0: "SEVERITY_UNKNOWN",
+		1: "SEVERITY_SUB_SEVERE",
+		2: "SEVERITY_SEVERE",
+		3: "SEVERITY_SIGNIFICANT",
+	}
+	Severity_value = map[string]int32{
+		"SEVERITY_UNKNOWN":     0,
+		"SEVERITY_SUB_SEVERE":  1,
+		"SEVERITY_SEVERE":      2,
+		"SEVERITY_SIGNIFICANT": 3,
+	}
+)
+
+func (x Severity) Enum() *Severity {
+	p := new(Severity)
+	*p = x
+	return p
+}
+
+func (x Severity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Severity) Descriptor() protoreflect.EnumDescriptor {
+	return file_report_proto_enumTypes[0].Descriptor()
+}
+
+func (Severity) Type() protoreflect.EnumType {
+	return &file_report_proto_enumTypes[0]
+}
+
+func (x Severity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Severity.Descriptor instead.
+func (Severity) EnumDescriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{0}
+}
+
+// Confidence is how sure an inferred value is.
+type Confidence int32
+
+const (
+	Confidence_CONFIDENCE_NONE   Confidence = 0
+	Confidence_CONFIDENCE_LOW    Confidence = 1
+	Confidence_CONFIDENCE_MEDIUM Confidence = 2
+	Confidence_CONFIDENCE_HIGH   Confidence = 3
+)
+
+// Enum value maps for Confidence.
+var (
+	Confidence_name = map[int32]string{
+		0: "CONFIDENCE_NONE",
+		1: "CONFIDENCE_LOW",
+		2: "CONFIDENCE_MEDIUM",
+		3: "CONFIDENCE_HIGH",
+	}
+	Confidence_value = map[string]int32{
+		"CONFIDENCE_NONE":   0,
+		"CONFIDENCE_LOW":    1,
+		"CONFIDENCE_MEDIUM": 2,
+		"CONFIDENCE_HIGH":   3,
+	}
+)
+
+func (x Confidence) Enum() *Confidence {
+	p := new(Confidence)
+	*p = x
+	return p
+}
+
+func (x Confidence) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Confidence) Descriptor() protoreflect.EnumDescriptor {
+	return file_report_proto_enumTypes[1].Descriptor()
+}
+
+func (Confidence) Type() protoreflect.EnumType {
+	return &file_report_proto_enumTypes[1]
+}
+
+func (x Confidence) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Confidence.Descriptor instead.
+func (Confidence) EnumDescriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{1}
+}
+
+// InferredMagnitude is a magnitude taken from the remarks when the magnitude
+// column is UNK. Value is in the units of the column it stands in for.
+type InferredMagnitude struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Time      int64  `protobuf:"varint,1,opt,name=Time,proto3" json:"Time,omitempty"`
-	Size      int32  `protobuf:"varint,2,opt,name=Size,proto3" json:"Size,omitempty"`
-	Distance  int32  `protobuf:"varint,3,opt,name=Distance,proto3" json:"Distance,omitempty"`
-	Direction string `protobuf:"bytes,4,opt,name=Direction,proto3" json:"Direction,omitempty"`
-	Location  string `protobuf:"bytes,5,opt,name=Location,proto3" json:"Location,omitempty"`
-	County    string `protobuf:"bytes,6,opt,name=County,proto3" json:"County,omitempty"`
-	State     string `protobuf:"bytes,7,opt,name=State,proto3" json:"State,omitempty"`
-	Lat       string `protobuf:"bytes,8,opt,name=Lat,proto3" json:"Lat,omitempty"`
-	Lon       string `protobuf:"bytes,9,opt,name=Lon,proto3" json:"Lon,omitempty"`
-	Remarks   string `protobuf:"bytes,10,opt,name=Remarks,proto3" json:"Remarks,omitempty"`
-	Type      string `protobuf:"bytes,11,opt,name=Type,proto3" json:"Type,omitempty"`
+	Value      int32      `protobuf:"varint,1,opt,name=Value,proto3" json:"Value,omitempty"`
+	Confidence Confidence `protobuf:"varint,2,opt,name=Confidence,proto3,enum=proto.Confidence" json:"Confidence,omitempty"`
+	Source     string     `protobuf:"bytes,3,opt,name=Source,proto3" json:"Source,omitempty"`
 }
 
-func (x *HailMsg) Reset() {
-	*x = HailMsg{}
+func (x *InferredMagnitude) Reset() {
+	*x = InferredMagnitude{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_report_proto_msgTypes[0]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -48,13 +148,13 @@ func (x *HailMsg) Reset() {
 	}
 }
 
-func (x *HailMsg) String() string {
+func (x *InferredMagnitude) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HailMsg) ProtoMessage() {}
+func (*InferredMagnitude) ProtoMessage() {}
 
-func (x *HailMsg) ProtoReflect() protoreflect.Message {
+func (x *InferredMagnitude) ProtoReflect() protoreflect.Message {
 	mi := &file_report_proto_msgTypes[0]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -66,123 +166,1871 @@ func (x *HailMsg) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HailMsg.ProtoReflect.Descriptor instead.
-func (*HailMsg) Descriptor() ([]byte, []int) {
+// Deprecated: Use InferredMagnitude.ProtoReflect.Descriptor instead.
+func (*InferredMagnitude) Descriptor() ([]byte, []int) {
 	return file_report_proto_rawDescGZIP(), []int{0}
 }
 
-func (x *HailMsg) GetTime() int64 {
+func (x *InferredMagnitude) GetValue() int32 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *InferredMagnitude) GetConfidence() Confidence {
+	if x != nil {
+		return x.Confidence
+	}
+	return Confidence_CONFIDENCE_NONE
+}
+
+func (x *InferredMagnitude) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+// RemarksInfo holds the structured details pulled out of a report's remarks.
+type RemarksInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Office        string   `protobuf:"bytes,1,opt,name=Office,proto3" json:"Office,omitempty"`
+	Qualifier     string   `protobuf:"bytes,2,opt,name=Qualifier,proto3" json:"Qualifier,omitempty"`
+	Damage        []string `protobuf:"bytes,3,rep,name=Damage,proto3" json:"Damage,omitempty"`
+	Injuries      bool     `protobuf:"varint,4,opt,name=Injuries,proto3" json:"Injuries,omitempty"`
+	InjuryCount   int32    `protobuf:"varint,5,opt,name=InjuryCount,proto3" json:"InjuryCount,omitempty"`
+	Fatalities    bool     `protobuf:"varint,6,opt,name=Fatalities,proto3" json:"Fatalities,omitempty"`
+	FatalityCount int32    `protobuf:"varint,7,opt,name=FatalityCount,proto3" json:"FatalityCount,omitempty"`
+	Stations      []string `protobuf:"bytes,8,rep,name=Stations,proto3" json:"Stations,omitempty"`
+}
+
+func (x *RemarksInfo) Reset() {
+	*x = RemarksInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_report_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemarksInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemarksInfo) ProtoMessage() {}
+
+func (x *RemarksInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_report_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemarksInfo.ProtoReflect.Descriptor instead.
+func (*RemarksInfo) Descriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RemarksInfo) GetOffice() string {
+	if x != nil {
+		return x.Office
+	}
+	return ""
+}
+
+func (x *RemarksInfo) GetQualifier() string {
+	if x != nil {
+		return x.Qualifier
+	}
+	return ""
+}
+
+func (x *RemarksInfo) GetDamage() []string {
+	if x != nil {
+		return x.Damage
+	}
+	return nil
+}
+
+func (x *RemarksInfo) GetInjuries() bool {
+	if x != nil {
+		return x.Injuries
+	}
+	return false
+}
+
+func (x *RemarksInfo) GetInjuryCount() int32 {
+	if x != nil {
+		return x.InjuryCount
+	}
+	return 0
+}
+
+func (x *RemarksInfo) GetFatalities() bool {
+	if x != nil {
+		return x.Fatalities
+	}
+	return false
+}
+
+func (x *RemarksInfo) GetFatalityCount() int32 {
+	if x != nil {
+		return x.FatalityCount
+	}
+	return 0
+}
+
+func (x *RemarksInfo) GetStations() []string {
+	if x != nil {
+		return x.Stations
+	}
+	return nil
+}
+
+// Impact is the harm a report caused, as recorded in the SPC historical database.
+type Impact struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Injuries   int32 `protobuf:"varint,1,opt,name=Injuries,proto3" json:"Injuries,omitempty"`
+	Fatalities int32 `protobuf:"varint,2,opt,name=Fatalities,proto3" json:"Fatalities,omitempty"`
+	// Losses are in dollars. Before 1996 property loss was only recorded as a
+	// category from 1 to 9, kept in PropertyLossCategory.
+	PropertyLoss         float64 `protobuf:"fixed64,3,opt,name=PropertyLoss,proto3" json:"PropertyLoss,omitempty"`
+	CropLoss             float64 `protobuf:"fixed64,4,opt,name=CropLoss,proto3" json:"CropLoss,omitempty"`
+	PropertyLossCategory int32   `protobuf:"varint,5,opt,name=PropertyLossCategory,proto3" json:"PropertyLossCategory,omitempty"`
+}
+
+func (x *Impact) Reset() {
+	*x = Impact{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_report_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Impact) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Impact) ProtoMessage() {}
+
+func (x *Impact) ProtoReflect() protoreflect.Message {
+	mi := &file_report_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Impact.ProtoReflect.Descriptor instead.
+func (*Impact) Descriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Impact) GetInjuries() int32 {
+	if x != nil {
+		return x.Injuries
+	}
+	return 0
+}
+
+func (x *Impact) GetFatalities() int32 {
+	if x != nil {
+		return x.Fatalities
+	}
+	return 0
+}
+
+func (x *Impact) GetPropertyLoss() float64 {
+	if x != nil {
+		return x.PropertyLoss
+	}
+	return 0
+}
+
+func (x *Impact) GetCropLoss() float64 {
+	if x != nil {
+		return x.CropLoss
+	}
+	return 0
+}
+
+func (x *Impact) GetPropertyLossCategory() int32 {
+	if x != nil {
+		return x.PropertyLossCategory
+	}
+	return 0
+}
+
+// Track is the path of a tornado from the SPC historical database. It starts
+// at the Lat and Lon of the tornado report.
+type Track struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EndLat      string  `protobuf:"bytes,1,opt,name=EndLat,proto3" json:"EndLat,omitempty"`
+	EndLon      string  `protobuf:"bytes,2,opt,name=EndLon,proto3" json:"EndLon,omitempty"`
+	LengthMiles float64 `protobuf:"fixed64,3,opt,name=LengthMiles,proto3" json:"LengthMiles,omitempty"`
+	WidthYards  int32   `protobuf:"varint,4,opt,name=WidthYards,proto3" json:"WidthYards,omitempty"`
+	// States is the number of states the whole track crossed, and Segment whether
+	// the record is the whole track (1), a state's part of it (2), or a county's (-9).
+	States     int32    `protobuf:"varint,5,opt,name=States,proto3" json:"States,omitempty"`
+	Segment    int32    `protobuf:"varint,6,opt,name=Segment,proto3" json:"Segment,omitempty"`
+	CountyFips []string `protobuf:"bytes,7,rep,name=CountyFips,proto3" json:"CountyFips,omitempty"`
+}
+
+func (x *Track) Reset() {
+	*x = Track{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_report_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Track) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Track) ProtoMessage() {}
+
+func (x *Track) ProtoReflect() protoreflect.Message {
+	mi := &file_report_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Track.ProtoReflect.Descriptor instead.
+func (*Track) Descriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Track) GetEndLat() string {
+	if x != nil {
+		return x.EndLat
+	}
+	return ""
+}
+
+func (x *Track) GetEndLon() string {
+	if x != nil {
+		return x.EndLon
+	}
+	return ""
+}
+
+func (x *Track) GetLengthMiles() float64 {
+	if x != nil {
+		return x.LengthMiles
+	}
+	return 0
+}
+
+func (x *Track) GetWidthYards() int32 {
+	if x != nil {
+		return x.WidthYards
+	}
+	return 0
+}
+
+func (x *Track) GetStates() int32 {
+	if x != nil {
+		return x.States
+	}
+	return 0
+}
+
+func (x *Track) GetSegment() int32 {
+	if x != nil {
+		return x.Segment
+	}
+	return 0
+}
+
+func (x *Track) GetCountyFips() []string {
+	if x != nil {
+		return x.CountyFips
+	}
+	return nil
+}
+
+// StormEvent is the part of an NCEI Storm Events record that the report messages
+// have no fields for. The report's time, location, magnitude and direct
+// casualties come from the record's beginning.
+type StormEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EpisodeID int64  `protobuf:"varint,1,opt,name=EpisodeID,proto3" json:"EpisodeID,omitempty"`
+	EventID   int64  `protobuf:"varint,2,opt,name=EventID,proto3" json:"EventID,omitempty"`
+	EventType string `protobuf:"bytes,3,opt,name=EventType,proto3" json:"EventType,omitempty"`
+	EndTime   int64  `protobuf:"varint,4,opt,name=EndTime,proto3" json:"EndTime,omitempty"`
+	// TimeZone is the zone of the record's local times, such as CST-6.
+	TimeZone string `protobuf:"bytes,5,opt,name=TimeZone,proto3" json:"TimeZone,omitempty"`
+	// ZoneType is C for a county, Z for a forecast zone or M for a marine zone.
+	ZoneType         string `protobuf:"bytes,6,opt,name=ZoneType,proto3" json:"ZoneType,omitempty"`
+	ZoneFips         string `protobuf:"bytes,7,opt,name=ZoneFips,proto3" json:"ZoneFips,omitempty"`
+	ZoneName         string `protobuf:"bytes,8,opt,name=ZoneName,proto3" json:"ZoneName,omitempty"`
+	InjuriesIndirect int32  `protobuf:"varint,9,opt,name=InjuriesIndirect,proto3" json:"InjuriesIndirect,omitempty"`
+	DeathsIndirect   int32  `protobuf:"varint,10,opt,name=DeathsIndirect,proto3" json:"DeathsIndirect,omitempty"`
+	Source           string `protobuf:"bytes,11,opt,name=Source,proto3" json:"Source,omitempty"`
+	EndDistance      int32  `protobuf:"varint,12,opt,name=EndDistance,proto3" json:"EndDistance,omitempty"`
+	EndDirection     string `protobuf:"bytes,13,opt,name=EndDirection,proto3" json:"EndDirection,omitempty"`
+	EndLocation      string `protobuf:"bytes,14,opt,name=EndLocation,proto3" json:"EndLocation,omitempty"`
+	EndLat           string `protobuf:"bytes,15,opt,name=EndLat,proto3" json:"EndLat,omitempty"`
+	EndLon           string `protobuf:"bytes,16,opt,name=EndLon,proto3" json:"EndLon,omitempty"`
+	EpisodeNarrative string `protobuf:"bytes,17,opt,name=EpisodeNarrative,proto3" json:"EpisodeNarrative,omitempty"`
+	DataSource       string `protobuf:"bytes,18,opt,name=DataSource,proto3" json:"DataSource,omitempty"`
+}
+
+func (x *StormEvent) Reset() {
+	*x = StormEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_report_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StormEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StormEvent) ProtoMessage() {}
+
+func (x *StormEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_report_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StormEvent.ProtoReflect.Descriptor instead.
+func (*StormEvent) Descriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StormEvent) GetEpisodeID() int64 {
+	if x != nil {
+		return x.EpisodeID
+	}
+	return 0
+}
+
+func (x *StormEvent) GetEventID() int64 {
+	if x != nil {
+		return x.EventID
+	}
+	return 0
+}
+
+func (x *StormEvent) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *StormEvent) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *StormEvent) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *StormEvent) GetZoneType() string {
+	if x != nil {
+		return x.ZoneType
+	}
+	return ""
+}
+
+func (x *StormEvent) GetZoneFips() string {
+	if x != nil {
+		return x.ZoneFips
+	}
+	return ""
+}
+
+func (x *StormEvent) GetZoneName() string {
+	if x != nil {
+		return x.ZoneName
+	}
+	return ""
+}
+
+func (x *StormEvent) GetInjuriesIndirect() int32 {
+	if x != nil {
+		return x.InjuriesIndirect
+	}
+	return 0
+}
+
+func (x *StormEvent) GetDeathsIndirect() int32 {
+	if x != nil {
+		return x.DeathsIndirect
+	}
+	return 0
+}
+
+func (x *StormEvent) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *StormEvent) GetEndDistance() int32 {
+	if x != nil {
+		return x.EndDistance
+	}
+	return 0
+}
+
+func (x *StormEvent) GetEndDirection() string {
+	if x != nil {
+		return x.EndDirection
+	}
+	return ""
+}
+
+func (x *StormEvent) GetEndLocation() string {
+	if x != nil {
+		return x.EndLocation
+	}
+	return ""
+}
+
+func (x *StormEvent) GetEndLat() string {
+	if x != nil {
+		return x.EndLat
+	}
+	return ""
+}
+
+func (x *StormEvent) GetEndLon() string {
+	if x != nil {
+		return x.EndLon
+	}
+	return ""
+}
+
+func (x *StormEvent) GetEpisodeNarrative() string {
+	if x != nil {
+		return x.EpisodeNarrative
+	}
+	return ""
+}
+
+func (x *StormEvent) GetDataSource() string {
+	if x != nil {
+		return x.DataSource
+	}
+	return ""
+}
+
+// LocalStormReport is the part of a report from an NWS Local Storm Report (LSR)
+// text product that the report messages have no fields for.
+type LocalStormReport struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Office is the issuing weather forecast office, such as OUN, and ProductTime
+	// when it issued the product.
+	Office      string `protobuf:"bytes,1,opt,name=Office,proto3" json:"Office,omitempty"`
+	ProductTime int64  `protobuf:"varint,2,opt,name=ProductTime,proto3" json:"ProductTime,omitempty"`
+	// Event is the event type as written in the product, such as TSTM WND GST.
+	Event string `protobuf:"bytes,3,opt,name=Event,proto3" json:"Event,omitempty"`
+	// MagnitudeQualifier is E for an estimated, M for a measured or U for an
+	// unknown magnitude.
+	MagnitudeQualifier string `protobuf:"bytes,4,opt,name=MagnitudeQualifier,proto3" json:"MagnitudeQualifier,omitempty"`
+	Source             string `protobuf:"bytes,5,opt,name=Source,proto3" json:"Source,omitempty"`
+}
+
+func (x *LocalStormReport) Reset() {
+	*x = LocalStormReport{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_report_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LocalStormReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocalStormReport) ProtoMessage() {}
+
+func (x *LocalStormReport) ProtoReflect() protoreflect.Message {
+	mi := &file_report_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocalStormReport.ProtoReflect.Descriptor instead.
+func (*LocalStormReport) Descriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LocalStormReport) GetOffice() string {
+	if x != nil {
+		return x.Office
+	}
+	return ""
+}
+
+func (x *LocalStormReport) GetProductTime() int64 {
+	if x != nil {
+		return x.ProductTime
+	}
+	return 0
+}
+
+func (x *LocalStormReport) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *LocalStormReport) GetMagnitudeQualifier() string {
+	if x != nil {
+		return x.MagnitudeQualifier
+	}
+	return ""
+}
+
+func (x *LocalStormReport) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type HailMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time              int64              `protobuf:"varint,1,opt,name=Time,proto3" json:"Time,omitempty"`
+	Size              int32              `protobuf:"varint,2,opt,name=Size,proto3" json:"Size,omitempty"`
+	Distance          int32              `protobuf:"varint,3,opt,name=Distance,proto3" json:"Distance,omitempty"`
+	Direction         string             `protobuf:"bytes,4,opt,name=Direction,proto3" json:"Direction,omitempty"`
+	Location          string             `protobuf:"bytes,5,opt,name=Location,proto3" json:"Location,omitempty"`
+	County            string             `protobuf:"bytes,6,opt,name=County,proto3" json:"County,omitempty"`
+	State             string             `protobuf:"bytes,7,opt,name=State,proto3" json:"State,omitempty"`
+	Lat               string             `protobuf:"bytes,8,opt,name=Lat,proto3" json:"Lat,omitempty"`
+	Lon               string             `protobuf:"bytes,9,opt,name=Lon,proto3" json:"Lon,omitempty"`
+	Remarks           string             `protobuf:"bytes,10,opt,name=Remarks,proto3" json:"Remarks,omitempty"`
+	Type              string             `protobuf:"bytes,11,opt,name=Type,proto3" json:"Type,omitempty"`
+	CountyFips        string             `protobuf:"bytes,12,opt,name=county_fips,json=countyFips,proto3" json:"county_fips,omitempty"`
+	StateFips         string             `protobuf:"bytes,13,opt,name=state_fips,json=stateFips,proto3" json:"state_fips,omitempty"`
+	Cwa               string             `protobuf:"bytes,14,opt,name=cwa,proto3" json:"cwa,omitempty"`
+	StateMismatch     bool               `protobuf:"varint,15,opt,name=state_mismatch,json=stateMismatch,proto3" json:"state_mismatch,omitempty"`
+	Severity          Severity           `protobuf:"varint,16,opt,name=severity,proto3,enum=proto.Severity" json:"severity,omitempty"`
+	Significant       bool               `protobuf:"varint,17,opt,name=significant,proto3" json:"significant,omitempty"`
+	EventId           string             `protobuf:"bytes,18,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	RemarksInfo       *RemarksInfo       `protobuf:"bytes,19,opt,name=remarks_info,json=remarksInfo,proto3" json:"remarks_info,omitempty"`
+	InferredMagnitude *InferredMagnitude `protobuf:"bytes,20,opt,name=inferred_magnitude,json=inferredMagnitude,proto3" json:"inferred_magnitude,omitempty"`
+	QcFlags           uint32             `protobuf:"varint,21,opt,name=qc_flags,json=qcFlags,proto3" json:"qc_flags,omitempty"`
+	QcIssues          []string           `protobuf:"bytes,22,rep,name=qc_issues,json=qcIssues,proto3" json:"qc_issues,omitempty"`
+	Impact            *Impact            `protobuf:"bytes,23,opt,name=impact,proto3" json:"impact,omitempty"`
+	StormEvent        *StormEvent        `protobuf:"bytes,24,opt,name=storm_event,json=stormEvent,proto3" json:"storm_event,omitempty"`
+	Lsr               *LocalStormReport  `protobuf:"bytes,25,opt,name=lsr,proto3" json:"lsr,omitempty"`
+}
+
+func (x *HailMsg) Reset() {
+	*x = HailMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_report_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HailMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HailMsg) ProtoMessage() {}
+
+func (x *HailMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_report_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HailMsg.ProtoReflect.Descriptor instead.
+func (*HailMsg) Descriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *HailMsg) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *HailMsg) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *HailMsg) GetDistance() int32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *HailMsg) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *HailMsg) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *HailMsg) GetCounty() string {
+	if x != nil {
+		return x.County
+	}
+	return ""
+}
+
+func (x *HailMsg) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *HailMsg) GetLat() string {
+	if x != nil {
+		return x.Lat
+	}
+	return ""
+}
+
+func (x *HailMsg) GetLon() string {
+	if x != nil {
+		return x.Lon
+	}
+	return ""
+}
+
+func (x *HailMsg) GetRemarks() string {
+	if x != nil {
+		return x.Remarks
+	}
+	return ""
+}
+
+func (x *HailMsg) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *HailMsg) GetCountyFips() string {
+	if x != nil {
+		return x.CountyFips
+	}
+	return ""
+}
+
+func (x *HailMsg) GetStateFips() string {
+	if x != nil {
+		return x.StateFips
+	}
+	return ""
+}
+
+func (x *HailMsg) GetCwa() string {
+	if x != nil {
+		return x.Cwa
+	}
+	return ""
+}
+
+func (x *HailMsg) GetStateMismatch() bool {
+	if x != nil {
+		return x.StateMismatch
+	}
+	return false
+}
+
+func (x *HailMsg) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_UNKNOWN
+}
+
+func (x *HailMsg) GetSignificant() bool {
+	if x != nil {
+		return x.Significant
+	}
+	return false
+}
+
+func (x *HailMsg) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *HailMsg) GetRemarksInfo() *RemarksInfo {
+	if x != nil {
+		return x.RemarksInfo
+	}
+	return nil
+}
+
+func (x *HailMsg) GetInferredMagnitude() *InferredMagnitude {
+	if x != nil {
+		return x.InferredMagnitude
+	}
+	return nil
+}
+
+func (x *HailMsg) GetQcFlags() uint32 {
+	if x != nil {
+		return x.QcFlags
+	}
+	return 0
+}
+
+func (x *HailMsg) GetQcIssues() []string {
+	if x != nil {
+		return x.QcIssues
+	}
+	return nil
+}
+
+func (x *HailMsg) GetImpact() *Impact {
+	if x != nil {
+		return x.Impact
+	}
+	return nil
+}
+
+func (x *HailMsg) GetStormEvent() *StormEvent {
+	if x != nil {
+		return x.StormEvent
+	}
+	return nil
+}
+
+func (x *HailMsg) GetLsr() *LocalStormReport {
+	if x != nil {
+		return x.Lsr
+	}
+	return nil
+}
+
+type WindMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time              int64              `protobuf:"varint,1,opt,name=Time,proto3" json:"Time,omitempty"`
+	Speed             int32              `protobuf:"varint,2,opt,name=Speed,proto3" json:"Speed,omitempty"`
+	Distance          int32              `protobuf:"varint,3,opt,name=Distance,proto3" json:"Distance,omitempty"`
+	Direction         string             `protobuf:"bytes,4,opt,name=Direction,proto3" json:"Direction,omitempty"`
+	Location          string             `protobuf:"bytes,5,opt,name=Location,proto3" json:"Location,omitempty"`
+	County            string             `protobuf:"bytes,6,opt,name=County,proto3" json:"County,omitempty"`
+	State             string             `protobuf:"bytes,7,opt,name=State,proto3" json:"State,omitempty"`
+	Lat               string             `protobuf:"bytes,8,opt,name=Lat,proto3" json:"Lat,omitempty"`
+	Lon               string             `protobuf:"bytes,9,opt,name=Lon,proto3" json:"Lon,omitempty"`
+	Remarks           string             `protobuf:"bytes,10,opt,name=Remarks,proto3" json:"Remarks,omitempty"`
+	Type              string             `protobuf:"bytes,11,opt,name=Type,proto3" json:"Type,omitempty"`
+	CountyFips        string             `protobuf:"bytes,12,opt,name=county_fips,json=countyFips,proto3" json:"county_fips,omitempty"`
+	StateFips         string             `protobuf:"bytes,13,opt,name=state_fips,json=stateFips,proto3" json:"state_fips,omitempty"`
+	Cwa               string             `protobuf:"bytes,14,opt,name=cwa,proto3" json:"cwa,omitempty"`
+	StateMismatch     bool               `protobuf:"varint,15,opt,name=state_mismatch,json=stateMismatch,proto3" json:"state_mismatch,omitempty"`
+	Severity          Severity           `protobuf:"varint,16,opt,name=severity,proto3,enum=proto.Severity" json:"severity,omitempty"`
+	Significant       bool               `protobuf:"varint,17,opt,name=significant,proto3" json:"significant,omitempty"`
+	EventId           string             `protobuf:"bytes,18,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	RemarksInfo       *RemarksInfo       `protobuf:"bytes,19,opt,name=remarks_info,json=remarksInfo,proto3" json:"remarks_info,omitempty"`
+	InferredMagnitude *InferredMagnitude `protobuf:"bytes,20,opt,name=inferred_magnitude,json=inferredMagnitude,proto3" json:"inferred_magnitude,omitempty"`
+	QcFlags           uint32             `protobuf:"varint,21,opt,name=qc_flags,json=qcFlags,proto3" json:"qc_flags,omitempty"`
+	QcIssues          []string           `protobuf:"bytes,22,rep,name=qc_issues,json=qcIssues,proto3" json:"qc_issues,omitempty"`
+	Impact            *Impact            `protobuf:"bytes,23,opt,name=impact,proto3" json:"impact,omitempty"`
+	// MagnitudeType is how the speed was found: EG or MG for an estimated or
+	// measured gust, ES or MS for sustained wind.
+	MagnitudeType string            `protobuf:"bytes,24,opt,name=magnitude_type,json=magnitudeType,proto3" json:"magnitude_type,omitempty"`
+	StormEvent    *StormEvent       `protobuf:"bytes,25,opt,name=storm_event,json=stormEvent,proto3" json:"storm_event,omitempty"`
+	Lsr           *LocalStormReport `protobuf:"bytes,26,opt,name=lsr,proto3" json:"lsr,omitempty"`
+}
+
+func (x *WindMsg) Reset() {
+	*x = WindMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_report_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WindMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WindMsg) ProtoMessage() {}
+
+func (x *WindMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_report_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WindMsg.ProtoReflect.Descriptor instead.
+func (*WindMsg) Descriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *WindMsg) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *WindMsg) GetSpeed() int32 {
+	if x != nil {
+		return x.Speed
+	}
+	return 0
+}
+
+func (x *WindMsg) GetDistance() int32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *WindMsg) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *WindMsg) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *WindMsg) GetCounty() string {
+	if x != nil {
+		return x.County
+	}
+	return ""
+}
+
+func (x *WindMsg) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *WindMsg) GetLat() string {
+	if x != nil {
+		return x.Lat
+	}
+	return ""
+}
+
+func (x *WindMsg) GetLon() string {
+	if x != nil {
+		return x.Lon
+	}
+	return ""
+}
+
+func (x *WindMsg) GetRemarks() string {
+	if x != nil {
+		return x.Remarks
+	}
+	return ""
+}
+
+func (x *WindMsg) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *WindMsg) GetCountyFips() string {
+	if x != nil {
+		return x.CountyFips
+	}
+	return ""
+}
+
+func (x *WindMsg) GetStateFips() string {
+	if x != nil {
+		return x.StateFips
+	}
+	return ""
+}
+
+func (x *WindMsg) GetCwa() string {
+	if x != nil {
+		return x.Cwa
+	}
+	return ""
+}
+
+func (x *WindMsg) GetStateMismatch() bool {
+	if x != nil {
+		return x.StateMismatch
+	}
+	return false
+}
+
+func (x *WindMsg) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_UNKNOWN
+}
+
+func (x *WindMsg) GetSignificant() bool {
+	if x != nil {
+		return x.Significant
+	}
+	return false
+}
+
+func (x *WindMsg) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *WindMsg) GetRemarksInfo() *RemarksInfo {
+	if x != nil {
+		return x.RemarksInfo
+	}
+	return nil
+}
+
+func (x *WindMsg) GetInferredMagnitude() *InferredMagnitude {
+	if x != nil {
+		return x.InferredMagnitude
+	}
+	return nil
+}
+
+func (x *WindMsg) GetQcFlags() uint32 {
+	if x != nil {
+		return x.QcFlags
+	}
+	return 0
+}
+
+func (x *WindMsg) GetQcIssues() []string {
+	if x != nil {
+		return x.QcIssues
+	}
+	return nil
+}
+
+func (x *WindMsg) GetImpact() *Impact {
+	if x != nil {
+		return x.Impact
+	}
+	return nil
+}
+
+func (x *WindMsg) GetMagnitudeType() string {
+	if x != nil {
+		return x.MagnitudeType
+	}
+	return ""
+}
+
+func (x *WindMsg) GetStormEvent() *StormEvent {
+	if x != nil {
+		return x.StormEvent
+	}
+	return nil
+}
+
+func (x *WindMsg) GetLsr() *LocalStormReport {
+	if x != nil {
+		return x.Lsr
+	}
+	return nil
+}
+
+type TornadoMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time              int64              `protobuf:"varint,1,opt,name=Time,proto3" json:"Time,omitempty"`
+	F_Scale           int32              `protobuf:"varint,2,opt,name=F_Scale,json=FScale,proto3" json:"F_Scale,omitempty"`
+	Distance          int32              `protobuf:"varint,3,opt,name=Distance,proto3" json:"Distance,omitempty"`
+	Direction         string             `protobuf:"bytes,4,opt,name=Direction,proto3" json:"Direction,omitempty"`
+	Location          string             `protobuf:"bytes,5,opt,name=Location,proto3" json:"Location,omitempty"`
+	County            string             `protobuf:"bytes,6,opt,name=County,proto3" json:"County,omitempty"`
+	State             string             `protobuf:"bytes,7,opt,name=State,proto3" json:"State,omitempty"`
+	Lat               string             `protobuf:"bytes,8,opt,name=Lat,proto3" json:"Lat,omitempty"`
+	Lon               string             `protobuf:"bytes,9,opt,name=Lon,proto3" json:"Lon,omitempty"`
+	Remarks           string             `protobuf:"bytes,10,opt,name=Remarks,proto3" json:"Remarks,omitempty"`
+	Type              string             `protobuf:"bytes,11,opt,name=Type,proto3" json:"Type,omitempty"`
+	CountyFips        string             `protobuf:"bytes,12,opt,name=county_fips,json=countyFips,proto3" json:"county_fips,omitempty"`
+	StateFips         string             `protobuf:"bytes,13,opt,name=state_fips,json=stateFips,proto3" json:"state_fips,omitempty"`
+	Cwa               string             `protobuf:"bytes,14,opt,name=cwa,proto3" json:"cwa,omitempty"`
+	StateMismatch     bool               `protobuf:"varint,15,opt,name=state_mismatch,json=stateMismatch,proto3" json:"state_mismatch,omitempty"`
+	Severity          Severity           `protobuf:"varint,16,opt,name=severity,proto3,enum=proto.Severity" json:"severity,omitempty"`
+	Significant       bool               `protobuf:"varint,17,opt,name=significant,proto3" json:"significant,omitempty"`
+	EventId           string             `protobuf:"bytes,18,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	RemarksInfo       *RemarksInfo       `protobuf:"bytes,19,opt,name=remarks_info,json=remarksInfo,proto3" json:"remarks_info,omitempty"`
+	InferredMagnitude *InferredMagnitude `protobuf:"bytes,20,opt,name=inferred_magnitude,json=inferredMagnitude,proto3" json:"inferred_magnitude,omitempty"`
+	QcFlags           uint32             `protobuf:"varint,21,opt,name=qc_flags,json=qcFlags,proto3" json:"qc_flags,omitempty"`
+	QcIssues          []string           `protobuf:"bytes,22,rep,name=qc_issues,json=qcIssues,proto3" json:"qc_issues,omitempty"`
+	Impact            *Impact            `protobuf:"bytes,23,opt,name=impact,proto3" json:"impact,omitempty"`
+	Track             *Track             `protobuf:"bytes,24,opt,name=track,proto3" json:"track,omitempty"`
+	StormEvent        *StormEvent        `protobuf:"bytes,25,opt,name=storm_event,json=stormEvent,proto3" json:"storm_event,omitempty"`
+	Lsr               *LocalStormReport  `protobuf:"bytes,26,opt,name=lsr,proto3" json:"lsr,omitempty"`
+}
+
+func (x *TornadoMsg) Reset() {
+	*x = TornadoMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_report_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TornadoMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TornadoMsg) ProtoMessage() {}
+
+func (x *TornadoMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_report_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TornadoMsg.ProtoReflect.Descriptor instead.
+func (*TornadoMsg) Descriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TornadoMsg) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *TornadoMsg) GetF_Scale() int32 {
+	if x != nil {
+		return x.F_Scale
+	}
+	return 0
+}
+
+func (x *TornadoMsg) GetDistance() int32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *TornadoMsg) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *TornadoMsg) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *TornadoMsg) GetCounty() string {
+	if x != nil {
+		return x.County
+	}
+	return ""
+}
+
+func (x *TornadoMsg) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *TornadoMsg) GetLat() string {
+	if x != nil {
+		return x.Lat
+	}
+	return ""
+}
+
+func (x *TornadoMsg) GetLon() string {
+	if x != nil {
+		return x.Lon
+	}
+	return ""
+}
+
+func (x *TornadoMsg) GetRemarks() string {
+	if x != nil {
+		return x.Remarks
+	}
+	return ""
+}
+
+func (x *TornadoMsg) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *TornadoMsg) GetCountyFips() string {
+	if x != nil {
+		return x.CountyFips
+	}
+	return ""
+}
+
+func (x *TornadoMsg) GetStateFips() string {
+	if x != nil {
+		return x.StateFips
+	}
+	return ""
+}
+
+func (x *TornadoMsg) GetCwa() string {
+	if x != nil {
+		return x.Cwa
+	}
+	return ""
+}
+
+func (x *TornadoMsg) GetStateMismatch() bool {
+	if x != nil {
+		return x.StateMismatch
+	}
+	return false
+}
+
+func (x *TornadoMsg) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_UNKNOWN
+}
+
+func (x *TornadoMsg) GetSignificant() bool {
+	if x != nil {
+		return x.Significant
+	}
+	return false
+}
+
+func (x *TornadoMsg) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *TornadoMsg) GetRemarksInfo() *RemarksInfo {
+	if x != nil {
+		return x.RemarksInfo
+	}
+	return nil
+}
+
+func (x *TornadoMsg) GetInferredMagnitude() *InferredMagnitude {
+	if x != nil {
+		return x.InferredMagnitude
+	}
+	return nil
+}
+
+func (x *TornadoMsg) GetQcFlags() uint32 {
+	if x != nil {
+		return x.QcFlags
+	}
+	return 0
+}
+
+func (x *TornadoMsg) GetQcIssues() []string {
+	if x != nil {
+		return x.QcIssues
+	}
+	return nil
+}
+
+func (x *TornadoMsg) GetImpact() *Impact {
+	if x != nil {
+		return x.Impact
+	}
+	return nil
+}
+
+func (x *TornadoMsg) GetTrack() *Track {
+	if x != nil {
+		return x.Track
+	}
+	return nil
+}
+
+func (x *TornadoMsg) GetStormEvent() *StormEvent {
+	if x != nil {
+		return x.StormEvent
+	}
+	return nil
+}
+
+func (x *TornadoMsg) GetLsr() *LocalStormReport {
+	if x != nil {
+		return x.Lsr
+	}
+	return nil
+}
+
+// LSRMsg is a report from an NWS Local Storm Report product of an event other
+// than hail, thunderstorm wind or a tornado, such as a flood or heavy snow. Its
+// Type is always LSR and EventType the kind of event, such as Flood.
+type LSRMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time int64 `protobuf:"varint,1,opt,name=Time,proto3" json:"Time,omitempty"`
+	// Magnitude is the magnitude as written in the product, in Unit, such as 4.5
+	// INCH of snow, and zero when the product gives none.
+	Magnitude     float64           `protobuf:"fixed64,2,opt,name=Magnitude,proto3" json:"Magnitude,omitempty"`
+	Distance      int32             `protobuf:"varint,3,opt,name=Distance,proto3" json:"Distance,omitempty"`
+	Direction     string            `protobuf:"bytes,4,opt,name=Direction,proto3" json:"Direction,omitempty"`
+	Location      string            `protobuf:"bytes,5,opt,name=Location,proto3" json:"Location,omitempty"`
+	County        string            `protobuf:"bytes,6,opt,name=County,proto3" json:"County,omitempty"`
+	State         string            `protobuf:"bytes,7,opt,name=State,proto3" json:"State,omitempty"`
+	Lat           string            `protobuf:"bytes,8,opt,name=Lat,proto3" json:"Lat,omitempty"`
+	Lon           string            `protobuf:"bytes,9,opt,name=Lon,proto3" json:"Lon,omitempty"`
+	Remarks       string            `protobuf:"bytes,10,opt,name=Remarks,proto3" json:"Remarks,omitempty"`
+	Type          string            `protobuf:"bytes,11,opt,name=Type,proto3" json:"Type,omitempty"`
+	CountyFips    string            `protobuf:"bytes,12,opt,name=county_fips,json=countyFips,proto3" json:"county_fips,omitempty"`
+	StateFips     string            `protobuf:"bytes,13,opt,name=state_fips,json=stateFips,proto3" json:"state_fips,omitempty"`
+	Cwa           string            `protobuf:"bytes,14,opt,name=cwa,proto3" json:"cwa,omitempty"`
+	StateMismatch bool              `protobuf:"varint,15,opt,name=state_mismatch,json=stateMismatch,proto3" json:"state_mismatch,omitempty"`
+	EventId       string            `protobuf:"bytes,16,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	QcFlags       uint32            `protobuf:"varint,17,opt,name=qc_flags,json=qcFlags,proto3" json:"qc_flags,omitempty"`
+	QcIssues      []string          `protobuf:"bytes,18,rep,name=qc_issues,json=qcIssues,proto3" json:"qc_issues,omitempty"`
+	EventType     string            `protobuf:"bytes,19,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	Unit          string            `protobuf:"bytes,20,opt,name=unit,proto3" json:"unit,omitempty"`
+	Lsr           *LocalStormReport `protobuf:"bytes,21,opt,name=lsr,proto3" json:"lsr,omitempty"`
+}
+
+func (x *LSRMsg) Reset() {
+	*x = LSRMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_report_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LSRMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LSRMsg) ProtoMessage() {}
+
+func (x *LSRMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_report_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LSRMsg.ProtoReflect.Descriptor instead.
+func (*LSRMsg) Descriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *LSRMsg) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *LSRMsg) GetMagnitude() float64 {
+	if x != nil {
+		return x.Magnitude
+	}
+	return 0
+}
+
+func (x *LSRMsg) GetDistance() int32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *LSRMsg) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *LSRMsg) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *LSRMsg) GetCounty() string {
+	if x != nil {
+		return x.County
+	}
+	return ""
+}
+
+func (x *LSRMsg) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *LSRMsg) GetLat() string {
+	if x != nil {
+		return x.Lat
+	}
+	return ""
+}
+
+func (x *LSRMsg) GetLon() string {
+	if x != nil {
+		return x.Lon
+	}
+	return ""
+}
+
+func (x *LSRMsg) GetRemarks() string {
+	if x != nil {
+		return x.Remarks
+	}
+	return ""
+}
+
+func (x *LSRMsg) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *LSRMsg) GetCountyFips() string {
+	if x != nil {
+		return x.CountyFips
+	}
+	return ""
+}
+
+func (x *LSRMsg) GetStateFips() string {
+	if x != nil {
+		return x.StateFips
+	}
+	return ""
+}
+
+func (x *LSRMsg) GetCwa() string {
+	if x != nil {
+		return x.Cwa
+	}
+	return ""
+}
+
+func (x *LSRMsg) GetStateMismatch() bool {
+	if x != nil {
+		return x.StateMismatch
+	}
+	return false
+}
+
+func (x *LSRMsg) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *LSRMsg) GetQcFlags() uint32 {
+	if x != nil {
+		return x.QcFlags
+	}
+	return 0
+}
+
+func (x *LSRMsg) GetQcIssues() []string {
+	if x != nil {
+		return x.QcIssues
+	}
+	return nil
+}
+
+func (x *LSRMsg) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *LSRMsg) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *LSRMsg) GetLsr() *LocalStormReport {
+	if x != nil {
+		return x.Lsr
+	}
+	return nil
+}
+
+// SummaryMsg aggregates the reports of one type in a state and county over a
+// tumbling window of event time.
+type SummaryMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	State        string  `protobuf:"bytes,1,opt,name=State,proto3" json:"State,omitempty"`
+	County       string  `protobuf:"bytes,2,opt,name=County,proto3" json:"County,omitempty"`
+	Type         string  `protobuf:"bytes,3,opt,name=Type,proto3" json:"Type,omitempty"`
+	WindowStart  int64   `protobuf:"varint,4,opt,name=WindowStart,proto3" json:"WindowStart,omitempty"`
+	WindowEnd    int64   `protobuf:"varint,5,opt,name=WindowEnd,proto3" json:"WindowEnd,omitempty"`
+	Count        int32   `protobuf:"varint,6,opt,name=Count,proto3" json:"Count,omitempty"`
+	MaxMagnitude int32   `protobuf:"varint,7,opt,name=MaxMagnitude,proto3" json:"MaxMagnitude,omitempty"`
+	FirstTime    int64   `protobuf:"varint,8,opt,name=FirstTime,proto3" json:"FirstTime,omitempty"`
+	LastTime     int64   `protobuf:"varint,9,opt,name=LastTime,proto3" json:"LastTime,omitempty"`
+	MinLat       float64 `protobuf:"fixed64,10,opt,name=MinLat,proto3" json:"MinLat,omitempty"`
+	MinLon       float64 `protobuf:"fixed64,11,opt,name=MinLon,proto3" json:"MinLon,omitempty"`
+	MaxLat       float64 `protobuf:"fixed64,12,opt,name=MaxLat,proto3" json:"MaxLat,omitempty"`
+	MaxLon       float64 `protobuf:"fixed64,13,opt,name=MaxLon,proto3" json:"MaxLon,omitempty"`
+}
+
+func (x *SummaryMsg) Reset() {
+	*x = SummaryMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_report_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SummaryMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SummaryMsg) ProtoMessage() {}
+
+func (x *SummaryMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_report_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SummaryMsg.ProtoReflect.Descriptor instead.
+func (*SummaryMsg) Descriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SummaryMsg) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *SummaryMsg) GetCounty() string {
+	if x != nil {
+		return x.County
+	}
+	return ""
+}
+
+func (x *SummaryMsg) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SummaryMsg) GetWindowStart() int64 {
+	if x != nil {
+		return x.WindowStart
+	}
+	return 0
+}
+
+func (x *SummaryMsg) GetWindowEnd() int64 {
+	if x != nil {
+		return x.WindowEnd
+	}
+	return 0
+}
+
+func (x *SummaryMsg) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *SummaryMsg) GetMaxMagnitude() int32 {
+	if x != nil {
+		return x.MaxMagnitude
+	}
+	return 0
+}
+
+func (x *SummaryMsg) GetFirstTime() int64 {
+	if x != nil {
+		return x.FirstTime
+	}
+	return 0
+}
+
+func (x *SummaryMsg) GetLastTime() int64 {
+	if x != nil {
+		return x.LastTime
+	}
+	return 0
+}
+
+func (x *SummaryMsg) GetMinLat() float64 {
+	if x != nil {
+		return x.MinLat
+	}
+	return 0
+}
+
+func (x *SummaryMsg) GetMinLon() float64 {
+	if x != nil {
+		return x.MinLon
+	}
+	return 0
+}
+
+func (x *SummaryMsg) GetMaxLat() float64 {
+	if x != nil {
+		return x.MaxLat
+	}
+	return 0
+}
+
+func (x *SummaryMsg) GetMaxLon() float64 {
+	if x != nil {
+		return x.MaxLon
+	}
+	return 0
+}
+
+// EventMsg describes a cluster of reports of the same type that are close
+// together in space and time and likely describe the same storm.
+type EventMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EventID      string   `protobuf:"bytes,1,opt,name=EventID,proto3" json:"EventID,omitempty"`
+	Type         string   `protobuf:"bytes,2,opt,name=Type,proto3" json:"Type,omitempty"`
+	Count        int32    `protobuf:"varint,3,opt,name=Count,proto3" json:"Count,omitempty"`
+	MaxMagnitude int32    `protobuf:"varint,4,opt,name=MaxMagnitude,proto3" json:"MaxMagnitude,omitempty"`
+	FirstTime    int64    `protobuf:"varint,5,opt,name=FirstTime,proto3" json:"FirstTime,omitempty"`
+	LastTime     int64    `protobuf:"varint,6,opt,name=LastTime,proto3" json:"LastTime,omitempty"`
+	MinLat       float64  `protobuf:"fixed64,7,opt,name=MinLat,proto3" json:"MinLat,omitempty"`
+	MinLon       float64  `protobuf:"fixed64,8,opt,name=MinLon,proto3" json:"MinLon,omitempty"`
+	MaxLat       float64  `protobuf:"fixed64,9,opt,name=MaxLat,proto3" json:"MaxLat,omitempty"`
+	MaxLon       float64  `protobuf:"fixed64,10,opt,name=MaxLon,proto3" json:"MaxLon,omitempty"`
+	CentroidLat  float64  `protobuf:"fixed64,11,opt,name=CentroidLat,proto3" json:"CentroidLat,omitempty"`
+	CentroidLon  float64  `protobuf:"fixed64,12,opt,name=CentroidLon,proto3" json:"CentroidLon,omitempty"`
+	States       []string `protobuf:"bytes,13,rep,name=States,proto3" json:"States,omitempty"`
+	Counties     []string `protobuf:"bytes,14,rep,name=Counties,proto3" json:"Counties,omitempty"`
+}
+
+func (x *EventMsg) Reset() {
+	*x = EventMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_report_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EventMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventMsg) ProtoMessage() {}
+
+func (x *EventMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_report_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventMsg.ProtoReflect.Descriptor instead.
+func (*EventMsg) Descriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *EventMsg) GetEventID() string {
+	if x != nil {
+		return x.EventID
+	}
+	return ""
+}
+
+func (x *EventMsg) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *EventMsg) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *EventMsg) GetMaxMagnitude() int32 {
 	if x != nil {
-		return x.Time
+		return x.MaxMagnitude
 	}
 	return 0
 }
 
-func (x *HailMsg) GetSize() int32 {
+func (x *EventMsg) GetFirstTime() int64 {
 	if x != nil {
-		return x.Size
+		return x.FirstTime
 	}
 	return 0
 }
 
-func (x *HailMsg) GetDistance() int32 {
+func (x *EventMsg) GetLastTime() int64 {
 	if x != nil {
-		return x.Distance
+		return x.LastTime
 	}
 	return 0
 }
 
-func (x *HailMsg) GetDirection() string {
+func (x *EventMsg) GetMinLat() float64 {
 	if x != nil {
-		return x.Direction
+		return x.MinLat
 	}
-	return ""
+	return 0
 }
 
-func (x *HailMsg) GetLocation() string {
+func (x *EventMsg) GetMinLon() float64 {
 	if x != nil {
-		return x.Location
+		return x.MinLon
 	}
-	return ""
+	return 0
 }
 
-func (x *HailMsg) GetCounty() string {
+func (x *EventMsg) GetMaxLat() float64 {
 	if x != nil {
-		return x.County
+		return x.MaxLat
 	}
-	return ""
+	return 0
 }
 
-func (x *HailMsg) GetState() string {
+func (x *EventMsg) GetMaxLon() float64 {
 	if x != nil {
-		return x.State
+		return x.MaxLon
 	}
-	return ""
+	return 0
 }
 
-func (x *HailMsg) GetLat() string {
+func (x *EventMsg) GetCentroidLat() float64 {
 	if x != nil {
-		return x.Lat
+		return x.CentroidLat
 	}
-	return ""
+	return 0
 }
 
-func (x *HailMsg) GetLon() string {
+func (x *EventMsg) GetCentroidLon() float64 {
 	if x != nil {
-		return x.Lon
+		return x.CentroidLon
 	}
-	return ""
+	return 0
 }
 
-func (x *HailMsg) GetRemarks() string {
+func (x *EventMsg) GetStates() []string {
 	if x != nil {
-		return x.Remarks
+		return x.States
 	}
-	return ""
+	return nil
 }
 
-func (x *HailMsg) GetType() string {
+func (x *EventMsg) GetCounties() []string {
 	if x != nil {
-		return x.Type
+		return x.Counties
 	}
-	return ""
+	return nil
 }
 
-type WindMsg struct {
+// AlertReport is a report that contributed to an alert.
+type AlertReport struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Time      int64  `protobuf:"varint,1,opt,name=Time,proto3" json:"Time,omitempty"`
-	Speed     int32  `protobuf:"varint,2,opt,name=Speed,proto3" json:"Speed,omitempty"`
-	Distance  int32  `protobuf:"varint,3,opt,name=Distance,proto3" json:"Distance,omitempty"`
-	Direction string `protobuf:"bytes,4,opt,name=Direction,proto3" json:"Direction,omitempty"`
-	Location  string `protobuf:"bytes,5,opt,name=Location,proto3" json:"Location,omitempty"`
-	County    string `protobuf:"bytes,6,opt,name=County,proto3" json:"County,omitempty"`
-	State     string `protobuf:"bytes,7,opt,name=State,proto3" json:"State,omitempty"`
-	Lat       string `protobuf:"bytes,8,opt,name=Lat,proto3" json:"Lat,omitempty"`
-	Lon       string `protobuf:"bytes,9,opt,name=Lon,proto3" json:"Lon,omitempty"`
+	Type      string `protobuf:"bytes,1,opt,name=Type,proto3" json:"Type,omitempty"`
+	Time      int64  `protobuf:"varint,2,opt,name=Time,proto3" json:"Time,omitempty"`
+	Magnitude int32  `protobuf:"varint,3,opt,name=Magnitude,proto3" json:"Magnitude,omitempty"`
+	Location  string `protobuf:"bytes,4,opt,name=Location,proto3" json:"Location,omitempty"`
+	County    string `protobuf:"bytes,5,opt,name=County,proto3" json:"County,omitempty"`
+	State     string `protobuf:"bytes,6,opt,name=State,proto3" json:"State,omitempty"`
+	Lat       string `protobuf:"bytes,7,opt,name=Lat,proto3" json:"Lat,omitempty"`
+	Lon       string `protobuf:"bytes,8,opt,name=Lon,proto3" json:"Lon,omitempty"`
+	EventID   string `protobuf:"bytes,9,opt,name=EventID,proto3" json:"EventID,omitempty"`
 	Remarks   string `protobuf:"bytes,10,opt,name=Remarks,proto3" json:"Remarks,omitempty"`
-	Type      string `protobuf:"bytes,11,opt,name=Type,proto3" json:"Type,omitempty"`
 }
 
-func (x *WindMsg) Reset() {
-	*x = WindMsg{}
+func (x *AlertReport) Reset() {
+	*x = AlertReport{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_report_proto_msgTypes[1]
+		mi := &file_report_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *WindMsg) String() string {
+func (x *AlertReport) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WindMsg) ProtoMessage() {}
+func (*AlertReport) ProtoMessage() {}
 
-func (x *WindMsg) ProtoReflect() protoreflect.Message {
-	mi := &file_report_proto_msgTypes[1]
+func (x *AlertReport) ProtoReflect() protoreflect.Message {
+	mi := &file_report_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -193,123 +2041,112 @@ func (x *WindMsg) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WindMsg.ProtoReflect.Descriptor instead.
-func (*WindMsg) Descriptor() ([]byte, []int) {
-	return file_report_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use AlertReport.ProtoReflect.Descriptor instead.
+func (*AlertReport) Descriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *WindMsg) GetTime() int64 {
+func (x *AlertReport) GetType() string {
 	if x != nil {
-		return x.Time
+		return x.Type
 	}
-	return 0
+	return ""
 }
 
-func (x *WindMsg) GetSpeed() int32 {
+func (x *AlertReport) GetTime() int64 {
 	if x != nil {
-		return x.Speed
+		return x.Time
 	}
 	return 0
 }
 
-func (x *WindMsg) GetDistance() int32 {
+func (x *AlertReport) GetMagnitude() int32 {
 	if x != nil {
-		return x.Distance
+		return x.Magnitude
 	}
 	return 0
 }
 
-func (x *WindMsg) GetDirection() string {
-	if x != nil {
-		return x.Direction
-	}
-	return ""
-}
-
-func (x *WindMsg) GetLocation() string {
+func (x *AlertReport) GetLocation() string {
 	if x != nil {
 		return x.Location
 	}
 	return ""
 }
 
-func (x *WindMsg) GetCounty() string {
+func (x *AlertReport) GetCounty() string {
 	if x != nil {
 		return x.County
 	}
 	return ""
 }
 
-func (x *WindMsg) GetState() string {
+func (x *AlertReport) GetState() string {
 	if x != nil {
 		return x.State
 	}
 	return ""
 }
 
-func (x *WindMsg) GetLat() string {
+func (x *AlertReport) GetLat() string {
 	if x != nil {
 		return x.Lat
 	}
 	return ""
 }
 
-func (x *WindMsg) GetLon() string {
+func (x *AlertReport) GetLon() string {
 	if x != nil {
 		return x.Lon
 	}
 	return ""
 }
 
-func (x *WindMsg) GetRemarks() string {
+func (x *AlertReport) GetEventID() string {
 	if x != nil {
-		return x.Remarks
+		return x.EventID
 	}
 	return ""
 }
 
-func (x *WindMsg) GetType() string {
+func (x *AlertReport) GetRemarks() string {
 	if x != nil {
-		return x.Type
+		return x.Remarks
 	}
 	return ""
 }
 
-type TornadoMsg struct {
+// Alert is raised when an alert rule's conditions are met by one or more reports.
+type Alert struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Time      int64  `protobuf:"varint,1,opt,name=Time,proto3" json:"Time,omitempty"`
-	F_Scale   int32  `protobuf:"varint,2,opt,name=F_Scale,json=FScale,proto3" json:"F_Scale,omitempty"`
-	Distance  int32  `protobuf:"varint,3,opt,name=Distance,proto3" json:"Distance,omitempty"`
-	Direction string `protobuf:"bytes,4,opt,name=Direction,proto3" json:"Direction,omitempty"`
-	Location  string `protobuf:"bytes,5,opt,name=Location,proto3" json:"Location,omitempty"`
-	County    string `protobuf:"bytes,6,opt,name=County,proto3" json:"County,omitempty"`
-	State     string `protobuf:"bytes,7,opt,name=State,proto3" json:"State,omitempty"`
-	Lat       string `protobuf:"bytes,8,opt,name=Lat,proto3" json:"Lat,omitempty"`
-	Lon       string `protobuf:"bytes,9,opt,name=Lon,proto3" json:"Lon,omitempty"`
-	Remarks   string `protobuf:"bytes,10,opt,name=Remarks,proto3" json:"Remarks,omitempty"`
-	Type      string `protobuf:"bytes,11,opt,name=Type,proto3" json:"Type,omitempty"`
+	AlertID     string         `protobuf:"bytes,1,opt,name=AlertID,proto3" json:"AlertID,omitempty"`
+	RuleID      string         `protobuf:"bytes,2,opt,name=RuleID,proto3" json:"RuleID,omitempty"`
+	Description string         `protobuf:"bytes,3,opt,name=Description,proto3" json:"Description,omitempty"`
+	Time        int64          `protobuf:"varint,4,opt,name=Time,proto3" json:"Time,omitempty"`
+	Group       string         `protobuf:"bytes,5,opt,name=Group,proto3" json:"Group,omitempty"`
+	Reports     []*AlertReport `protobuf:"bytes,6,rep,name=Reports,proto3" json:"Reports,omitempty"`
 }
 
-func (x *TornadoMsg) Reset() {
-	*x = TornadoMsg{}
+func (x *Alert) Reset() {
+	*x = Alert{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_report_proto_msgTypes[2]
+		mi := &file_report_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *TornadoMsg) String() string {
+func (x *Alert) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TornadoMsg) ProtoMessage() {}
+func (*Alert) ProtoMessage() {}
 
-func (x *TornadoMsg) ProtoReflect() protoreflect.Message {
-	mi := &file_report_proto_msgTypes[2]
+func (x *Alert) ProtoReflect() protoreflect.Message {
+	mi := &file_report_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -320,96 +2157,260 @@ func (x *TornadoMsg) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TornadoMsg.ProtoReflect.Descriptor instead.
-func (*TornadoMsg) Descriptor() ([]byte, []int) {
-	return file_report_proto_rawDescGZIP(), []int{2}
-}
-
-func (x *TornadoMsg) GetTime() int64 {
-	if x != nil {
-		return x.Time
-	}
-	return 0
-}
-
-func (x *TornadoMsg) GetF_Scale() int32 {
-	if x != nil {
-		return x.F_Scale
-	}
-	return 0
-}
-
-func (x *TornadoMsg) GetDistance() int32 {
-	if x != nil {
-		return x.Distance
-	}
-	return 0
-}
-
-func (x *TornadoMsg) GetDirection() string {
-	if x != nil {
-		return x.Direction
-	}
-	return ""
-}
-
-func (x *TornadoMsg) GetLocation() string {
-	if x != nil {
-		return x.Location
-	}
-	return ""
+// Deprecated: Use Alert.ProtoReflect.Descriptor instead.
+func (*Alert) Descriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *TornadoMsg) GetCounty() string {
+func (x *Alert) GetAlertID() string {
 	if x != nil {
-		return x.County
+		return x.AlertID
 	}
 	return ""
 }
 
-func (x *TornadoMsg) GetState() string {
+func (x *Alert) GetRuleID() string {
 	if x != nil {
-		return x.State
+		return x.RuleID
 	}
 	return ""
 }
 
-func (x *TornadoMsg) GetLat() string {
+func (x *Alert) GetDescription() string {
 	if x != nil {
-		return x.Lat
+		return x.Description
 	}
 	return ""
 }
 
-func (x *TornadoMsg) GetLon() string {
+func (x *Alert) GetTime() int64 {
 	if x != nil {
-		return x.Lon
+		return x.Time
 	}
-	return ""
+	return 0
 }
 
-func (x *TornadoMsg) GetRemarks() string {
+func (x *Alert) GetGroup() string {
 	if x != nil {
-		return x.Remarks
+		return x.Group
 	}
 	return ""
 }
 
-func (x *TornadoMsg) GetType() string {
+func (x *Alert) GetReports() []*AlertReport {
 	if x != nil {
-		return x.Type
+		return x.Reports
 	}
-	return ""
+	return nil
 }
 
 var File_report_proto protoreflect.FileDescriptor
 
 var file_report_proto_rawDesc = []byte{
 	0x0a, 0x0c, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x87, 0x02, 0x0a, 0x07, 0x48, 0x61, 0x69, 0x6c, 0x4d, 0x73,
-	0x67, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x04, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x04, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x44, 0x69, 0x73,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x74, 0x0a, 0x11, 0x49, 0x6e, 0x66, 0x65, 0x72, 0x72, 0x65,
+	0x64, 0x4d, 0x61, 0x67, 0x6e, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x12, 0x31, 0x0a, 0x0a, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x0a, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65,
+	0x6e, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0xfb, 0x01, 0x0a, 0x0b,
+	0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x4f,
+	0x66, 0x66, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x4f, 0x66, 0x66,
+	0x69, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x12, 0x16, 0x0a, 0x06, 0x44, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x06, 0x44, 0x61, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x49, 0x6e, 0x6a,
+	0x75, 0x72, 0x69, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x49, 0x6e, 0x6a,
+	0x75, 0x72, 0x69, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x49, 0x6e, 0x6a, 0x75, 0x72, 0x79, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x49, 0x6e, 0x6a, 0x75,
+	0x72, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x46, 0x61, 0x74, 0x61, 0x6c,
+	0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x46, 0x61, 0x74,
+	0x61, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x46, 0x61, 0x74, 0x61, 0x6c,
+	0x69, 0x74, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d,
+	0x46, 0x61, 0x74, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1a, 0x0a,
+	0x08, 0x53, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x08, 0x53, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xb8, 0x01, 0x0a, 0x06, 0x49, 0x6d,
+	0x70, 0x61, 0x63, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x49, 0x6e, 0x6a, 0x75, 0x72, 0x69, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x49, 0x6e, 0x6a, 0x75, 0x72, 0x69, 0x65, 0x73,
+	0x12, 0x1e, 0x0a, 0x0a, 0x46, 0x61, 0x74, 0x61, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x46, 0x61, 0x74, 0x61, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x12, 0x22, 0x0a, 0x0c, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x4c, 0x6f, 0x73, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79,
+	0x4c, 0x6f, 0x73, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x43, 0x72, 0x6f, 0x70, 0x4c, 0x6f, 0x73, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x43, 0x72, 0x6f, 0x70, 0x4c, 0x6f, 0x73, 0x73,
+	0x12, 0x32, 0x0a, 0x14, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x4c, 0x6f, 0x73, 0x73,
+	0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x14,
+	0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x4c, 0x6f, 0x73, 0x73, 0x43, 0x61, 0x74, 0x65,
+	0x67, 0x6f, 0x72, 0x79, 0x22, 0xcb, 0x01, 0x0a, 0x05, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x12, 0x16,
+	0x0a, 0x06, 0x45, 0x6e, 0x64, 0x4c, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x45, 0x6e, 0x64, 0x4c, 0x61, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x6e, 0x64, 0x4c, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x6e, 0x64, 0x4c, 0x6f, 0x6e, 0x12, 0x20,
+	0x0a, 0x0b, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x4d, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x0b, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x4d, 0x69, 0x6c, 0x65, 0x73,
+	0x12, 0x1e, 0x0a, 0x0a, 0x57, 0x69, 0x64, 0x74, 0x68, 0x59, 0x61, 0x72, 0x64, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x57, 0x69, 0x64, 0x74, 0x68, 0x59, 0x61, 0x72, 0x64, 0x73,
+	0x12, 0x16, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x06, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x53, 0x65, 0x67, 0x6d,
+	0x65, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x53, 0x65, 0x67, 0x6d, 0x65,
+	0x6e, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x46, 0x69, 0x70, 0x73,
+	0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x46, 0x69,
+	0x70, 0x73, 0x22, 0xbc, 0x04, 0x0a, 0x0a, 0x53, 0x74, 0x6f, 0x72, 0x6d, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x12, 0x1c, 0x0a, 0x09, 0x45, 0x70, 0x69, 0x73, 0x6f, 0x64, 0x65, 0x49, 0x44, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x45, 0x70, 0x69, 0x73, 0x6f, 0x64, 0x65, 0x49, 0x44, 0x12,
+	0x18, 0x0a, 0x07, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x07, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x45, 0x6e, 0x64, 0x54, 0x69,
+	0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x45, 0x6e, 0x64, 0x54, 0x69, 0x6d,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x54, 0x69, 0x6d, 0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x54, 0x69, 0x6d, 0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x5a, 0x6f, 0x6e, 0x65, 0x54, 0x79, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x5a, 0x6f, 0x6e, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x5a, 0x6f, 0x6e,
+	0x65, 0x46, 0x69, 0x70, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x5a, 0x6f, 0x6e,
+	0x65, 0x46, 0x69, 0x70, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x5a, 0x6f, 0x6e, 0x65, 0x4e, 0x61, 0x6d,
+	0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x5a, 0x6f, 0x6e, 0x65, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x2a, 0x0a, 0x10, 0x49, 0x6e, 0x6a, 0x75, 0x72, 0x69, 0x65, 0x73, 0x49, 0x6e, 0x64,
+	0x69, 0x72, 0x65, 0x63, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10, 0x49, 0x6e, 0x6a,
+	0x75, 0x72, 0x69, 0x65, 0x73, 0x49, 0x6e, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x12, 0x26, 0x0a,
+	0x0e, 0x44, 0x65, 0x61, 0x74, 0x68, 0x73, 0x49, 0x6e, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x44, 0x65, 0x61, 0x74, 0x68, 0x73, 0x49, 0x6e, 0x64,
+	0x69, 0x72, 0x65, 0x63, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18,
+	0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x20, 0x0a,
+	0x0b, 0x45, 0x6e, 0x64, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0b, 0x45, 0x6e, 0x64, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12,
+	0x22, 0x0a, 0x0c, 0x45, 0x6e, 0x64, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x45, 0x6e, 0x64, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x45, 0x6e, 0x64, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x45, 0x6e, 0x64, 0x4c, 0x6f, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x6e, 0x64, 0x4c, 0x61, 0x74, 0x18,
+	0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x6e, 0x64, 0x4c, 0x61, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x45, 0x6e, 0x64, 0x4c, 0x6f, 0x6e, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45,
+	0x6e, 0x64, 0x4c, 0x6f, 0x6e, 0x12, 0x2a, 0x0a, 0x10, 0x45, 0x70, 0x69, 0x73, 0x6f, 0x64, 0x65,
+	0x4e, 0x61, 0x72, 0x72, 0x61, 0x74, 0x69, 0x76, 0x65, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x10, 0x45, 0x70, 0x69, 0x73, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x72, 0x72, 0x61, 0x74, 0x69, 0x76,
+	0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x44, 0x61, 0x74, 0x61, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18,
+	0x12, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x44, 0x61, 0x74, 0x61, 0x53, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x22, 0xaa, 0x01, 0x0a, 0x10, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x53, 0x74, 0x6f, 0x72, 0x6d,
+	0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x4f, 0x66, 0x66, 0x69, 0x63, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x4f, 0x66, 0x66, 0x69, 0x63, 0x65, 0x12, 0x20,
+	0x0a, 0x0b, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0b, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x54, 0x69, 0x6d, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x12, 0x4d, 0x61, 0x67, 0x6e, 0x69, 0x74,
+	0x75, 0x64, 0x65, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x12, 0x4d, 0x61, 0x67, 0x6e, 0x69, 0x74, 0x75, 0x64, 0x65, 0x51, 0x75, 0x61,
+	0x6c, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0xa8,
+	0x06, 0x0a, 0x07, 0x48, 0x61, 0x69, 0x6c, 0x4d, 0x73, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x69,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x53, 0x69,
+	0x7a, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1c,
+	0x0a, 0x09, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08,
+	0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x79,
+	0x12, 0x14, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x4c, 0x61, 0x74, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x4c, 0x61, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x4c, 0x6f, 0x6e, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x4c, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x52, 0x65,
+	0x6d, 0x61, 0x72, 0x6b, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x52, 0x65, 0x6d,
+	0x61, 0x72, 0x6b, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x79, 0x5f, 0x66, 0x69, 0x70, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x79, 0x46, 0x69, 0x70, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x5f, 0x66, 0x69, 0x70, 0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x46, 0x69, 0x70, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x77, 0x61, 0x18,
+	0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x63, 0x77, 0x61, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x5f, 0x6d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0d, 0x73, 0x74, 0x61, 0x74, 0x65, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63,
+	0x68, 0x12, 0x2b, 0x0a, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x18, 0x10, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x65, 0x76, 0x65,
+	0x72, 0x69, 0x74, 0x79, 0x52, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x12, 0x20,
+	0x0a, 0x0b, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x66, 0x69, 0x63, 0x61, 0x6e, 0x74, 0x18, 0x11, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0b, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x66, 0x69, 0x63, 0x61, 0x6e, 0x74,
+	0x12, 0x19, 0x0a, 0x08, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x12, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x35, 0x0a, 0x0c, 0x72,
+	0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x13, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b,
+	0x73, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0b, 0x72, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x49, 0x6e,
+	0x66, 0x6f, 0x12, 0x47, 0x0a, 0x12, 0x69, 0x6e, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x5f, 0x6d,
+	0x61, 0x67, 0x6e, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x6e, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x4d,
+	0x61, 0x67, 0x6e, 0x69, 0x74, 0x75, 0x64, 0x65, 0x52, 0x11, 0x69, 0x6e, 0x66, 0x65, 0x72, 0x72,
+	0x65, 0x64, 0x4d, 0x61, 0x67, 0x6e, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x71,
+	0x63, 0x5f, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x71,
+	0x63, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x71, 0x63, 0x5f, 0x69, 0x73, 0x73,
+	0x75, 0x65, 0x73, 0x18, 0x16, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x71, 0x63, 0x49, 0x73, 0x73,
+	0x75, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x06, 0x69, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x18, 0x17, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x6d, 0x70, 0x61,
+	0x63, 0x74, 0x52, 0x06, 0x69, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x12, 0x32, 0x0a, 0x0b, 0x73, 0x74,
+	0x6f, 0x72, 0x6d, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x18, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x6d, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x52, 0x0a, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x29,
+	0x0a, 0x03, 0x6c, 0x73, 0x72, 0x18, 0x19, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x53, 0x74, 0x6f, 0x72, 0x6d, 0x52, 0x65,
+	0x70, 0x6f, 0x72, 0x74, 0x52, 0x03, 0x6c, 0x73, 0x72, 0x22, 0xd1, 0x06, 0x0a, 0x07, 0x57, 0x69,
+	0x6e, 0x64, 0x4d, 0x73, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x04, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x53, 0x70, 0x65,
+	0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12,
+	0x1a, 0x0a, 0x08, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x08, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x44,
+	0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x4c, 0x6f, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x4c, 0x6f, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x4c, 0x61, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x4c, 0x61, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x4c, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x4c, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x52, 0x65, 0x6d, 0x61, 0x72,
+	0x6b, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b,
+	0x73, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x5f,
+	0x66, 0x69, 0x70, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x79, 0x46, 0x69, 0x70, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f,
+	0x66, 0x69, 0x70, 0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x46, 0x69, 0x70, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x77, 0x61, 0x18, 0x0e, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x63, 0x77, 0x61, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x5f, 0x6d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0d, 0x73, 0x74, 0x61, 0x74, 0x65, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x2b,
+	0x0a, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74,
+	0x79, 0x52, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x73,
+	0x69, 0x67, 0x6e, 0x69, 0x66, 0x69, 0x63, 0x61, 0x6e, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0b, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x66, 0x69, 0x63, 0x61, 0x6e, 0x74, 0x12, 0x19, 0x0a,
+	0x08, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x12, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x35, 0x0a, 0x0c, 0x72, 0x65, 0x6d, 0x61,
+	0x72, 0x6b, 0x73, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x0b, 0x72, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x12,
+	0x47, 0x0a, 0x12, 0x69, 0x6e, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x5f, 0x6d, 0x61, 0x67, 0x6e,
+	0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x6e, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x4d, 0x61, 0x67, 0x6e,
+	0x69, 0x74, 0x75, 0x64, 0x65, 0x52, 0x11, 0x69, 0x6e, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x4d,
+	0x61, 0x67, 0x6e, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x71, 0x63, 0x5f, 0x66,
+	0x6c, 0x61, 0x67, 0x73, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x71, 0x63, 0x46, 0x6c,
+	0x61, 0x67, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x71, 0x63, 0x5f, 0x69, 0x73, 0x73, 0x75, 0x65, 0x73,
+	0x18, 0x16, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x71, 0x63, 0x49, 0x73, 0x73, 0x75, 0x65, 0x73,
+	0x12, 0x25, 0x0a, 0x06, 0x69, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x18, 0x17, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x52,
+	0x06, 0x69, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x6d, 0x61, 0x67, 0x6e, 0x69,
+	0x74, 0x75, 0x64, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x18, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x6d, 0x61, 0x67, 0x6e, 0x69, 0x74, 0x75, 0x64, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x32,
+	0x0a, 0x0b, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x19, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x6f, 0x72,
+	0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x0a, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x12, 0x29, 0x0a, 0x03, 0x6c, 0x73, 0x72, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x53, 0x74, 0x6f,
+	0x72, 0x6d, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x03, 0x6c, 0x73, 0x72, 0x22, 0xd4, 0x06,
+	0x0a, 0x0a, 0x54, 0x6f, 0x72, 0x6e, 0x61, 0x64, 0x6f, 0x4d, 0x73, 0x67, 0x12, 0x12, 0x0a, 0x04,
+	0x54, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x54, 0x69, 0x6d, 0x65,
+	0x12, 0x17, 0x0a, 0x07, 0x46, 0x5f, 0x53, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x06, 0x46, 0x53, 0x63, 0x61, 0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x44, 0x69, 0x73,
 	0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x44, 0x69, 0x73,
 	0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69,
 	0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74,
@@ -422,44 +2423,169 @@ var file_report_proto_rawDesc = []byte{
 	0x10, 0x0a, 0x03, 0x4c, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x4c, 0x6f,
 	0x6e, 0x12, 0x18, 0x0a, 0x07, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x18, 0x0a, 0x20, 0x01,
 	0x28, 0x09, 0x52, 0x07, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x54,
-	0x79, 0x70, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x22,
-	0x89, 0x02, 0x0a, 0x07, 0x57, 0x69, 0x6e, 0x64, 0x4d, 0x73, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x54,
-	0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x54, 0x69, 0x6d, 0x65, 0x12,
-	0x14, 0x0a, 0x05, 0x53, 0x70, 0x65, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
-	0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63,
-	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63,
-	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
-	0x1a, 0x0a, 0x08, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x08, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x43,
-	0x6f, 0x75, 0x6e, 0x74, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x43, 0x6f, 0x75,
-	0x6e, 0x74, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x4c, 0x61, 0x74,
-	0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x4c, 0x61, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x4c,
-	0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x4c, 0x6f, 0x6e, 0x12, 0x18, 0x0a,
-	0x07, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
-	0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18,
-	0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x22, 0x8f, 0x02, 0x0a, 0x0a,
-	0x54, 0x6f, 0x72, 0x6e, 0x61, 0x64, 0x6f, 0x4d, 0x73, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x69,
-	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x17,
-	0x0a, 0x07, 0x46, 0x5f, 0x53, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
-	0x06, 0x46, 0x53, 0x63, 0x61, 0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x44, 0x69, 0x73, 0x74, 0x61,
-	0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x44, 0x69, 0x73, 0x74, 0x61,
-	0x6e, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x08, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a,
-	0x06, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x43,
-	0x6f, 0x75, 0x6e, 0x74, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x18, 0x07,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x4c,
-	0x61, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x4c, 0x61, 0x74, 0x12, 0x10, 0x0a,
-	0x03, 0x4c, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x4c, 0x6f, 0x6e, 0x12,
-	0x18, 0x0a, 0x07, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x07, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x79, 0x70,
-	0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x42, 0x23, 0x5a,
-	0x21, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6a, 0x61, 0x73, 0x6f,
-	0x6e, 0x2d, 0x63, 0x6f, 0x73, 0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x2f, 0x77, 0x65, 0x61, 0x74, 0x68,
-	0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x79, 0x70, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x1f, 0x0a, 0x0b, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x5f, 0x66, 0x69, 0x70, 0x73, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x46, 0x69, 0x70, 0x73,
+	0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x66, 0x69, 0x70, 0x73, 0x18, 0x0d,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x61, 0x74, 0x65, 0x46, 0x69, 0x70, 0x73, 0x12,
+	0x10, 0x0a, 0x03, 0x63, 0x77, 0x61, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x63, 0x77,
+	0x61, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x6d, 0x69, 0x73, 0x6d, 0x61,
+	0x74, 0x63, 0x68, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x2b, 0x0a, 0x08, 0x73, 0x65, 0x76, 0x65,
+	0x72, 0x69, 0x74, 0x79, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x53, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x52, 0x08, 0x73, 0x65, 0x76,
+	0x65, 0x72, 0x69, 0x74, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x66, 0x69,
+	0x63, 0x61, 0x6e, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x73, 0x69, 0x67, 0x6e,
+	0x69, 0x66, 0x69, 0x63, 0x61, 0x6e, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x5f, 0x69, 0x64, 0x18, 0x12, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x49, 0x64, 0x12, 0x35, 0x0a, 0x0c, 0x72, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x5f, 0x69, 0x6e,
+	0x66, 0x6f, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x52, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0b, 0x72, 0x65,
+	0x6d, 0x61, 0x72, 0x6b, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x47, 0x0a, 0x12, 0x69, 0x6e, 0x66,
+	0x65, 0x72, 0x72, 0x65, 0x64, 0x5f, 0x6d, 0x61, 0x67, 0x6e, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18,
+	0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x6e,
+	0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x4d, 0x61, 0x67, 0x6e, 0x69, 0x74, 0x75, 0x64, 0x65, 0x52,
+	0x11, 0x69, 0x6e, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x4d, 0x61, 0x67, 0x6e, 0x69, 0x74, 0x75,
+	0x64, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x71, 0x63, 0x5f, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x15,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x71, 0x63, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x1b, 0x0a,
+	0x09, 0x71, 0x63, 0x5f, 0x69, 0x73, 0x73, 0x75, 0x65, 0x73, 0x18, 0x16, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x08, 0x71, 0x63, 0x49, 0x73, 0x73, 0x75, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x06, 0x69, 0x6d,
+	0x70, 0x61, 0x63, 0x74, 0x18, 0x17, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x49, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x52, 0x06, 0x69, 0x6d, 0x70, 0x61, 0x63,
+	0x74, 0x12, 0x22, 0x0a, 0x05, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x18, 0x18, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x52, 0x05,
+	0x74, 0x72, 0x61, 0x63, 0x6b, 0x12, 0x32, 0x0a, 0x0b, 0x73, 0x74, 0x6f, 0x72, 0x6d, 0x5f, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x18, 0x19, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x0a, 0x73,
+	0x74, 0x6f, 0x72, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x03, 0x6c, 0x73, 0x72,
+	0x18, 0x1a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c,
+	0x6f, 0x63, 0x61, 0x6c, 0x53, 0x74, 0x6f, 0x72, 0x6d, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52,
+	0x03, 0x6c, 0x73, 0x72, 0x22, 0xba, 0x04, 0x0a, 0x06, 0x4c, 0x53, 0x52, 0x4d, 0x73, 0x67, 0x12,
+	0x12, 0x0a, 0x04, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x54,
+	0x69, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x4d, 0x61, 0x67, 0x6e, 0x69, 0x74, 0x75, 0x64, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x4d, 0x61, 0x67, 0x6e, 0x69, 0x74, 0x75, 0x64,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x08, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1c, 0x0a,
+	0x09, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x4c,
+	0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x4c,
+	0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x4c, 0x61, 0x74, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x4c, 0x61, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x4c, 0x6f, 0x6e, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x4c, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x52, 0x65, 0x6d,
+	0x61, 0x72, 0x6b, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x52, 0x65, 0x6d, 0x61,
+	0x72, 0x6b, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x79, 0x5f, 0x66, 0x69, 0x70, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x79, 0x46, 0x69, 0x70, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x5f, 0x66, 0x69, 0x70, 0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x46, 0x69, 0x70, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x77, 0x61, 0x18, 0x0e,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x63, 0x77, 0x61, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x5f, 0x6d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x0f, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0d, 0x73, 0x74, 0x61, 0x74, 0x65, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74, 0x63, 0x68,
+	0x12, 0x19, 0x0a, 0x08, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x10, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x71,
+	0x63, 0x5f, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x71,
+	0x63, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x71, 0x63, 0x5f, 0x69, 0x73, 0x73,
+	0x75, 0x65, 0x73, 0x18, 0x12, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x71, 0x63, 0x49, 0x73, 0x73,
+	0x75, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x13, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x14, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x12, 0x29, 0x0a, 0x03, 0x6c, 0x73, 0x72, 0x18, 0x15, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x6f, 0x63, 0x61,
+	0x6c, 0x53, 0x74, 0x6f, 0x72, 0x6d, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x03, 0x6c, 0x73,
+	0x72, 0x22, 0xe2, 0x02, 0x0a, 0x0a, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x4d, 0x73, 0x67,
+	0x12, 0x14, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x12, 0x12,
+	0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x53, 0x74, 0x61, 0x72,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x53,
+	0x74, 0x61, 0x72, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x45, 0x6e,
+	0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x45,
+	0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x4d, 0x61, 0x78, 0x4d,
+	0x61, 0x67, 0x6e, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c,
+	0x4d, 0x61, 0x78, 0x4d, 0x61, 0x67, 0x6e, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x1c, 0x0a, 0x09,
+	0x46, 0x69, 0x72, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x09, 0x46, 0x69, 0x72, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x4c, 0x61,
+	0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x4c, 0x61,
+	0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x4d, 0x69, 0x6e, 0x4c, 0x61, 0x74,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x4d, 0x69, 0x6e, 0x4c, 0x61, 0x74, 0x12, 0x16,
+	0x0a, 0x06, 0x4d, 0x69, 0x6e, 0x4c, 0x6f, 0x6e, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06,
+	0x4d, 0x69, 0x6e, 0x4c, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x4d, 0x61, 0x78, 0x4c, 0x61, 0x74,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x4d, 0x61, 0x78, 0x4c, 0x61, 0x74, 0x12, 0x16,
+	0x0a, 0x06, 0x4d, 0x61, 0x78, 0x4c, 0x6f, 0x6e, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06,
+	0x4d, 0x61, 0x78, 0x4c, 0x6f, 0x6e, 0x22, 0x84, 0x03, 0x0a, 0x08, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x4d, 0x73, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x12, 0x12, 0x0a,
+	0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x4d, 0x61, 0x78, 0x4d, 0x61,
+	0x67, 0x6e, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x4d,
+	0x61, 0x78, 0x4d, 0x61, 0x67, 0x6e, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x46,
+	0x69, 0x72, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
+	0x46, 0x69, 0x72, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x4c, 0x61, 0x73,
+	0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x4c, 0x61, 0x73,
+	0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x4d, 0x69, 0x6e, 0x4c, 0x61, 0x74, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x4d, 0x69, 0x6e, 0x4c, 0x61, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x4d, 0x69, 0x6e, 0x4c, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x4d,
+	0x69, 0x6e, 0x4c, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x4d, 0x61, 0x78, 0x4c, 0x61, 0x74, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x4d, 0x61, 0x78, 0x4c, 0x61, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x4d, 0x61, 0x78, 0x4c, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x4d,
+	0x61, 0x78, 0x4c, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x43, 0x65, 0x6e, 0x74, 0x72, 0x6f, 0x69,
+	0x64, 0x4c, 0x61, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x43, 0x65, 0x6e, 0x74,
+	0x72, 0x6f, 0x69, 0x64, 0x4c, 0x61, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x43, 0x65, 0x6e, 0x74, 0x72,
+	0x6f, 0x69, 0x64, 0x4c, 0x6f, 0x6e, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x43, 0x65,
+	0x6e, 0x74, 0x72, 0x6f, 0x69, 0x64, 0x4c, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x69, 0x65, 0x73, 0x18, 0x0e, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x08, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x69, 0x65, 0x73, 0x22, 0xf5, 0x01,
+	0x0a, 0x0b, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x04, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x4d, 0x61, 0x67, 0x6e, 0x69, 0x74, 0x75,
+	0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x4d, 0x61, 0x67, 0x6e, 0x69, 0x74,
+	0x75, 0x64, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x16, 0x0a, 0x06, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x10, 0x0a,
+	0x03, 0x4c, 0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x4c, 0x61, 0x74, 0x12,
+	0x10, 0x0a, 0x03, 0x4c, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x4c, 0x6f,
+	0x6e, 0x12, 0x18, 0x0a, 0x07, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x52,
+	0x65, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x52, 0x65,
+	0x6d, 0x61, 0x72, 0x6b, 0x73, 0x22, 0xb3, 0x01, 0x0a, 0x05, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x12,
+	0x18, 0x0a, 0x07, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x52, 0x75, 0x6c,
+	0x65, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x52, 0x75, 0x6c, 0x65, 0x49,
+	0x44, 0x12, 0x20, 0x0a, 0x0b, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x04, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x2c, 0x0a,
+	0x07, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x52, 0x65, 0x70, 0x6f,
+	0x72, 0x74, 0x52, 0x07, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x2a, 0x68, 0x0a, 0x08, 0x53,
+	0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x12, 0x14, 0x0a, 0x10, 0x53, 0x45, 0x56, 0x45, 0x52,
+	0x49, 0x54, 0x59, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x17, 0x0a,
+	0x13, 0x53, 0x45, 0x56, 0x45, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x53, 0x55, 0x42, 0x5f, 0x53, 0x45,
+	0x56, 0x45, 0x52, 0x45, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x45, 0x56, 0x45, 0x52, 0x49,
+	0x54, 0x59, 0x5f, 0x53, 0x45, 0x56, 0x45, 0x52, 0x45, 0x10, 0x02, 0x12, 0x18, 0x0a, 0x14, 0x53,
+	0x45, 0x56, 0x45, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x53, 0x49, 0x47, 0x4e, 0x49, 0x46, 0x49, 0x43,
+	0x41, 0x4e, 0x54, 0x10, 0x03, 0x2a, 0x61, 0x0a, 0x0a, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x64, 0x65,
+	0x6e, 0x63, 0x65, 0x12, 0x13, 0x0a, 0x0f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x44, 0x45, 0x4e, 0x43,
+	0x45, 0x5f, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x43, 0x4f, 0x4e, 0x46,
+	0x49, 0x44, 0x45, 0x4e, 0x43, 0x45, 0x5f, 0x4c, 0x4f, 0x57, 0x10, 0x01, 0x12, 0x15, 0x0a, 0x11,
+	0x43, 0x4f, 0x4e, 0x46, 0x49, 0x44, 0x45, 0x4e, 0x43, 0x45, 0x5f, 0x4d, 0x45, 0x44, 0x49, 0x55,
+	0x4d, 0x10, 0x02, 0x12, 0x13, 0x0a, 0x0f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x44, 0x45, 0x4e, 0x43,
+	0x45, 0x5f, 0x48, 0x49, 0x47, 0x48, 0x10, 0x03, 0x42, 0x23, 0x5a, 0x21, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6a, 0x61, 0x73, 0x6f, 0x6e, 0x2d, 0x63, 0x6f, 0x73,
+	0x74, 0x65, 0x6c, 0x6c, 0x6f, 0x2f, 0x77, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -474,18 +2600,54 @@ func file_report_proto_rawDescGZIP() []byte {
 	return file_report_proto_rawDescData
 }
 
-var file_report_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_report_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_report_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_report_proto_goTypes = []interface{}{
-	(*HailMsg)(nil),    // 0: proto.HailMsg
-	(*WindMsg)(nil),    // 1: proto.WindMsg
-	(*TornadoMsg)(nil), // 2: proto.TornadoMsg
+	(Severity)(0),             // 0: proto.Severity
+	(Confidence)(0),           // 1: proto.Confidence
+	(*InferredMagnitude)(nil), // 2: proto.InferredMagnitude
+	(*RemarksInfo)(nil),       // 3: proto.RemarksInfo
+	(*Impact)(nil),            // 4: proto.Impact
+	(*Track)(nil),             // 5: proto.Track
+	(*StormEvent)(nil),        // 6: proto.StormEvent
+	(*LocalStormReport)(nil),  // 7: proto.LocalStormReport
+	(*HailMsg)(nil),           // 8: proto.HailMsg
+	(*WindMsg)(nil),           // 9: proto.WindMsg
+	(*TornadoMsg)(nil),        // 10: proto.TornadoMsg
+	(*LSRMsg)(nil),            // 11: proto.LSRMsg
+	(*SummaryMsg)(nil),        // 12: proto.SummaryMsg
+	(*EventMsg)(nil),          // 13: proto.EventMsg
+	(*AlertReport)(nil),       // 14: proto.AlertReport
+	(*Alert)(nil),             // 15: proto.Alert
 }
 var file_report_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	1,  // 0: proto.InferredMagnitude.Confidence:type_name -> proto.Confidence
+	0,  // 1: proto.HailMsg.severity:type_name -> proto.Severity
+	3,  // 2: proto.HailMsg.remarks_info:type_name -> proto.RemarksInfo
+	2,  // 3: proto.HailMsg.inferred_magnitude:type_name -> proto.InferredMagnitude
+	4,  // 4: proto.HailMsg.impact:type_name -> proto.Impact
+	6,  // 5: proto.HailMsg.storm_event:type_name -> proto.StormEvent
+	7,  // 6: proto.HailMsg.lsr:type_name -> proto.LocalStormReport
+	0,  // 7: proto.WindMsg.severity:type_name -> proto.Severity
+	3,  // 8: proto.WindMsg.remarks_info:type_name -> proto.RemarksInfo
+	2,  // 9: proto.WindMsg.inferred_magnitude:type_name -> proto.InferredMagnitude
+	4,  // 10: proto.WindMsg.impact:type_name -> proto.Impact
+	6,  // 11: proto.WindMsg.storm_event:type_name -> proto.StormEvent
+	7,  // 12: proto.WindMsg.lsr:type_name -> proto.LocalStormReport
+	0,  // 13: proto.TornadoMsg.severity:type_name -> proto.Severity
+	3,  // 14: proto.TornadoMsg.remarks_info:type_name -> proto.RemarksInfo
+	2,  // 15: proto.TornadoMsg.inferred_magnitude:type_name -> proto.InferredMagnitude
+	4,  // 16: proto.TornadoMsg.impact:type_name -> proto.Impact
+	5,  // 17: proto.TornadoMsg.track:type_name -> proto.Track
+	6,  // 18: proto.TornadoMsg.storm_event:type_name -> proto.StormEvent
+	7,  // 19: proto.TornadoMsg.lsr:type_name -> proto.LocalStormReport
+	7,  // 20: proto.LSRMsg.lsr:type_name -> proto.LocalStormReport
+	14, // 21: proto.Alert.Reports:type_name -> proto.AlertReport
+	22, // [22:22] is the sub-list for method output_type
+	22, // [22:22] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
 }
 
 func init() { file_report_proto_init() }
@@ -495,7 +2657,7 @@ func file_report_proto_init() {
 	}
 	if !protoimpl.UnsafeEnabled {
 		file_report_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HailMsg); i {
+			switch v := v.(*InferredMagnitude); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -507,7 +2669,7 @@ func file_report_proto_init() {
 			}
 		}
 		file_report_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WindMsg); i {
+			switch v := v.(*RemarksInfo); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -519,6 +2681,78 @@ func file_report_proto_init() {
 			}
 		}
 		file_report_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Impact); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_report_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Track); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_report_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StormEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_report_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LocalStormReport); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_report_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HailMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_report_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WindMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_report_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*TornadoMsg); i {
 			case 0:
 				return &v.state
@@ -530,19 +2764,80 @@ func file_report_proto_init() {
 				return nil
 			}
 		}
+		file_report_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LSRMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_report_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SummaryMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_report_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EventMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_report_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AlertReport); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_report_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Alert); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_report_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   3,
+			NumEnums:      2,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_report_proto_goTypes,
 		DependencyIndexes: file_report_proto_depIdxs,
+		EnumInfos:         file_report_proto_enumTypes,
 		MessageInfos:      file_report_proto_msgTypes,
 	}.Build()
 	File_report_proto = out.File
@@ -0,0 +1,179 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// messageWriter is the part of kafka.Writer a KProvider uses.
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// Delivery is the pending result of a message written with WriteMessageAsync.
+type Delivery struct {
+	Payload WriterPayload
+
+	msg   kafka.Message
+	flush bool // marks a flush request rather than a message
+	done  chan struct{}
+	err   error
+}
+
+func newDelivery(wp WriterPayload, msg kafka.Message) *Delivery {
+	return &Delivery{Payload: wp, msg: msg, done: make(chan struct{})}
+}
+
+// Done returns a channel closed once the message is delivered or has failed.
+func (d *Delivery) Done() <-chan struct{} {
+	return d.done
+}
+
+// Err returns the delivery error, or nil if the message was delivered.
+// It must only be called once Done is closed.
+func (d *Delivery) Err() error {
+	return d.err
+}
+
+// Wait blocks until the message is delivered or has failed and returns the
+// delivery error. It returns the context's error if the context is done first.
+func (d *Delivery) Wait(ctx context.Context) error {
+	select {
+	case <-d.done:
+		return d.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *Delivery) complete(err error) {
+	d.err = err
+	close(d.done)
+}
+
+// asyncQueue is the bounded queue between WriteMessage and the writer goroutine.
+type asyncQueue struct {
+	mu      sync.RWMutex // held for writing only while closing the channel
+	closed  bool
+	ch      chan *Delivery
+	stopped chan struct{}
+}
+
+func newAsyncQueue(size int) *asyncQueue {
+	return &asyncQueue{
+		ch:      make(chan *Delivery, size),
+		stopped: make(chan struct{}),
+	}
+}
+
+// enqueue adds the delivery to the queue, blocking while it is full.
+func (q *asyncQueue) enqueue(ctx context.Context, d *Delivery) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return ErrClosed
+	}
+	select {
+	case q.ch <- d:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// flush returns once every message queued before it has been delivered.
+func (q *asyncQueue) flush(ctx context.Context) error {
+	marker := &Delivery{flush: true, done: make(chan struct{})}
+	if err := q.enqueue(ctx, marker); err != nil {
+		return err
+	}
+	return marker.Wait(ctx)
+}
+
+// close stops the queue from accepting messages. The writer goroutine delivers
+// what is left and then closes stopped. A blocked enqueue holds the read lock, so
+// a caller with a context that never ends can delay close until the queue drains.
+func (q *asyncQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.closed {
+		q.closed = true
+		close(q.ch)
+	}
+}
+
+// run delivers queued messages in batches of up to BatchSize, sending a partial
+// batch once its first message has waited Linger, until the queue is closed.
+func (p *KProvider) run() {
+	q := p.queue
+	defer close(q.stopped)
+
+	var batch []*Delivery
+	var linger <-chan time.Time
+	send := func() {
+		p.deliver(batch)
+		batch, linger = nil, nil
+	}
+	for {
+		select {
+		case d, ok := <-q.ch:
+			if !ok {
+				send()
+				return
+			}
+			if d.flush {
+				send()
+				d.complete(nil)
+				continue
+			}
+			batch = append(batch, d)
+			if len(batch) == 1 {
+				linger = time.After(p.config.Linger)
+			}
+			if len(batch) >= p.config.BatchSize {
+				send()
+			}
+		case <-linger:
+			send()
+		}
+	}
+}
+
+// deliver writes the batch and completes each delivery with its result.
+func (p *KProvider) deliver(batch []*Delivery) {
+	if len(batch) == 0 {
+		return
+	}
+	msgs := make([]kafka.Message, len(batch))
+	for i, d := range batch {
+		msgs[i] = d.msg
+	}
+	err := p.writer.WriteMessages(context.Background(), msgs...)
+
+	// kafka-go reports which messages of a batch failed with WriteErrors, indexed
+	// like the messages; any other error applies to the whole batch
+	var writeErrs kafka.WriteErrors
+	perMessage := errors.As(err, &writeErrs) && len(writeErrs) == len(batch)
+	for i, d := range batch {
+		msgErr := err
+		if perMessage {
+			msgErr = writeErrs[i]
+		}
+		if msgErr != nil {
+			// the body stays out of the error and logs, it is binary and makes every failure unique
+			p.logger.Debug("WriteMessages failed", "type", d.Payload.Type, "key", string(d.Payload.Key), "size", len(d.Payload.Body), "error", msgErr)
+			msgErr = fmt.Errorf("failed to write message to topic %s: %w", p.Topic, msgErr)
+		}
+		d.complete(msgErr)
+		if p.onDelivery != nil {
+			p.onDelivery(d.Payload, msgErr)
+		}
+	}
+	p.logger.Debug("batch written", "topic", p.Topic, "messages", len(batch))
+}
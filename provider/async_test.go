@@ -0,0 +1,258 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWriter records the batches written, failing the messages whose value is in fail.
+type fakeWriter struct {
+	mu      sync.Mutex
+	batches [][]kafka.Message
+	fail    map[string]bool
+	err     error
+	block   chan struct{}
+	closed  bool
+}
+
+func (w *fakeWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	if w.block != nil {
+		<-w.block
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.batches = append(w.batches, msgs)
+	if w.err != nil {
+		return w.err
+	}
+	var errs kafka.WriteErrors
+	for _, m := range msgs {
+		var err error
+		if w.fail[string(m.Value)] {
+			err = errors.New("message too large")
+		}
+		errs = append(errs, err)
+	}
+	if errs.Count() > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (w *fakeWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	return nil
+}
+
+func (w *fakeWriter) sizes() []int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var sizes []int
+	for _, b := range w.batches {
+		sizes = append(sizes, len(b))
+	}
+	return sizes
+}
+
+func newTestProvider(w *fakeWriter, cfg WriterConfig, opts ...Option) *KProvider {
+	p := &KProvider{Topic: "test", logger: logger, writer: w, config: cfg}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if cfg.Async {
+		p.queue = newAsyncQueue(cfg.QueueSize)
+		go p.run()
+	}
+	return p
+}
+
+func asyncConfig(batchSize int, linger time.Duration) WriterConfig {
+	cfg := DefaultWriterConfig()
+	cfg.Async = true
+	cfg.BatchSize = batchSize
+	cfg.Linger = linger
+	return cfg
+}
+
+func payload(body string) WriterPayload {
+	return WriterPayload{Type: "Hail", Body: []byte(body)}
+}
+
+func TestKProvider_Async(t *testing.T) {
+	ctx := context.Background()
+	w := &fakeWriter{fail: map[string]bool{"bad": true}}
+
+	var mu sync.Mutex
+	delivered := map[string]error{}
+	p := newTestProvider(w, asyncConfig(2, time.Hour), WithDeliveryCallback(func(wp WriterPayload, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		delivered[string(wp.Body)] = err
+	}))
+
+	good, err := p.WriteMessageAsync(ctx, payload("good"))
+	require.NoError(t, err)
+	bad, err := p.WriteMessageAsync(ctx, payload("bad"))
+	require.NoError(t, err)
+
+	// the batch is full, so it is sent without waiting for the linger
+	require.NoError(t, good.Wait(ctx))
+	require.Error(t, bad.Wait(ctx))
+	assert.EqualError(t, bad.Err(), "failed to write message to topic test: message too large", "the body is kept out of the error")
+
+	// a partial batch is sent on flush
+	require.NoError(t, p.WriteMessage(ctx, payload("last")))
+	require.NoError(t, p.Flush(ctx))
+	assert.Equal(t, []int{2, 1}, w.sizes())
+
+	mu.Lock()
+	assert.Len(t, delivered, 3)
+	assert.NoError(t, delivered["good"])
+	assert.Error(t, delivered["bad"])
+	assert.NoError(t, delivered["last"])
+	mu.Unlock()
+
+	require.NoError(t, p.Close(ctx))
+	assert.True(t, w.closed)
+	_, err = p.WriteMessageAsync(ctx, payload("late"))
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestKProvider_AsyncLinger(t *testing.T) {
+	ctx := context.Background()
+	w := &fakeWriter{}
+	p := newTestProvider(w, asyncConfig(100, 10*time.Millisecond))
+
+	d, err := p.WriteMessageAsync(ctx, payload("a"))
+	require.NoError(t, err)
+	waitCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	require.NoError(t, d.Wait(waitCtx))
+	assert.Equal(t, []int{1}, w.sizes())
+	require.NoError(t, p.Close(ctx))
+}
+
+func TestKProvider_AsyncBatchError(t *testing.T) {
+	ctx := context.Background()
+	w := &fakeWriter{err: errors.New("broker unavailable")}
+	p := newTestProvider(w, asyncConfig(2, time.Hour))
+
+	a, err := p.WriteMessageAsync(ctx, payload("a"))
+	require.NoError(t, err)
+	b, err := p.WriteMessageAsync(ctx, payload("b"))
+	require.NoError(t, err)
+	assert.ErrorContains(t, a.Wait(ctx), "broker unavailable")
+	assert.ErrorContains(t, b.Wait(ctx), "broker unavailable")
+	require.NoError(t, p.Close(ctx))
+}
+
+func TestKProvider_AsyncQueueFull(t *testing.T) {
+	w := &fakeWriter{block: make(chan struct{})}
+	cfg := asyncConfig(1, time.Hour)
+	cfg.QueueSize = 1
+	p := newTestProvider(w, cfg)
+
+	ctx := context.Background()
+	// the first message is taken by the blocked writer and the second fills the queue
+	require.NoError(t, p.WriteMessage(ctx, payload("a")))
+	require.Eventually(t, func() bool { return len(p.queue.ch) == 0 }, time.Second, time.Millisecond)
+	require.NoError(t, p.WriteMessage(ctx, payload("b")))
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	err := p.WriteMessage(timeout, payload("c"))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(w.block)
+	require.NoError(t, p.Close(ctx))
+	assert.Equal(t, []int{1, 1}, w.sizes())
+}
+
+func TestKProvider_Sync(t *testing.T) {
+	ctx := context.Background()
+	w := &fakeWriter{fail: map[string]bool{"bad": true}}
+	var calls int
+	p := newTestProvider(w, DefaultWriterConfig(), WithDeliveryCallback(func(WriterPayload, error) { calls++ }))
+
+	require.NoError(t, p.WriteMessage(ctx, payload("good")))
+	d, err := p.WriteMessageAsync(ctx, payload("bad"))
+	require.NoError(t, err)
+	select {
+	case <-d.Done():
+	default:
+		t.Fatal("synchronous delivery should be complete")
+	}
+	assert.EqualError(t, d.Err(), "failed to write message to topic test: Kafka write errors (1/1), errors: [message too large]", "the body is kept out of the error")
+	assert.Equal(t, 2, calls)
+
+	_, err = p.WriteMessageAsync(ctx, WriterPayload{Type: "Hail"})
+	assert.Error(t, err)
+	require.NoError(t, p.Close(ctx))
+	assert.ErrorIs(t, p.WriteMessage(ctx, payload("late")), ErrClosed)
+}
+
+func TestWriterConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*WriterConfig)
+		wantErr []string
+	}{
+		{name: "default", modify: func(*WriterConfig) {}},
+		{name: "async", modify: func(c *WriterConfig) { c.Async, c.Compression, c.RequiredAcks = true, CompressionZstd, AcksAll }},
+		{name: "empty compression", modify: func(c *WriterConfig) { c.Compression = "" }},
+		{
+			name: "every problem",
+			modify: func(c *WriterConfig) {
+				*c = WriterConfig{Linger: -time.Second, Compression: "brotli", RequiredAcks: "some", Async: true}
+			},
+			wantErr: []string{"batch size", "batch bytes", "linger", "compression codec", "required acks", "max attempts", "queue size"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultWriterConfig()
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			for _, want := range tt.wantErr {
+				assert.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
+
+func TestWriterConfig_Apply(t *testing.T) {
+	cfg := WriterConfig{
+		BatchSize:    500,
+		BatchBytes:   4 << 20,
+		Linger:       50 * time.Millisecond,
+		Compression:  CompressionSnappy,
+		RequiredAcks: AcksAll,
+		MaxAttempts:  3,
+	}
+	var w kafka.Writer
+	cfg.apply(&w)
+	assert.Equal(t, 500, w.BatchSize)
+	assert.Equal(t, int64(4<<20), w.BatchBytes)
+	assert.Equal(t, 50*time.Millisecond, w.BatchTimeout)
+	assert.Equal(t, kafka.Snappy, w.Compression)
+	assert.Equal(t, kafka.RequireAll, w.RequiredAcks)
+	assert.Equal(t, 3, w.MaxAttempts)
+
+	cfg.Async, cfg.Compression = true, CompressionNone
+	cfg.apply(&w)
+	assert.Equal(t, time.Millisecond, w.BatchTimeout)
+	assert.Equal(t, kafka.Compression(0), w.Compression)
+}
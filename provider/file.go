@@ -0,0 +1,230 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/stormsync/transformer/proto"
+	"github.com/stormsync/transformer/report"
+)
+
+// Format is the encoding a FileProvider writes messages in.
+type Format string
+
+const (
+	// FormatProtobuf writes each body as a varint length followed by the marshaled
+	// protobuf, the framing read by protodelim.UnmarshalFrom.
+	FormatProtobuf Format = "protobuf"
+	// FormatNDJSON writes each message as a line of protojson.
+	FormatNDJSON Format = "ndjson"
+	// FormatCSV writes report messages as CSV rows after a header row.
+	// Summaries, events and alerts cannot be written as CSV.
+	FormatCSV Format = "csv"
+)
+
+// messageTypes maps payload types to the message their body holds so it can be
+// decoded for the NDJSON and CSV formats.
+var messageTypes = map[string]func() proto.Message{
+	"Hail":    func() proto.Message { return &pb.HailMsg{} },
+	"Wind":    func() proto.Message { return &pb.WindMsg{} },
+	"Tornado": func() proto.Message { return &pb.TornadoMsg{} },
+	"LSR":     func() proto.Message { return &pb.LSRMsg{} },
+	"Summary": func() proto.Message { return &pb.SummaryMsg{} },
+	"Event":   func() proto.Message { return &pb.EventMsg{} },
+	"Alert":   func() proto.Message { return &pb.Alert{} },
+}
+
+// csvHeader is the header row written before the first CSV row.
+var csvHeader = []string{"type", "time", "magnitude", "distance", "direction", "location", "county", "state", "lat", "lon", "remarks", "severity", "event_id", "qc_flags"}
+
+// FileProvider writes messages to a file or stdout.
+type FileProvider struct {
+	Name   string
+	format Format
+	logger *slog.Logger
+
+	mu          sync.Mutex
+	w           *bufio.Writer
+	csv         *csv.Writer
+	wroteHeader bool
+	closer      io.Closer
+	closed      bool
+	closeErr    error
+}
+
+var _ Provider = (*FileProvider)(nil)
+
+// NewFileProvider returns a provider that writes messages to w in the format.
+// Writes are buffered until Flush or Close. When w is an io.Closer it is closed by Close.
+func NewFileProvider(w io.Writer, name string, format Format, logger *slog.Logger) (*FileProvider, error) {
+	switch format {
+	case FormatProtobuf, FormatNDJSON, FormatCSV:
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+	bw := bufio.NewWriter(w)
+	p := &FileProvider{
+		Name:   name,
+		format: format,
+		logger: logger,
+		w:      bw,
+		csv:    csv.NewWriter(bw),
+	}
+	if closer, ok := w.(io.Closer); ok {
+		p.closer = closer
+	}
+	return p, nil
+}
+
+// WriteMessage encodes the payload and writes it. Payload headers and keys are not written.
+func (p *FileProvider) WriteMessage(_ context.Context, wp WriterPayload) error {
+	if wp.Type == "" {
+		return errors.New("payload type cannot be empty")
+	}
+	if wp.Body == nil {
+		return errors.New("payload body cannot be nil")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return fmt.Errorf("failed to write message to %s: %w", p.Name, ErrClosed)
+	}
+
+	var err error
+	switch p.format {
+	case FormatProtobuf:
+		_, err = p.w.Write(binary.AppendUvarint(nil, uint64(len(wp.Body))))
+		if err == nil {
+			_, err = p.w.Write(wp.Body)
+		}
+	case FormatNDJSON:
+		var msg proto.Message
+		if msg, err = Decode(wp); err != nil {
+			return err
+		}
+		var b []byte
+		if b, err = protojson.Marshal(msg); err != nil {
+			return fmt.Errorf("failed to encode %s message as json: %w", wp.Type, err)
+		}
+		if _, err = p.w.Write(b); err == nil {
+			err = p.w.WriteByte('\n')
+		}
+	case FormatCSV:
+		err = p.writeCSV(wp)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write message to %s: %w", p.Name, err)
+	}
+	p.logger.Debug("message written", "output", p.Name, "type", wp.Type)
+	return nil
+}
+
+// writeCSV writes a report as a CSV row. p.mu must be held.
+func (p *FileProvider) writeCSV(wp WriterPayload) error {
+	msg, err := Decode(wp)
+	if err != nil {
+		return err
+	}
+	r, ok := msg.(report.Report)
+	if !ok {
+		return fmt.Errorf("%s messages cannot be written as csv", wp.Type)
+	}
+	if !p.wroteHeader {
+		if err := p.csv.Write(csvHeader); err != nil {
+			return err
+		}
+		p.wroteHeader = true
+	}
+	row := []string{
+		r.GetType(),
+		strconv.FormatInt(r.GetTime(), 10),
+		strconv.FormatInt(int64(report.Magnitude(r)), 10),
+		strconv.FormatInt(int64(r.GetDistance()), 10),
+		r.GetDirection(),
+		r.GetLocation(),
+		r.GetCounty(),
+		r.GetState(),
+		r.GetLat(),
+		r.GetLon(),
+		r.GetRemarks(),
+	}
+	switch m := r.(type) {
+	case *pb.HailMsg:
+		row = append(row, report.SeverityName(m.GetSeverity()), m.GetEventId(), strconv.FormatUint(uint64(m.GetQcFlags()), 10))
+	case *pb.WindMsg:
+		row = append(row, report.SeverityName(m.GetSeverity()), m.GetEventId(), strconv.FormatUint(uint64(m.GetQcFlags()), 10))
+	case *pb.TornadoMsg:
+		row = append(row, report.SeverityName(m.GetSeverity()), m.GetEventId(), strconv.FormatUint(uint64(m.GetQcFlags()), 10))
+	case *pb.LSRMsg:
+		row = append(row, report.SeverityName(pb.Severity_SEVERITY_UNKNOWN), m.GetEventId(), strconv.FormatUint(uint64(m.GetQcFlags()), 10))
+	}
+	if err := p.csv.Write(row); err != nil {
+		return err
+	}
+	return p.csv.Error()
+}
+
+// Decode unmarshals the payload body into the message for its type, such as
+// Hail or Summary.
+func Decode(wp WriterPayload) (proto.Message, error) {
+	newMsg, ok := messageTypes[wp.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown payload type %q", wp.Type)
+	}
+	msg := newMsg()
+	if err := proto.Unmarshal(wp.Body, msg); err != nil {
+		return nil, fmt.Errorf("failed to decode %s message: %w", wp.Type, err)
+	}
+	return msg, nil
+}
+
+// Flush writes any buffered messages to the underlying writer.
+func (p *FileProvider) Flush(_ context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.flush()
+}
+
+// flush writes the buffered messages. p.mu must be held.
+func (p *FileProvider) flush() error {
+	p.csv.Flush()
+	if err := p.csv.Error(); err != nil {
+		return fmt.Errorf("failed to flush %s: %w", p.Name, err)
+	}
+	if err := p.w.Flush(); err != nil {
+		return fmt.Errorf("failed to flush %s: %w", p.Name, err)
+	}
+	return nil
+}
+
+// Close flushes the buffered messages and closes the underlying writer.
+func (p *FileProvider) Close(_ context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return p.closeErr
+	}
+	p.closed = true
+
+	var errs []error
+	errs = append(errs, p.flush())
+	if p.closer != nil {
+		if err := p.closer.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close %s: %w", p.Name, err))
+		}
+	}
+	p.closeErr = errors.Join(errs...)
+	return p.closeErr
+}
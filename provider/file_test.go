@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	slogenv "github.com/cbrewster/slog-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/stormsync/transformer/proto"
+)
+
+var logger = slog.New(slogenv.NewHandler(slog.NewTextHandler(os.Stderr, nil)))
+
+var testHail = &pb.HailMsg{
+	Type:     "Hail",
+	Time:     1715984520,
+	Size:     175,
+	Distance: 9,
+	Location: "Granbury, west side",
+	County:   "Hood",
+	State:    "TX",
+	Lat:      "32.36",
+	Lon:      "-97.66",
+	Severity: pb.Severity_SEVERITY_SEVERE,
+}
+
+func writeHail(t *testing.T, format Format) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	p, err := NewFileProvider(&buf, "test", format, logger)
+	require.NoError(t, err)
+
+	body, err := proto.Marshal(testHail)
+	require.NoError(t, err)
+	for range 2 {
+		require.NoError(t, p.WriteMessage(context.Background(), WriterPayload{Body: body, Type: "Hail"}))
+	}
+	require.NoError(t, p.Flush(context.Background()))
+	return buf.Bytes()
+}
+
+func TestFileProvider_Protobuf(t *testing.T) {
+	r := bufio.NewReader(bytes.NewReader(writeHail(t, FormatProtobuf)))
+	for range 2 {
+		var got pb.HailMsg
+		require.NoError(t, protodelim.UnmarshalFrom(r, &got))
+		assert.True(t, proto.Equal(testHail, &got))
+	}
+}
+
+func TestFileProvider_NDJSON(t *testing.T) {
+	lines := bytes.Split(bytes.TrimSpace(writeHail(t, FormatNDJSON)), []byte("\n"))
+	require.Len(t, lines, 2)
+	var got pb.HailMsg
+	require.NoError(t, protojson.Unmarshal(lines[1], &got))
+	assert.True(t, proto.Equal(testHail, &got))
+}
+
+func TestFileProvider_CSV(t *testing.T) {
+	assert.Equal(t, "type,time,magnitude,distance,direction,location,county,state,lat,lon,remarks,severity,event_id,qc_flags\n"+
+		"Hail,1715984520,175,9,,\"Granbury, west side\",Hood,TX,32.36,-97.66,,severe,,0\n"+
+		"Hail,1715984520,175,9,,\"Granbury, west side\",Hood,TX,32.36,-97.66,,severe,,0\n",
+		string(writeHail(t, FormatCSV)))
+
+	var buf bytes.Buffer
+	p, err := NewFileProvider(&buf, "test", FormatCSV, logger)
+	require.NoError(t, err)
+	body, err := proto.Marshal(&pb.SummaryMsg{State: "TX"})
+	require.NoError(t, err)
+	assert.ErrorContains(t, p.WriteMessage(context.Background(), WriterPayload{Body: body, Type: "Summary"}), "cannot be written as csv")
+}
+
+func TestNewFileProvider_UnknownFormat(t *testing.T) {
+	_, err := NewFileProvider(&bytes.Buffer{}, "test", "xml", logger)
+	assert.Error(t, err)
+}
+
+type closeCounter struct {
+	bytes.Buffer
+	closes int
+}
+
+func (c *closeCounter) Close() error {
+	c.closes++
+	return nil
+}
+
+func TestFileProvider_Close(t *testing.T) {
+	var out closeCounter
+	p, err := NewFileProvider(&out, "test", FormatNDJSON, logger)
+	require.NoError(t, err)
+	body, err := proto.Marshal(testHail)
+	require.NoError(t, err)
+	require.NoError(t, p.WriteMessage(context.Background(), WriterPayload{Body: body, Type: "Hail"}))
+	assert.Zero(t, out.Len(), "writes should be buffered")
+
+	require.NoError(t, p.Close(context.Background()))
+	require.NoError(t, p.Close(context.Background()))
+	assert.NotZero(t, out.Len(), "close should flush buffered writes")
+	assert.Equal(t, 1, out.closes)
+	assert.ErrorIs(t, p.WriteMessage(context.Background(), WriterPayload{Body: body, Type: "Hail"}), ErrClosed)
+}
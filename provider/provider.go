@@ -2,79 +2,230 @@ package provider
 
 import (
 	"context"
-	"crypto/tls"
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/segmentio/kafka-go"
-	"github.com/segmentio/kafka-go/sasl/scram"
+
+	"github.com/stormsync/transformer/kafkaconn"
 )
 
 type Provider interface {
 	WriteMessage(ctx context.Context, payload WriterPayload) error
+	// Flush blocks until every message written so far has been delivered.
+	Flush(ctx context.Context) error
+	// Close flushes the provider and releases its connections. It is safe to call
+	// more than once; later calls return the result of the first.
+	Close(ctx context.Context) error
 }
 
+// ErrClosed is returned when writing to a provider that has been closed.
+var ErrClosed = errors.New("provider is closed")
+
 type WriterPayload struct {
-	Body []byte
-	Type string
+	Key     []byte
+	Body    []byte
+	Type    string
+	Headers []WriterHeader
+}
+
+// WriterHeader is an additional header written with the message alongside the report type.
+type WriterHeader struct {
+	Key   string
+	Value []byte
 }
 
 type KProvider struct {
-	Writer   *kafka.Writer
-	Topic    string
-	Address  string
-	user     string
-	password string
-	logger   *slog.Logger
+	Writer  *kafka.Writer
+	Topic   string
+	Address string
+	logger  *slog.Logger
+
+	writer     messageWriter // Writer, replaced in tests
+	config     WriterConfig
+	onDelivery func(WriterPayload, error)
+	queue      *asyncQueue // nil unless the writer is async
+
+	closeOnce sync.Once
+	closeErr  error
+	closed    atomic.Bool
 }
 
-// NewProvider generates a new kafka provider allowing for writes to a topic
-func NewKProvider(address, topic, user, pw string, logger *slog.Logger) (*KProvider, error) {
-	mechanism, err := scram.Mechanism(scram.SHA256, user, pw)
+// Option configures optional behavior of a KProvider.
+type Option func(*KProvider)
+
+// WithWriterConfig tunes batching, compression, acks and retries, and selects
+// the async mode. DefaultWriterConfig is used when it is not given.
+func WithWriterConfig(cfg WriterConfig) Option {
+	return func(p *KProvider) {
+		p.config = cfg
+	}
+}
+
+// WithDeliveryCallback calls fn with each payload once it is delivered, or with the
+// error that kept it from being delivered. In async mode fn is called from the
+// writer's goroutine, so it should not block.
+func WithDeliveryCallback(fn func(WriterPayload, error)) Option {
+	return func(p *KProvider) {
+		p.onDelivery = fn
+	}
+}
+
+// NewKProvider generates a new kafka provider allowing for writes to a topic on the
+// brokers in conn, authenticating and dialing TLS as conn describes.
+func NewKProvider(conn kafkaconn.Options, topic string, logger *slog.Logger, opts ...Option) (*KProvider, error) {
+	transport, err := conn.Transport()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create scram.Mechanism for auth: %w", err)
+		return nil, fmt.Errorf("invalid kafka connection options: %w", err)
+	}
+	p := &KProvider{
+		Topic:   topic,
+		Address: strings.Join(conn.Brokers, ","),
+		logger:  logger,
+		config:  DefaultWriterConfig(),
+	}
+	for _, opt := range opts {
+		opt(p)
 	}
+	if err := p.config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid writer config: %w", err)
+	}
+
 	w := kafka.Writer{
-		Addr:  kafka.TCP(address),
-		Topic: topic,
-		Transport: &kafka.Transport{
-			SASL: mechanism,
-			TLS:  &tls.Config{},
-		},
-	}
-
-	return &KProvider{
-		Writer:   &w,
-		Topic:    topic,
-		Address:  address,
-		user:     user,
-		password: pw,
-		logger:   logger,
-	}, nil
+		Addr:      kafka.TCP(conn.Brokers...),
+		Topic:     topic,
+		Transport: transport,
+	}
+	p.config.apply(&w)
+	p.Writer = &w
+	p.writer = &w
+
+	if p.config.Async {
+		p.queue = newAsyncQueue(p.config.QueueSize)
+		go p.run()
+	}
+	return p, nil
 }
 
 // WriteMessage allows writing to topic defined in the Provider constructor.
+// In async mode the message is only queued, and a failed delivery is reported
+// to the delivery callback instead.
 func (p *KProvider) WriteMessage(ctx context.Context, wp WriterPayload) error {
+	if p.queue != nil {
+		_, err := p.WriteMessageAsync(ctx, wp)
+		return err
+	}
+	msg, err := p.message(wp)
+	if err != nil {
+		return err
+	}
+	return p.write(ctx, wp, msg)
+}
+
+// WriteMessageAsync queues the payload and returns a Delivery that completes once
+// the message is delivered. It blocks while the queue is full, and returns an
+// error if the payload is invalid, the provider is closed, or the context is done
+// before the message is queued. Without async mode the message is written before
+// WriteMessageAsync returns and the Delivery is already complete.
+func (p *KProvider) WriteMessageAsync(ctx context.Context, wp WriterPayload) (*Delivery, error) {
+	msg, err := p.message(wp)
+	if err != nil {
+		return nil, err
+	}
+	d := newDelivery(wp, msg)
+	if p.queue == nil {
+		d.complete(p.write(ctx, wp, msg))
+		return d, nil
+	}
+	if err := p.queue.enqueue(ctx, d); err != nil {
+		return nil, fmt.Errorf("failed to queue message for topic %s: %w", p.Topic, err)
+	}
+	return d, nil
+}
+
+// write delivers a single message synchronously.
+func (p *KProvider) write(ctx context.Context, wp WriterPayload, msg kafka.Message) error {
+	p.logger.Debug("writing message", "type", wp.Type)
+	err := p.writer.WriteMessages(ctx, msg)
+	if err != nil {
+		// the body stays out of the error and logs, it is binary and makes every failure unique
+		p.logger.Debug("WriteMessages failed", "type", wp.Type, "key", string(wp.Key), "size", len(wp.Body), "error", err)
+		err = fmt.Errorf("failed to write message to topic %s: %w", p.Topic, err)
+	}
+	if p.onDelivery != nil {
+		p.onDelivery(wp, err)
+	}
+	return err
+}
+
+// message validates the payload and builds the kafka message for it.
+func (p *KProvider) message(wp WriterPayload) (kafka.Message, error) {
+	if p.closed.Load() {
+		return kafka.Message{}, fmt.Errorf("failed to write message to topic %s: %w", p.Topic, ErrClosed)
+	}
 	if wp.Type == "" {
 		p.logger.Debug("payload type is empty in WriteMessage", "type", wp.Type)
-		return errors.New("payload type cannot be empty")
+		return kafka.Message{}, errors.New("payload type cannot be empty")
 	}
 	if wp.Body == nil {
 		p.logger.Debug("payload body is nil in WriteMessage", "type", wp.Type)
-		return errors.New("payload body cannot be nil")
+		return kafka.Message{}, errors.New("payload body cannot be nil")
 	}
 
 	header := []kafka.Header{{
 		Key:   "reportType",
 		Value: []byte(wp.Type),
 	}}
+	for _, h := range wp.Headers {
+		header = append(header, kafka.Header{Key: h.Key, Value: h.Value})
+	}
+	return kafka.Message{Key: wp.Key, Value: wp.Body, Headers: header}, nil
+}
 
-	p.logger.Debug("writing message", "type", wp.Type)
-	err := p.Writer.WriteMessages(ctx, kafka.Message{Value: wp.Body, Headers: header})
-	if err != nil {
-		p.logger.Debug("WriteMessages failed", "Type", wp.Type, "bBody", string(wp.Body))
-		return fmt.Errorf("failed to write message to topic %s; line: %s;  err: %w", p.Topic, string(wp.Body), err)
+// Flush returns once pending writes are delivered. Synchronous writes are
+// delivered when WriteMessage returns, so only the async queue is waited on.
+func (p *KProvider) Flush(ctx context.Context) error {
+	if p.queue == nil {
+		return ctx.Err()
+	}
+	if err := p.queue.flush(ctx); err != nil {
+		return fmt.Errorf("failed to flush provider of topic %s: %w", p.Topic, err)
 	}
 	return nil
 }
+
+// Close delivers any queued messages, then closes the writer and its connections
+// to the brokers. It returns early with the context's error if the context is
+// done first.
+func (p *KProvider) Close(ctx context.Context) error {
+	p.closeOnce.Do(func() {
+		p.closed.Store(true)
+		var errs []error
+		if p.queue != nil {
+			errs = append(errs, p.Flush(ctx))
+			p.queue.close()
+		}
+
+		done := make(chan error, 1)
+		go func() {
+			if p.queue != nil {
+				<-p.queue.stopped
+			}
+			done <- p.writer.Close()
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to close provider of topic %s: %w", p.Topic, err))
+			}
+		case <-ctx.Done():
+			errs = append(errs, fmt.Errorf("failed to close provider of topic %s: %w", p.Topic, ctx.Err()))
+		}
+		p.closeErr = errors.Join(errs...)
+	})
+	return p.closeErr
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Compression codecs accepted by WriterConfig.
+const (
+	CompressionNone   = "none"
+	CompressionGzip   = "gzip"
+	CompressionSnappy = "snappy"
+	CompressionLz4    = "lz4"
+	CompressionZstd   = "zstd"
+)
+
+// Required acks accepted by WriterConfig.
+const (
+	AcksNone = "none"
+	AcksOne  = "one"
+	AcksAll  = "all"
+)
+
+var compressionCodecs = map[string]kafka.Compression{
+	CompressionGzip:   kafka.Gzip,
+	CompressionSnappy: kafka.Snappy,
+	CompressionLz4:    kafka.Lz4,
+	CompressionZstd:   kafka.Zstd,
+}
+
+var requiredAcks = map[string]kafka.RequiredAcks{
+	AcksNone: kafka.RequireNone,
+	AcksOne:  kafka.RequireOne,
+	AcksAll:  kafka.RequireAll,
+}
+
+// WriterConfig tunes how a KProvider batches and delivers messages. Larger batches
+// and a longer linger trade latency for throughput, which suits backfills.
+//
+// In async mode WriteMessage only queues the message, blocking while QueueSize
+// messages are already waiting, and delivery results are reported to the delivery
+// callback or through the Delivery returned by WriteMessageAsync.
+type WriterConfig struct {
+	BatchSize    int           `yaml:"batch-size"`
+	BatchBytes   int64         `yaml:"batch-bytes"`
+	Linger       time.Duration `yaml:"linger"`
+	Compression  string        `yaml:"compression"`
+	RequiredAcks string        `yaml:"required-acks"`
+	MaxAttempts  int           `yaml:"max-attempts"`
+
+	Async     bool `yaml:"async"`
+	QueueSize int  `yaml:"queue-size"`
+}
+
+// DefaultWriterConfig returns the kafka-go writer defaults with a synchronous
+// writer, which is how KProvider has always behaved, except that writes wait for
+// every in-sync replica so an acknowledged report is not lost with its leader.
+func DefaultWriterConfig() WriterConfig {
+	return WriterConfig{
+		BatchSize:    100,
+		BatchBytes:   1048576,
+		Linger:       time.Second,
+		Compression:  CompressionNone,
+		RequiredAcks: AcksAll,
+		MaxAttempts:  10,
+		QueueSize:    1000,
+	}
+}
+
+// Validate returns every problem found with the config.
+func (c WriterConfig) Validate() error {
+	var errs []error
+	if c.BatchSize <= 0 {
+		errs = append(errs, errors.New("batch size must be positive"))
+	}
+	if c.BatchBytes <= 0 {
+		errs = append(errs, errors.New("batch bytes must be positive"))
+	}
+	if c.Linger < 0 {
+		errs = append(errs, errors.New("linger cannot be negative"))
+	}
+	if _, ok := compressionCodecs[c.Compression]; !ok && c.Compression != CompressionNone && c.Compression != "" {
+		errs = append(errs, fmt.Errorf("unknown compression codec %q, expected none, gzip, snappy, lz4 or zstd", c.Compression))
+	}
+	if _, ok := requiredAcks[c.RequiredAcks]; !ok {
+		errs = append(errs, fmt.Errorf("unknown required acks %q, expected none, one or all", c.RequiredAcks))
+	}
+	if c.MaxAttempts <= 0 {
+		errs = append(errs, errors.New("max attempts must be positive"))
+	}
+	if c.Async && c.QueueSize <= 0 {
+		errs = append(errs, errors.New("async queue size must be positive"))
+	}
+	return errors.Join(errs...)
+}
+
+// apply sets the tuning on the writer. The config must be valid.
+func (c WriterConfig) apply(w *kafka.Writer) {
+	w.BatchSize = c.BatchSize
+	w.BatchBytes = c.BatchBytes
+	w.BatchTimeout = c.Linger
+	w.Compression = compressionCodecs[c.Compression]
+	w.RequiredAcks = requiredAcks[c.RequiredAcks]
+	w.MaxAttempts = c.MaxAttempts
+	if c.Async {
+		// the queue worker lingers and hands the writer whole batches, so the
+		// writer should not wait on top of that for a batch to fill
+		w.BatchTimeout = time.Millisecond
+	}
+}
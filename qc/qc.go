@@ -0,0 +1,297 @@
+// Package qc runs data quality checks on reports and records the checks that
+// failed on the report itself so downstream consumers can decide what to trust.
+package qc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/stormsync/transformer/geo"
+	pb "github.com/stormsync/transformer/proto"
+	"github.com/stormsync/transformer/report"
+)
+
+// Flag is a single quality check. A report's qc_flags field is the bitwise OR of
+// the flags of every check it failed.
+type Flag uint32
+
+const (
+	// FlagCoordinates is set when the lat/lon cannot be parsed, is 0,0, or lies
+	// outside of the configured domain.
+	FlagCoordinates Flag = 1 << iota
+	// FlagStateCode is set when the state is not a known USPS code.
+	FlagStateCode
+	// FlagStateCoordinates is set when the coordinates are far from the listed state.
+	FlagStateCoordinates
+	// FlagFutureTime is set when the report time is later than now.
+	FlagFutureTime
+	// FlagMagnitude is set when the magnitude is beyond what is physically plausible.
+	FlagMagnitude
+	// FlagEmptyLocation is set when the location or county is blank. Historical
+	// database records have neither and are not checked.
+	FlagEmptyLocation
+)
+
+// Names of the checks, as used in the config and in the qc_issues field.
+const (
+	CheckCoordinates      = "coordinates"
+	CheckStateCode        = "state-code"
+	CheckStateCoordinates = "state-coordinates"
+	CheckFutureTime       = "future-time"
+	CheckMagnitude        = "magnitude"
+	CheckEmptyLocation    = "empty-location"
+)
+
+var flagNames = map[Flag]string{
+	FlagCoordinates:      CheckCoordinates,
+	FlagStateCode:        CheckStateCode,
+	FlagStateCoordinates: CheckStateCoordinates,
+	FlagFutureTime:       CheckFutureTime,
+	FlagMagnitude:        CheckMagnitude,
+	FlagEmptyLocation:    CheckEmptyLocation,
+}
+
+// allFlags lists the flags in bit order so issues are always reported in the same order.
+var allFlags = []Flag{
+	FlagCoordinates,
+	FlagStateCode,
+	FlagStateCoordinates,
+	FlagFutureTime,
+	FlagMagnitude,
+	FlagEmptyLocation,
+}
+
+// String returns the check name of a single flag.
+func (f Flag) String() string {
+	if name, ok := flagNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("flag(%d)", uint32(f))
+}
+
+// Names returns the check names of every flag set in the bitmask.
+func Names(flags uint32) []string {
+	names := make([]string, 0, bits.OnesCount32(flags))
+	for _, f := range allFlags {
+		if flags&uint32(f) != 0 {
+			names = append(names, f.String())
+		}
+	}
+	return names
+}
+
+// Domain is the lat/lon rectangle that valid coordinates must fall within. A MinLon
+// greater than MaxLon wraps the rectangle across the antimeridian.
+type Domain struct {
+	MinLat float64 `yaml:"min-lat"`
+	MinLon float64 `yaml:"min-lon"`
+	MaxLat float64 `yaml:"max-lat"`
+	MaxLon float64 `yaml:"max-lon"`
+}
+
+// Config selects the checks to run and their limits. Magnitude limits are in the
+// units of the source column: hail size in hundredths of an inch, wind speed in
+// knots, and the tornado F/EF scale.
+type Config struct {
+	// Checks lists the names of the checks to run. An empty list runs every check.
+	Checks []string `yaml:"checks"`
+
+	Domain Domain `yaml:"domain"`
+	// StateMarginMiles is how far outside a state's bounding box a report can be
+	// before its coordinates are considered inconsistent with the state.
+	StateMarginMiles float64 `yaml:"state-margin-miles"`
+	// FutureTolerance allows for clock skew before a report time is in the future.
+	FutureTolerance time.Duration `yaml:"future-tolerance"`
+
+	MaxHail    int32 `yaml:"max-hail"`
+	MaxWind    int32 `yaml:"max-wind"`
+	MaxTornado int32 `yaml:"max-tornado"`
+}
+
+// DefaultConfig runs every check. The domain covers the states and territories,
+// from Guam and American Samoa across the antimeridian to Puerto Rico and the
+// Virgin Islands, and the magnitude limits sit just above the US records of 8"
+// hail and 200 kt wind.
+func DefaultConfig() Config {
+	return Config{
+		Domain:           Domain{MinLat: -15, MinLon: 144, MaxLat: 72, MaxLon: -64},
+		StateMarginMiles: 100,
+		FutureTolerance:  15 * time.Minute,
+		MaxHail:          800,
+		MaxWind:          200,
+		MaxTornado:       5,
+	}
+}
+
+// LoadConfig decodes a YAML config. Fields that are not set keep their defaults.
+func LoadConfig(r io.Reader) (Config, error) {
+	cfg := DefaultConfig()
+	if err := yaml.NewDecoder(r).Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return cfg, fmt.Errorf("unable to decode qc config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Validate returns every problem found with the config.
+func (c Config) Validate() error {
+	var errs []error
+	for _, name := range c.Checks {
+		if !slices.Contains(Names(^uint32(0)), name) {
+			errs = append(errs, fmt.Errorf("unknown check %q", name))
+		}
+	}
+	if c.Domain.MinLat > c.Domain.MaxLat {
+		errs = append(errs, errors.New("domain min lat must not exceed max lat"))
+	}
+	if c.Domain.MinLon < -180 || c.Domain.MaxLon > 180 {
+		errs = append(errs, errors.New("domain longitudes must be between -180 and 180"))
+	}
+	if c.StateMarginMiles < 0 {
+		errs = append(errs, errors.New("state margin cannot be negative"))
+	}
+	if c.FutureTolerance < 0 {
+		errs = append(errs, errors.New("future tolerance cannot be negative"))
+	}
+	if c.MaxHail <= 0 || c.MaxWind <= 0 || c.MaxTornado <= 0 {
+		errs = append(errs, errors.New("magnitude limits must be positive"))
+	}
+	return errors.Join(errs...)
+}
+
+// Checker runs the enabled checks against reports and keeps track of how many
+// reports were checked and how many failed each check.
+type Checker struct {
+	cfg     Config
+	enabled uint32
+	now     func() time.Time
+
+	mu      sync.Mutex
+	checked int64
+	counts  map[string]int64
+}
+
+// NewChecker validates the config and returns a Checker for it.
+func NewChecker(cfg Config) (*Checker, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	c := &Checker{cfg: cfg, now: time.Now, counts: make(map[string]int64, len(allFlags))}
+	for _, f := range allFlags {
+		if len(cfg.Checks) == 0 || slices.Contains(cfg.Checks, f.String()) {
+			c.enabled |= uint32(f)
+			c.counts[f.String()] = 0
+		}
+	}
+	return c, nil
+}
+
+// Check returns the bitmask of the enabled checks that the report failed.
+func (c *Checker) Check(r report.Report) uint32 {
+	var flags uint32
+	set := func(f Flag, failed bool) {
+		if failed && c.enabled&uint32(f) != 0 {
+			flags |= uint32(f)
+		}
+	}
+
+	lat, lon, err := report.LatLon(r)
+	validCoords := err == nil && !(lat == 0 && lon == 0) && c.inDomain(lat, lon)
+	set(FlagCoordinates, !validCoords)
+
+	bounds, knownState := geo.StateBounds(r.GetState())
+	set(FlagStateCode, !knownState)
+	if validCoords && knownState {
+		set(FlagStateCoordinates, !bounds.Expand(c.cfg.StateMarginMiles).Contains(lat, lon))
+	}
+
+	set(FlagFutureTime, time.Unix(r.GetTime(), 0).After(c.now().Add(c.cfg.FutureTolerance)))
+	set(FlagMagnitude, !c.plausible(r))
+	set(FlagEmptyLocation, !fromDatabase(r) && (strings.TrimSpace(r.GetLocation()) == "" || strings.TrimSpace(r.GetCounty()) == ""))
+	return flags
+}
+
+// Apply checks the report, records the failed checks in its qc_flags and
+// qc_issues fields and counts them. The bitmask is returned.
+func (c *Checker) Apply(r report.Report) uint32 {
+	flags := c.Check(r)
+	issues := Names(flags)
+	switch m := r.(type) {
+	case *pb.HailMsg:
+		m.QcFlags, m.QcIssues = flags, issues
+	case *pb.WindMsg:
+		m.QcFlags, m.QcIssues = flags, issues
+	case *pb.TornadoMsg:
+		m.QcFlags, m.QcIssues = flags, issues
+	case *pb.LSRMsg:
+		m.QcFlags, m.QcIssues = flags, issues
+	}
+
+	c.mu.Lock()
+	c.checked++
+	for _, name := range issues {
+		c.counts[name]++
+	}
+	c.mu.Unlock()
+	return flags
+}
+
+// Checked returns the number of reports passed to Apply.
+func (c *Checker) Checked() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.checked
+}
+
+// Counts returns a copy of the number of reports that failed each enabled check.
+func (c *Checker) Counts() map[string]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := make(map[string]int64, len(c.counts))
+	for k, v := range c.counts {
+		counts[k] = v
+	}
+	return counts
+}
+
+func (c *Checker) inDomain(lat, lon float64) bool {
+	d := c.cfg.Domain
+	if lat < d.MinLat || lat > d.MaxLat {
+		return false
+	}
+	if d.MinLon > d.MaxLon {
+		return lon >= d.MinLon || lon <= d.MaxLon
+	}
+	return lon >= d.MinLon && lon <= d.MaxLon
+}
+
+// fromDatabase reports whether r is a record of the historical database, which has
+// no location or county columns and places a report by its county FIPS code. Only
+// historical sources record an impact, and storm events always name the county.
+func fromDatabase(r report.Report) bool {
+	m, ok := r.(interface{ GetImpact() *pb.Impact })
+	return ok && m.GetImpact() != nil &&
+		strings.TrimSpace(r.GetLocation()) == "" && strings.TrimSpace(r.GetCounty()) == ""
+}
+
+// plausible reports whether the magnitude is within the configured limits.
+// Unknown (UNK) magnitudes are zero and always plausible.
+func (c *Checker) plausible(r report.Report) bool {
+	mag := report.Magnitude(r)
+	switch r.(type) {
+	case *pb.HailMsg:
+		return mag >= 0 && mag <= c.cfg.MaxHail
+	case *pb.WindMsg:
+		return mag >= 0 && mag <= c.cfg.MaxWind
+	case *pb.TornadoMsg:
+		return mag >= 0 && mag <= c.cfg.MaxTornado
+	}
+	return true
+}
@@ -0,0 +1,210 @@
+package qc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/stormsync/transformer/proto"
+	"github.com/stormsync/transformer/report"
+)
+
+var now = time.Date(2024, 5, 17, 22, 0, 0, 0, time.UTC)
+
+func newTestChecker(t *testing.T, cfg Config) *Checker {
+	t.Helper()
+	c, err := NewChecker(cfg)
+	require.NoError(t, err)
+	c.now = func() time.Time { return now }
+	return c
+}
+
+func hail(mutate func(*pb.HailMsg)) *pb.HailMsg {
+	m := &pb.HailMsg{
+		Time:     now.Add(-time.Hour).Unix(),
+		Size:     100,
+		Location: "3 N Norman",
+		County:   "Cleveland",
+		State:    "OK",
+		Lat:      "35.26",
+		Lon:      "-97.44",
+	}
+	if mutate != nil {
+		mutate(m)
+	}
+	return m
+}
+
+func TestChecker_Check(t *testing.T) {
+	tests := []struct {
+		name   string
+		report report.Report
+		want   uint32
+	}{
+		{
+			name:   "should pass a clean report",
+			report: hail(nil),
+			want:   0,
+		},
+		{
+			name:   "should flag unparsable coordinates",
+			report: hail(func(m *pb.HailMsg) { m.Lat = "UNK" }),
+			want:   uint32(FlagCoordinates),
+		},
+		{
+			name:   "should flag 0,0 coordinates",
+			report: hail(func(m *pb.HailMsg) { m.Lat, m.Lon = "0", "0" }),
+			want:   uint32(FlagCoordinates),
+		},
+		{
+			name:   "should flag a longitude missing its sign",
+			report: hail(func(m *pb.HailMsg) { m.Lon = "97.44" }),
+			want:   uint32(FlagCoordinates),
+		},
+		{
+			name:   "should flag coordinates out in the Atlantic",
+			report: hail(func(m *pb.HailMsg) { m.Lon = "-40.00" }),
+			want:   uint32(FlagCoordinates),
+		},
+		{
+			name:   "should pass a report from Guam across the antimeridian",
+			report: hail(func(m *pb.HailMsg) { m.State, m.Lat, m.Lon = "GU", "13.48", "144.79" }),
+			want:   0,
+		},
+		{
+			name:   "should pass a report from the Northern Marianas",
+			report: hail(func(m *pb.HailMsg) { m.State, m.Lat, m.Lon = "MP", "15.21", "145.75" }),
+			want:   0,
+		},
+		{
+			name:   "should pass a report from American Samoa south of the equator",
+			report: hail(func(m *pb.HailMsg) { m.State, m.Lat, m.Lon = "AS", "-14.28", "-170.70" }),
+			want:   0,
+		},
+		{
+			name:   "should flag an unknown state code",
+			report: hail(func(m *pb.HailMsg) { m.State = "XX" }),
+			want:   uint32(FlagStateCode),
+		},
+		{
+			name:   "should flag coordinates hundreds of miles from the state",
+			report: hail(func(m *pb.HailMsg) { m.State = "FL" }),
+			want:   uint32(FlagStateCoordinates),
+		},
+		{
+			name:   "should allow coordinates just across the state line",
+			report: hail(func(m *pb.HailMsg) { m.State, m.Lat = "TX", "37.10" }),
+			want:   0,
+		},
+		{
+			name:   "should flag a time in the future",
+			report: hail(func(m *pb.HailMsg) { m.Time = now.Add(time.Hour).Unix() }),
+			want:   uint32(FlagFutureTime),
+		},
+		{
+			name:   "should allow a time within the tolerance",
+			report: hail(func(m *pb.HailMsg) { m.Time = now.Add(5 * time.Minute).Unix() }),
+			want:   0,
+		},
+		{
+			name:   "should flag implausible hail",
+			report: hail(func(m *pb.HailMsg) { m.Size = 1750 }),
+			want:   uint32(FlagMagnitude),
+		},
+		{
+			name:   "should flag implausible wind",
+			report: &pb.WindMsg{Time: now.Unix(), Speed: 520, Location: "Norman", County: "Cleveland", State: "OK", Lat: "35.26", Lon: "-97.44"},
+			want:   uint32(FlagMagnitude),
+		},
+		{
+			name:   "should flag a tornado rating above EF5",
+			report: &pb.TornadoMsg{Time: now.Unix(), F_Scale: 6, Location: "Moore", County: "Cleveland", State: "OK", Lat: "35.33", Lon: "-97.48"},
+			want:   uint32(FlagMagnitude),
+		},
+		{
+			name:   "should flag an empty location",
+			report: hail(func(m *pb.HailMsg) { m.Location = " " }),
+			want:   uint32(FlagEmptyLocation),
+		},
+		{
+			name: "should not flag a historical database record without location columns",
+			report: hail(func(m *pb.HailMsg) {
+				m.Location, m.County, m.CountyFips, m.Impact = "", "", "40027", &pb.Impact{}
+			}),
+			want: 0,
+		},
+		{
+			name: "should flag a storm event without a location",
+			report: hail(func(m *pb.HailMsg) {
+				m.Location, m.Impact = "", &pb.Impact{}
+			}),
+			want: uint32(FlagEmptyLocation),
+		},
+		{
+			name: "should combine failed checks",
+			report: hail(func(m *pb.HailMsg) {
+				m.State, m.Lat, m.County = "", "", ""
+			}),
+			want: uint32(FlagCoordinates | FlagStateCode | FlagEmptyLocation),
+		},
+	}
+	c := newTestChecker(t, DefaultConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.Check(tt.report)
+			assert.Equal(t, tt.want, got, "Check() = %v, want %v", Names(got), Names(tt.want))
+		})
+	}
+}
+
+func TestChecker_DisabledChecks(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Checks = []string{CheckMagnitude}
+	c := newTestChecker(t, cfg)
+
+	got := c.Check(hail(func(m *pb.HailMsg) { m.State, m.Size = "XX", 900 }))
+	assert.Equal(t, uint32(FlagMagnitude), got)
+	assert.Equal(t, map[string]int64{CheckMagnitude: 0}, c.Counts())
+}
+
+func TestChecker_Apply(t *testing.T) {
+	c := newTestChecker(t, DefaultConfig())
+
+	msg := hail(func(m *pb.HailMsg) { m.State, m.Location = "KS", "" })
+	c.Apply(msg)
+	c.Apply(hail(nil))
+
+	assert.Equal(t, uint32(FlagStateCoordinates|FlagEmptyLocation), msg.GetQcFlags())
+	assert.Equal(t, []string{CheckStateCoordinates, CheckEmptyLocation}, msg.GetQcIssues())
+	assert.Equal(t, int64(2), c.Checked())
+	assert.Equal(t, int64(1), c.Counts()[CheckStateCoordinates])
+	assert.Equal(t, int64(1), c.Counts()[CheckEmptyLocation])
+	assert.Equal(t, int64(0), c.Counts()[CheckMagnitude])
+}
+
+func TestNewChecker_Invalid(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Checks = []string{"bogus"}
+	cfg.MaxHail = 0
+	cfg.Domain.MinLat = 80
+	cfg.Domain.MaxLon = 190
+
+	_, err := NewChecker(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown check "bogus"`)
+	assert.Contains(t, err.Error(), "domain min lat")
+	assert.Contains(t, err.Error(), "domain longitudes")
+	assert.Contains(t, err.Error(), "magnitude limits")
+}
+
+func TestLoadConfig(t *testing.T) {
+	cfg, err := LoadConfig(strings.NewReader("checks: [coordinates, magnitude]\nmax-wind: 150\nfuture-tolerance: 1h\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{CheckCoordinates, CheckMagnitude}, cfg.Checks)
+	assert.Equal(t, int32(150), cfg.MaxWind)
+	assert.Equal(t, time.Hour, cfg.FutureTolerance)
+	assert.Equal(t, int32(800), cfg.MaxHail)
+}
@@ -0,0 +1,256 @@
+// Package reload rebuilds the filter rules, alert rules, quality checks,
+// classification thresholds and log level from the config file and swaps them
+// into a running transformer, on SIGHUP or when the files they were read from change.
+//
+// A new version is only applied once the config and every rule file in it have
+// been read and validated, so a bad edit leaves the current version running.
+// Versions are named by a hash of the files they were built from.
+//
+// Topic routing is deliberately not reloaded. The summary, event and alert topics
+// each have their own writer and aggregator, correlator or alert state built at
+// startup, so a change to the topics is logged and waits for a restart.
+package reload
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/stormsync/transformer"
+	"github.com/stormsync/transformer/alert"
+	"github.com/stormsync/transformer/config"
+	"github.com/stormsync/transformer/filter"
+	"github.com/stormsync/transformer/qc"
+	"github.com/stormsync/transformer/report"
+)
+
+// Snapshot is a validated version of the reloadable settings.
+type Snapshot struct {
+	Version  string
+	Config   config.Config
+	Rules    transformer.Rules
+	LogLevel slog.Level
+
+	files []string // the files the snapshot was built from, in the order hashed
+}
+
+// Load reads the config file at path, applies the environment overrides looked up
+// with getenv, and builds the filter and alert rules and quality checker it names.
+// The classification thresholds are validated with the rest of the config. Every
+// problem found is returned together.
+func Load(path string, getenv func(string) string) (*Snapshot, error) {
+	cfg, err := config.Load(path, getenv)
+	if err != nil {
+		return nil, err
+	}
+	s := &Snapshot{Config: cfg, LogLevel: cfg.Log.Level}
+	if path != "" {
+		s.files = append(s.files, path)
+	}
+
+	var errs []error
+	if cfg.Rules.Filter != "" {
+		s.files = append(s.files, cfg.Rules.Filter)
+		f, err := loadFilter(cfg.Rules.Filter)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid filter rules: %w", err))
+		}
+		s.Rules.Filter = f
+	}
+	if cfg.Rules.Alerts != "" {
+		s.files = append(s.files, cfg.Rules.Alerts)
+		e, err := loadAlerts(cfg.Rules.Alerts)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid alert rules: %w", err))
+		}
+		s.Rules.Alerts = e
+	}
+	if cfg.Rules.QC != "" {
+		s.files = append(s.files, cfg.Rules.QC)
+	}
+	c, err := loadQC(cfg.Rules.QC)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("invalid qc config: %w", err))
+	}
+	s.Rules.QC = c
+	if cfg.Parsing.Classification {
+		th := cfg.Parsing.Thresholds
+		s.Rules.Thresholds = &th
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	if s.Version, err = digest(s.files); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func loadFilter(path string) (*filter.Filter, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read filter rules: %w", err)
+	}
+	rules, err := filter.LoadRules(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	return filter.NewFilter(rules)
+}
+
+func loadAlerts(path string) (*alert.Engine, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alert rules: %w", err)
+	}
+	rules, err := alert.LoadRules(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	return alert.NewEngine(rules)
+}
+
+// loadQC builds a quality checker from the config file at path. Every check is
+// run with its default limits when no path is given.
+func loadQC(path string) (*qc.Checker, error) {
+	if path == "" {
+		return qc.NewChecker(qc.DefaultConfig())
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read qc config: %w", err)
+	}
+	cfg, err := qc.LoadConfig(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	return qc.NewChecker(cfg)
+}
+
+// digest hashes the names and contents of the files into a short version.
+func digest(files []string) (string, error) {
+	h := sha256.New()
+	for _, name := range files {
+		b, err := os.ReadFile(name)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", name, len(b))
+		h.Write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12], nil
+}
+
+// Reloader loads new snapshots and applies them, keeping the current one when
+// loading or applying fails.
+type Reloader struct {
+	load   func() (*Snapshot, error)
+	apply  func(*Snapshot) error
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	current *Snapshot
+	failed  string // digest of the files that last failed to load
+}
+
+// NewReloader returns a reloader starting from the current snapshot, which must
+// already be applied. Reloads call load for the next snapshot and apply to swap it in.
+func NewReloader(current *Snapshot, load func() (*Snapshot, error), apply func(*Snapshot) error, logger *slog.Logger) *Reloader {
+	return &Reloader{load: load, apply: apply, logger: logger, current: current}
+}
+
+// Current returns the snapshot in use.
+func (r *Reloader) Current() *Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// Reload loads the next snapshot and applies it if its version differs from the
+// current one. On failure the current snapshot stays in use and the error is returned.
+func (r *Reloader) Reload(reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := r.load()
+	if err != nil {
+		r.failed = r.filesDigest()
+		r.logger.Error("reload failed, keeping the current config", "reason", reason, "version", r.current.Version, "error", err)
+		return err
+	}
+	r.failed = ""
+	if next.Version == r.current.Version {
+		r.logger.Info("config unchanged", "reason", reason, "version", next.Version)
+		return nil
+	}
+	if err := r.apply(next); err != nil {
+		r.logger.Error("reload failed, keeping the current config", "reason", reason, "version", r.current.Version, "rejected", next.Version, "error", err)
+		return err
+	}
+	if restartNeeded(r.current.Config, next.Config) {
+		r.logger.Warn("only filter rules, alert rules, quality checks, classification and the log level are reloaded, restart to apply the other changes such as topics", "version", next.Version)
+	}
+	r.logger.Info("config reloaded", "reason", reason, "version", next.Version, "previous", r.current.Version)
+	r.current = next
+	return nil
+}
+
+// changed reports whether the files of the current snapshot have changed since it
+// was loaded. Files that already failed to load are not reported again until they change.
+func (r *Reloader) changed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	v := r.filesDigest()
+	return v != r.current.Version && v != r.failed
+}
+
+// filesDigest returns the digest of the current snapshot's files as they are now.
+// r.mu must be held.
+func (r *Reloader) filesDigest() string {
+	v, err := digest(r.current.files)
+	if err != nil {
+		return "unreadable"
+	}
+	return v
+}
+
+// Run reloads on every signal received and, when interval is positive, whenever
+// the files change, until the context is done.
+func (r *Reloader) Run(ctx context.Context, signals <-chan os.Signal, interval time.Duration) {
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-signals:
+			r.Reload(sig.String())
+		case <-tick:
+			if r.changed() {
+				r.Reload("file changed")
+			}
+		}
+	}
+}
+
+// restartNeeded reports whether the configs differ in more than the reloadable settings.
+func restartNeeded(prev, next config.Config) bool {
+	for _, c := range []*config.Config{&prev, &next} {
+		c.Rules.Filter, c.Rules.Alerts, c.Rules.QC, c.Log.Level = "", "", "", 0
+		c.Parsing.Classification, c.Parsing.Thresholds = false, report.Thresholds{}
+	}
+	return !reflect.DeepEqual(prev, next)
+}
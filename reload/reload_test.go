@@ -0,0 +1,199 @@
+package reload
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	slogenv "github.com/cbrewster/slog-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stormsync/transformer/config"
+)
+
+var logger = slog.New(slogenv.NewHandler(slog.NewTextHandler(os.Stderr, nil)))
+
+const filterRules = `
+- name: sub-severe-hail
+  types: [Hail]
+  field: magnitude
+  op: lt
+  value: "100"
+`
+
+const alertRules = `
+- id: any-tornado
+  types: [Tornado]
+`
+
+// files writes a config file naming filter and alert rule files in a temp dir.
+type files struct {
+	dir string
+}
+
+func newFiles(t *testing.T, level string) files {
+	t.Helper()
+	f := files{dir: t.TempDir()}
+	f.write(t, "filter.yaml", filterRules)
+	f.write(t, "alerts.yaml", alertRules)
+	f.write(t, "transformer.yaml", "rules:\n  filter: "+f.path("filter.yaml")+"\n  alerts: "+f.path("alerts.yaml")+"\nlog:\n  level: "+level+"\n")
+	return f
+}
+
+func (f files) path(name string) string {
+	return filepath.Join(f.dir, name)
+}
+
+func (f files) write(t *testing.T, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(f.path(name), []byte(content), 0o600))
+}
+
+func (f files) load() (*Snapshot, error) {
+	return Load(f.path("transformer.yaml"), func(string) string { return "" })
+}
+
+func TestLoad(t *testing.T) {
+	f := newFiles(t, "debug")
+	s, err := f.load()
+	require.NoError(t, err)
+	assert.NotNil(t, s.Rules.Filter)
+	assert.NotNil(t, s.Rules.Alerts)
+	assert.NotNil(t, s.Rules.QC, "every check runs without a qc config")
+	assert.Equal(t, &s.Config.Parsing.Thresholds, s.Rules.Thresholds)
+	assert.Equal(t, slog.LevelDebug, s.LogLevel)
+	assert.Len(t, s.Version, 12)
+
+	again, err := f.load()
+	require.NoError(t, err)
+	assert.Equal(t, s.Version, again.Version, "the version only depends on the files")
+
+	f.write(t, "filter.yaml", filterRules+"- name: no-state\n  field: state\n  op: eq\n  value: \"\"\n")
+	changed, err := f.load()
+	require.NoError(t, err)
+	assert.NotEqual(t, s.Version, changed.Version)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	f := newFiles(t, "info")
+	f.write(t, "filter.yaml", "- name: bad\n  field: colour\n  op: eq\n  value: red\n")
+	f.write(t, "alerts.yaml", "- id: [not, a, string]\n")
+	_, err := f.load()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "invalid filter rules")
+	assert.ErrorContains(t, err, "invalid alert rules")
+
+	f = newFiles(t, "info")
+	f.write(t, "qc.yaml", "checks: [coordinates, horoscope]\n")
+	f.write(t, "transformer.yaml", "rules:\n  qc: "+f.path("qc.yaml")+"\nparsing:\n  thresholds:\n    significant-wind: 40\n")
+	_, err = f.load()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "invalid classification thresholds")
+
+	f.write(t, "transformer.yaml", "rules:\n  qc: "+f.path("qc.yaml")+"\n")
+	_, err = f.load()
+	assert.ErrorContains(t, err, `invalid qc config: unknown check "horoscope"`)
+
+	f = newFiles(t, "loud")
+	_, err = f.load()
+	assert.Error(t, err)
+}
+
+func TestReloader(t *testing.T) {
+	f := newFiles(t, "info")
+	initial, err := f.load()
+	require.NoError(t, err)
+
+	var applied []*Snapshot
+	var rejectApply bool
+	r := NewReloader(initial, f.load, func(s *Snapshot) error {
+		if rejectApply {
+			return errors.New("rejected")
+		}
+		applied = append(applied, s)
+		return nil
+	}, logger)
+
+	require.NoError(t, r.Reload("test"))
+	assert.Empty(t, applied, "an unchanged version is not applied again")
+	assert.False(t, r.changed())
+
+	// a bad edit is not applied, and is only reported once
+	f.write(t, "filter.yaml", "- name: bad\n  op: sometimes\n")
+	assert.True(t, r.changed())
+	assert.Error(t, r.Reload("test"))
+	assert.Same(t, initial, r.Current())
+	assert.False(t, r.changed(), "files that failed to load are not retried until they change")
+
+	f.write(t, "transformer.yaml", "rules:\n  filter: "+f.path("filter.yaml")+"\nlog:\n  level: debug\n")
+	f.write(t, "filter.yaml", filterRules)
+	assert.True(t, r.changed())
+	require.NoError(t, r.Reload("test"))
+	require.Len(t, applied, 1)
+	assert.Same(t, applied[0], r.Current())
+	assert.Equal(t, slog.LevelDebug, r.Current().LogLevel)
+	assert.Nil(t, r.Current().Rules.Alerts, "alert rules were removed from the config")
+	assert.NotEqual(t, initial.Version, r.Current().Version)
+
+	current := r.Current()
+	f.write(t, "transformer.yaml", "log:\n  level: warn\n")
+	rejectApply = true
+	assert.Error(t, r.Reload("test"))
+	assert.Same(t, current, r.Current())
+}
+
+func TestReloader_Run(t *testing.T) {
+	f := newFiles(t, "info")
+	initial, err := f.load()
+	require.NoError(t, err)
+
+	var applies atomic.Int32
+	r := NewReloader(initial, f.load, func(*Snapshot) error {
+		applies.Add(1)
+		return nil
+	}, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	go func() {
+		r.Run(ctx, signals, 5*time.Millisecond)
+		close(done)
+	}()
+
+	// picked up by the file check
+	f.write(t, "transformer.yaml", "rules:\n  filter: "+f.path("filter.yaml")+"\n")
+	require.Eventually(t, func() bool { return applies.Load() == 1 }, time.Second, time.Millisecond)
+
+	// a signal reloads even when the check has not run
+	signals <- syscall.SIGHUP
+	require.Eventually(t, func() bool { return len(signals) == 0 }, time.Second, time.Millisecond)
+
+	cancel()
+	<-done
+	assert.Equal(t, int32(1), applies.Load(), "the signal found nothing new to apply")
+}
+
+func TestRestartNeeded(t *testing.T) {
+	prev := config.Default()
+	next := prev
+	next.Rules.QC = "qc.yaml"
+	next.Parsing.Classification = false
+	next.Parsing.Thresholds.SevereHail = 75
+	next.Log.Level = slog.LevelDebug
+	assert.False(t, restartNeeded(prev, next), "quality checks, classification and the log level are reloaded")
+
+	next.Parsing.Remarks = !prev.Parsing.Remarks
+	assert.True(t, restartNeeded(prev, next))
+
+	next = prev
+	next.Topics.Alert = "weather-alerts-v2"
+	assert.True(t, restartNeeded(prev, next), "topic routing is not reloaded")
+}
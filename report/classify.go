@@ -0,0 +1,106 @@
+package report
+
+import (
+	"errors"
+
+	report "github.com/stormsync/transformer/proto"
+)
+
+// Thresholds are the magnitudes at which a report is considered severe or
+// significant severe. Hail is in hundredths of an inch, wind in knots, and
+// tornadoes in F/EF scale. Every tornado is at least severe.
+type Thresholds struct {
+	SevereHail         int32 `yaml:"severe-hail"`
+	SignificantHail    int32 `yaml:"significant-hail"`
+	SevereWind         int32 `yaml:"severe-wind"`
+	SignificantWind    int32 `yaml:"significant-wind"`
+	SignificantTornado int32 `yaml:"significant-tornado"`
+}
+
+// DefaultThresholds returns the NWS severe criteria of 1" hail and 50 kt wind,
+// and the SPC significant severe criteria of 2" hail, 65 kt wind and EF2 tornadoes.
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		SevereHail:         100,
+		SignificantHail:    200,
+		SevereWind:         50,
+		SignificantWind:    65,
+		SignificantTornado: 2,
+	}
+}
+
+// Validate makes sure each significant threshold is at or above its severe threshold.
+func (t Thresholds) Validate() error {
+	var errs []error
+	if t.SevereHail <= 0 || t.SignificantHail < t.SevereHail {
+		errs = append(errs, errors.New("hail thresholds must be positive with significant at or above severe"))
+	}
+	if t.SevereWind <= 0 || t.SignificantWind < t.SevereWind {
+		errs = append(errs, errors.New("wind thresholds must be positive with significant at or above severe"))
+	}
+	if t.SignificantTornado < 0 {
+		errs = append(errs, errors.New("significant tornado threshold cannot be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// Classify returns the severity category of the report. Hail and wind reports
+// with an unknown (UNK) magnitude are SEVERITY_UNKNOWN.
+func Classify(r Report, t Thresholds) report.Severity {
+	mag := Magnitude(r)
+	switch r.(type) {
+	case *report.HailMsg:
+		return classifyMagnitude(mag, t.SevereHail, t.SignificantHail)
+	case *report.WindMsg:
+		return classifyMagnitude(mag, t.SevereWind, t.SignificantWind)
+	case *report.TornadoMsg:
+		if mag >= t.SignificantTornado {
+			return report.Severity_SEVERITY_SIGNIFICANT
+		}
+		return report.Severity_SEVERITY_SEVERE
+	}
+	return report.Severity_SEVERITY_UNKNOWN
+}
+
+func classifyMagnitude(mag, severe, significant int32) report.Severity {
+	switch {
+	case mag <= 0:
+		return report.Severity_SEVERITY_UNKNOWN
+	case mag >= significant:
+		return report.Severity_SEVERITY_SIGNIFICANT
+	case mag >= severe:
+		return report.Severity_SEVERITY_SEVERE
+	default:
+		return report.Severity_SEVERITY_SUB_SEVERE
+	}
+}
+
+// ApplyClassification classifies the report and records the severity and
+// significant flag on it.
+func ApplyClassification(r Report, t Thresholds) report.Severity {
+	sev := Classify(r, t)
+	significant := sev == report.Severity_SEVERITY_SIGNIFICANT
+	switch m := r.(type) {
+	case *report.HailMsg:
+		m.Severity, m.Significant = sev, significant
+	case *report.WindMsg:
+		m.Severity, m.Significant = sev, significant
+	case *report.TornadoMsg:
+		m.Severity, m.Significant = sev, significant
+	}
+	return sev
+}
+
+// SeverityName returns the short name of a severity used in message headers,
+// such as "significant".
+func SeverityName(s report.Severity) string {
+	switch s {
+	case report.Severity_SEVERITY_SUB_SEVERE:
+		return "sub-severe"
+	case report.Severity_SEVERITY_SEVERE:
+		return "severe"
+	case report.Severity_SEVERITY_SIGNIFICANT:
+		return "significant"
+	}
+	return "unknown"
+}
@@ -0,0 +1,58 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	report "github.com/stormsync/transformer/proto"
+)
+
+func TestClassify(t *testing.T) {
+	th := DefaultThresholds()
+	tests := []struct {
+		name   string
+		report Report
+		want   report.Severity
+	}{
+		{name: "should be unknown for UNK hail", report: &report.HailMsg{Size: 0}, want: report.Severity_SEVERITY_UNKNOWN},
+		{name: "should be sub-severe for small hail", report: &report.HailMsg{Size: 75}, want: report.Severity_SEVERITY_SUB_SEVERE},
+		{name: "should be severe for quarter hail", report: &report.HailMsg{Size: 100}, want: report.Severity_SEVERITY_SEVERE},
+		{name: "should be significant for 2 inch hail", report: &report.HailMsg{Size: 200}, want: report.Severity_SEVERITY_SIGNIFICANT},
+		{name: "should be unknown for UNK wind", report: &report.WindMsg{Speed: 0}, want: report.Severity_SEVERITY_UNKNOWN},
+		{name: "should be sub-severe for 40 kt wind", report: &report.WindMsg{Speed: 40}, want: report.Severity_SEVERITY_SUB_SEVERE},
+		{name: "should be severe for 58 kt wind", report: &report.WindMsg{Speed: 58}, want: report.Severity_SEVERITY_SEVERE},
+		{name: "should be significant for 65 kt wind", report: &report.WindMsg{Speed: 65}, want: report.Severity_SEVERITY_SIGNIFICANT},
+		{name: "should be severe for an EF1 tornado", report: &report.TornadoMsg{F_Scale: 1}, want: report.Severity_SEVERITY_SEVERE},
+		{name: "should be significant for an EF2 tornado", report: &report.TornadoMsg{F_Scale: 2}, want: report.Severity_SEVERITY_SIGNIFICANT},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Classify(tt.report, th))
+		})
+	}
+}
+
+func TestApplyClassification(t *testing.T) {
+	th := DefaultThresholds()
+	th.SignificantHail = 275
+
+	msg := &report.HailMsg{Size: 200}
+	assert.Equal(t, report.Severity_SEVERITY_SEVERE, ApplyClassification(msg, th))
+	assert.Equal(t, report.Severity_SEVERITY_SEVERE, msg.GetSeverity())
+	assert.False(t, msg.GetSignificant())
+
+	msg.Size = 275
+	ApplyClassification(msg, th)
+	assert.Equal(t, report.Severity_SEVERITY_SIGNIFICANT, msg.GetSeverity())
+	assert.True(t, msg.GetSignificant())
+	assert.Equal(t, "significant", SeverityName(msg.GetSeverity()))
+}
+
+func TestThresholds_Validate(t *testing.T) {
+	assert.NoError(t, DefaultThresholds().Validate())
+
+	th := DefaultThresholds()
+	th.SignificantWind = 40
+	assert.EqualError(t, th.Validate(), "wind thresholds must be positive with significant at or above severe")
+}
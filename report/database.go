@@ -0,0 +1,353 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stormsync/collector"
+
+	report "github.com/stormsync/transformer/proto"
+)
+
+// Report types of the SPC historical severe weather database, the 1950 to present
+// files such as 1950-2023_actual_tornadoes.csv. Their lines are parsed into the
+// same messages as the daily reports, with the impact and tornado track filled in.
+const (
+	HistoricalHail    = "HistoricalHail"
+	HistoricalWind    = "HistoricalWind"
+	HistoricalTornado = "HistoricalTornado"
+)
+
+// Columns of the historical database files, after the record number in column 0.
+// Wind files add the magnitude type, and tornado files the F-scale modification
+// flag, after the last county.
+const (
+	dbYear = 1 + iota
+	dbMonth
+	dbDay
+	dbDate
+	dbTime
+	dbTimeZone
+	dbState
+	dbStateFIPS
+	dbStateNumber
+	dbMagnitude
+	dbInjuries
+	dbFatalities
+	dbLoss
+	dbCropLoss
+	dbStartLat
+	dbStartLon
+	dbEndLat
+	dbEndLon
+	dbLength
+	dbWidth
+	dbStates
+	dbStateFlag
+	dbSegment
+	dbCounty1
+	dbCounty2
+	dbCounty3
+	dbCounty4
+	dbColumns
+)
+
+// dbUnknown is the magnitude of a report whose magnitude was not recorded.
+const dbUnknown = -9
+
+// cst is the time zone of the database times that are not marked as GMT.
+var cst = time.FixedZone("CST", -6*60*60)
+
+// databaseParsers parse the lines of each historical report type.
+var databaseParsers = map[string]func(line []byte) (Report, error){
+	HistoricalHail: func(line []byte) (Report, error) {
+		msg, err := FromDatabaseLineToHailMsg(line)
+		return &msg, err
+	},
+	HistoricalWind: func(line []byte) (Report, error) {
+		msg, err := FromDatabaseLineToWindMsg(line)
+		return &msg, err
+	},
+	HistoricalTornado: func(line []byte) (Report, error) {
+		msg, err := FromDatabaseLineToTornadoMsg(line)
+		return &msg, err
+	},
+	StormEvents: FromStormEventsLine,
+}
+
+// DatabaseParser returns the parser of the lines of a historical report type,
+// and whether reportType is one.
+func DatabaseParser(reportType string) (func(line []byte) (Report, error), bool) {
+	parse, ok := databaseParsers[reportType]
+	return parse, ok
+}
+
+// databaseFilename matches the historical database file names, which start with
+// the range of years they cover.
+var databaseFilename = regexp.MustCompile(`^\d{4}-\d{4}_`)
+
+// DatabaseTypeFromFilename guesses the historical report type from a database
+// file name such as 1950-2023_actual_tornadoes.csv or 1955-2023_hail.csv, or a
+// Storm Events details file name.
+func DatabaseTypeFromFilename(name string) (string, bool) {
+	base := strings.ToLower(filepath.Base(name))
+	if strings.HasPrefix(base, "stormevents_details") {
+		return StormEvents, true
+	}
+	if !databaseFilename.MatchString(base) {
+		return "", false
+	}
+	switch {
+	case strings.Contains(base, "hail"):
+		return HistoricalHail, true
+	case strings.Contains(base, "wind"):
+		return HistoricalWind, true
+	case strings.Contains(base, "torn"):
+		return HistoricalTornado, true
+	}
+	return "", false
+}
+
+// DatabaseHeaderType reports whether the line is the header row of a historical
+// database file, "om,yr,mo,dy,..." or "BEGIN_YEARMONTH,BEGIN_DAY,...", and the
+// report type its columns are for.
+func DatabaseHeaderType(line string) (string, bool) {
+	cols := strings.Split(strings.ToLower(line), ",")
+	if strings.Trim(strings.TrimSpace(cols[0]), `"`) == "begin_yearmonth" {
+		return StormEvents, true
+	}
+	if len(cols) < dbColumns || strings.TrimSpace(cols[0]) != "om" {
+		return "", false
+	}
+	switch strings.TrimSpace(cols[len(cols)-1]) {
+	case "mt":
+		return HistoricalWind, true
+	case "fc":
+		return HistoricalTornado, true
+	}
+	return HistoricalHail, true
+}
+
+// FromDatabaseLineToHailMsg converts a line of the historical hail database. Its
+// magnitude is the hail size in inches, which becomes hundredths of an inch.
+func FromDatabaseLineToHailMsg(line []byte) (report.HailMsg, error) {
+	r, err := newDBRecord(line)
+	if err != nil {
+		return report.HailMsg{}, err
+	}
+	size := r.float(dbMagnitude, "mag")
+	if size == dbUnknown {
+		size = 0
+	}
+	t, county, impact := r.time(), r.countyFIPS(dbCounty1), r.impact()
+	if err := r.err(); err != nil {
+		return report.HailMsg{}, err
+	}
+	return report.HailMsg{
+		Type:       collector.Hail.String(),
+		Time:       t,
+		Size:       int32(math.Round(size * 100)),
+		State:      r.fields[dbState],
+		Lat:        r.fields[dbStartLat],
+		Lon:        r.fields[dbStartLon],
+		StateFips:  r.stateFIPS,
+		CountyFips: county,
+		Impact:     impact,
+	}, nil
+}
+
+// FromDatabaseLineToWindMsg converts a line of the historical wind database. Its
+// magnitude is the wind speed in knots.
+func FromDatabaseLineToWindMsg(line []byte) (report.WindMsg, error) {
+	r, err := newDBRecord(line)
+	if err != nil {
+		return report.WindMsg{}, err
+	}
+	var magType string
+	if len(r.fields) > dbColumns {
+		magType = r.fields[dbColumns]
+	}
+	t, speed, county, impact := r.time(), r.magnitude(), r.countyFIPS(dbCounty1), r.impact()
+	if err := r.err(); err != nil {
+		return report.WindMsg{}, err
+	}
+	return report.WindMsg{
+		Type:          collector.Wind.String(),
+		Time:          t,
+		Speed:         speed,
+		State:         r.fields[dbState],
+		Lat:           r.fields[dbStartLat],
+		Lon:           r.fields[dbStartLon],
+		StateFips:     r.stateFIPS,
+		CountyFips:    county,
+		Impact:        impact,
+		MagnitudeType: magType,
+	}, nil
+}
+
+// FromDatabaseLineToTornadoMsg converts a line of the historical tornado database,
+// including the track from its start to end point. Its magnitude is the F or EF scale.
+func FromDatabaseLineToTornadoMsg(line []byte) (report.TornadoMsg, error) {
+	r, err := newDBRecord(line)
+	if err != nil {
+		return report.TornadoMsg{}, err
+	}
+	track := &report.Track{
+		EndLat:      r.coordinate(dbEndLat),
+		EndLon:      r.coordinate(dbEndLon),
+		LengthMiles: r.float(dbLength, "len"),
+		WidthYards:  r.int(dbWidth, "wid"),
+		States:      r.int(dbStates, "ns"),
+		Segment:     r.int(dbSegment, "sg"),
+	}
+	county := r.countyFIPS(dbCounty1)
+	for _, fips := range []string{county, r.countyFIPS(dbCounty2), r.countyFIPS(dbCounty3), r.countyFIPS(dbCounty4)} {
+		if fips != "" {
+			track.CountyFips = append(track.CountyFips, fips)
+		}
+	}
+	t, scale, impact := r.time(), r.magnitude(), r.impact()
+	if err := r.err(); err != nil {
+		return report.TornadoMsg{}, err
+	}
+	return report.TornadoMsg{
+		Type:       collector.Tornado.String(),
+		Time:       t,
+		F_Scale:    scale,
+		State:      r.fields[dbState],
+		Lat:        r.fields[dbStartLat],
+		Lon:        r.fields[dbStartLon],
+		StateFips:  r.stateFIPS,
+		CountyFips: county,
+		Impact:     impact,
+		Track:      track,
+	}, nil
+}
+
+// record is a line of a CSV file whose columns are parsed one at a time. The
+// columns that cannot be parsed are recorded and returned together by err.
+type record struct {
+	fields []string
+	errs   []error
+}
+
+func (r *record) err() error {
+	return errors.Join(r.errs...)
+}
+
+// int parses the column as an integer. An empty column is zero.
+func (r *record) int(col int, name string) int32 {
+	if r.fields[col] == "" {
+		return 0
+	}
+	n, err := strconv.ParseInt(r.fields[col], 10, 32)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("invalid %s %q", name, r.fields[col]))
+	}
+	return int32(n)
+}
+
+// float parses the column as a number. An empty column is zero.
+func (r *record) float(col int, name string) float64 {
+	if r.fields[col] == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(r.fields[col], 64)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("invalid %s %q", name, r.fields[col]))
+	}
+	return f
+}
+
+// dbRecord is a line of a historical database file.
+type dbRecord struct {
+	record
+	stateFIPS string
+}
+
+func newDBRecord(line []byte) (*dbRecord, error) {
+	fields := strings.Split(string(line), ",")
+	if len(fields) < dbColumns {
+		return nil, fmt.Errorf("line did not contain at least %d columns", dbColumns)
+	}
+	for i, f := range fields {
+		fields[i] = strings.Trim(strings.TrimSpace(f), `"`)
+	}
+	r := &dbRecord{record: record{fields: fields}}
+	if stf := r.int(dbStateFIPS, "stf"); stf > 0 {
+		r.stateFIPS = fmt.Sprintf("%02d", stf)
+	}
+	return r, nil
+}
+
+// magnitude returns the magnitude column, with zero for an unknown magnitude as
+// the daily reports have for UNK.
+func (r *dbRecord) magnitude() int32 {
+	if m := r.int(dbMagnitude, "mag"); m != dbUnknown {
+		return m
+	}
+	return 0
+}
+
+// time returns the report time in UTC. Times are CST unless the time zone
+// column is 9 for GMT.
+func (r *dbRecord) time() int64 {
+	loc := cst
+	if r.fields[dbTimeZone] == "9" {
+		loc = time.UTC
+	}
+	hms := r.fields[dbTime]
+	if len(hms) == len("15:04") {
+		hms += ":00"
+	}
+	t, err := time.ParseInLocation(time.DateTime, r.fields[dbDate]+" "+hms, loc)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("invalid date and time %q %q", r.fields[dbDate], r.fields[dbTime]))
+		return 0
+	}
+	return t.UTC().Unix()
+}
+
+// coordinate returns the coordinate column, or an empty string when it was not recorded.
+func (r *dbRecord) coordinate(col int) string {
+	if f, err := strconv.ParseFloat(r.fields[col], 64); err == nil && f == 0 {
+		return ""
+	}
+	return r.fields[col]
+}
+
+// countyFIPS returns the five digit FIPS code of the county in the column, or an
+// empty string when there is none.
+func (r *dbRecord) countyFIPS(col int) string {
+	county := r.int(col, "county fips")
+	if r.stateFIPS == "" || county <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s%03d", r.stateFIPS, county)
+}
+
+// impact returns the casualties and losses. Property loss was recorded as a
+// category before 1996, in millions of dollars until 2016, and in dollars since,
+// as was crop loss.
+func (r *dbRecord) impact() *report.Impact {
+	imp := &report.Impact{
+		Injuries:   r.int(dbInjuries, "inj"),
+		Fatalities: r.int(dbFatalities, "fat"),
+	}
+	loss, cropLoss := r.float(dbLoss, "loss"), r.float(dbCropLoss, "closs")
+	switch year := r.int(dbYear, "yr"); {
+	case year < 1996:
+		imp.PropertyLossCategory = int32(loss)
+	case year < 2016:
+		imp.PropertyLoss, imp.CropLoss = loss*1e6, cropLoss*1e6
+	default:
+		imp.PropertyLoss, imp.CropLoss = loss, cropLoss
+	}
+	return imp
+}
@@ -0,0 +1,132 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	report "github.com/stormsync/transformer/proto"
+)
+
+func TestFromDatabaseLineToTornadoMsg(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want *report.TornadoMsg
+	}{
+		{
+			name: "early record in CST with a loss category",
+			line: "1,1950,1,3,1950-01-03,11:00:00,3,MO,29,1,3,3,0,6,0,38.77,-90.22,38.83,-90.03,9.5,150,2,0,1,0,0,0,0,0",
+			want: &report.TornadoMsg{
+				Type:      "Tornado",
+				Time:      time.Date(1950, 1, 3, 17, 0, 0, 0, time.UTC).Unix(),
+				F_Scale:   3,
+				State:     "MO",
+				Lat:       "38.77",
+				Lon:       "-90.22",
+				StateFips: "29",
+				Impact:    &report.Impact{Injuries: 3, PropertyLossCategory: 6},
+				Track:     &report.Track{EndLat: "38.83", EndLon: "-90.03", LengthMiles: 9.5, WidthYards: 150, States: 2, Segment: 1},
+			},
+		},
+		{
+			name: "recent record in GMT with losses in dollars and several counties",
+			line: "620412,2023,5,17,2023-05-17,17:35:00,9,TX,48,0,-9,2,1,150000,2500,32.36,-97.66,0,0,4.2,200,1,1,1,221,251,0,0,0",
+			want: &report.TornadoMsg{
+				Type:       "Tornado",
+				Time:       time.Date(2023, 5, 17, 17, 35, 0, 0, time.UTC).Unix(),
+				State:      "TX",
+				Lat:        "32.36",
+				Lon:        "-97.66",
+				StateFips:  "48",
+				CountyFips: "48221",
+				Impact:     &report.Impact{Injuries: 2, Fatalities: 1, PropertyLoss: 150000, CropLoss: 2500},
+				Track:      &report.Track{LengthMiles: 4.2, WidthYards: 200, States: 1, Segment: 1, CountyFips: []string{"48221", "48251"}},
+			},
+		},
+		{
+			name: "losses in millions between 1996 and 2015",
+			line: "100,2011,4,27,2011-04-27,15:05:00,3,AL,1,0,4,1500,64,1500,0.5,33.03,-87.97,33.63,-86.93,80.7,2600,1,1,1,125,0,0,0,0",
+			want: &report.TornadoMsg{
+				Type:       "Tornado",
+				Time:       time.Date(2011, 4, 27, 21, 5, 0, 0, time.UTC).Unix(),
+				F_Scale:    4,
+				State:      "AL",
+				Lat:        "33.03",
+				Lon:        "-87.97",
+				StateFips:  "01",
+				CountyFips: "01125",
+				Impact:     &report.Impact{Injuries: 1500, Fatalities: 64, PropertyLoss: 1.5e9, CropLoss: 5e5},
+				Track:      &report.Track{EndLat: "33.63", EndLon: "-86.93", LengthMiles: 80.7, WidthYards: 2600, States: 1, Segment: 1, CountyFips: []string{"01125"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromDatabaseLineToTornadoMsg([]byte(tt.line))
+			require.NoError(t, err)
+			assert.True(t, proto.Equal(tt.want, &got), "got %v", &got)
+		})
+	}
+}
+
+func TestFromDatabaseLineToHailAndWindMsg(t *testing.T) {
+	hail, err := FromDatabaseLineToHailMsg([]byte("1,1955,1,9,1955-01-09,15:30:00,3,GA,13,1,1.75,0,0,0,0,33.63,-84.45,0,0,0,0,1,1,1,63,0,0,0"))
+	require.NoError(t, err)
+	assert.Equal(t, int32(175), hail.GetSize())
+	assert.Equal(t, "13063", hail.GetCountyFips())
+	assert.Equal(t, time.Date(1955, 1, 9, 21, 30, 0, 0, time.UTC).Unix(), hail.GetTime())
+
+	wind, err := FromDatabaseLineToWindMsg([]byte("7,2020,6,1,2020-06-01,20:15:00,3,OK,40,0,65,1,0,5000,0,35.5,-97.5,0,0,0,0,1,1,1,109,0,0,0,MG"))
+	require.NoError(t, err)
+	assert.Equal(t, int32(65), wind.GetSpeed())
+	assert.Equal(t, "MG", wind.GetMagnitudeType())
+	assert.Equal(t, float64(5000), wind.GetImpact().GetPropertyLoss())
+	assert.Equal(t, int32(1), wind.GetImpact().GetInjuries())
+
+	_, err = FromDatabaseLineToWindMsg([]byte("7,2020,6,1,2020-06-01"))
+	assert.EqualError(t, err, "line did not contain at least 28 columns")
+	_, err = FromDatabaseLineToHailMsg([]byte("1,1955,1,9,1955-01-09,noon,3,GA,13,1,big,0,0,0,0,33.63,-84.45,0,0,0,0,1,1,1,63,0,0,0"))
+	assert.EqualError(t, err, "invalid mag \"big\"\ninvalid date and time \"1955-01-09\" \"noon\"")
+}
+
+func TestDatabaseTypes(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{name: "1950-2023_actual_tornadoes.csv", want: HistoricalTornado, wantOK: true},
+		{name: "/data/1955-2023_hail.csv", want: HistoricalHail, wantOK: true},
+		{name: "1955-2023_wind.csv", want: HistoricalWind, wantOK: true},
+		{name: "240517_rpts_hail.csv", wantOK: false},
+		{name: "1955-2023_all.csv", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := DatabaseTypeFromFilename(tt.name)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	header := "om,yr,mo,dy,date,time,tz,st,stf,stn,mag,inj,fat,loss,closs,slat,slon,elat,elon,len,wid,ns,sn,sg,f1,f2,f3,f4"
+	for suffix, want := range map[string]string{"": HistoricalHail, ",mt": HistoricalWind, ",fc": HistoricalTornado} {
+		got, ok := DatabaseHeaderType(header + suffix)
+		assert.True(t, ok)
+		assert.Equal(t, want, got)
+	}
+	_, ok := DatabaseHeaderType("Time,Size,Location,County,State,Lat,Lon,Comments")
+	assert.False(t, ok)
+
+	parse, ok := DatabaseParser(HistoricalTornado)
+	require.True(t, ok)
+	r, err := parse([]byte("1,1950,1,3,1950-01-03,11:00:00,3,MO,29,1,3,3,0,6,0,38.77,-90.22,38.83,-90.03,9.5,150,2,0,1,0,0,0,0,0"))
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), Magnitude(r))
+	_, ok = DatabaseParser("Tornado")
+	assert.False(t, ok)
+}
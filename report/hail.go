@@ -37,14 +37,24 @@ var (
 )
 
 func FromCSVLineToHailMsg(line []byte) (report.HailMsg, error) {
-	words := strings.Split(string(line), ",")
+	return hailMsgFromCSVLine(line, today)
+}
+
+// FromCSVLineToHailMsgOn converts a line of the hail reports of an SPC convective
+// day, such as a line of 240517_rpts_hail.csv, dating it with ConvectiveDayTime.
+func FromCSVLineToHailMsgOn(line []byte, day time.Time) (report.HailMsg, error) {
+	return hailMsgFromCSVLine(line, onConvectiveDay(day))
+}
+
+func hailMsgFromCSVLine(line []byte, toTime func(hhmm string) int64) (report.HailMsg, error) {
+	words := strings.SplitN(string(line), ",", 8)
 	if len(words) < 8 {
 		return report.HailMsg{}, errors.New("line did not contain at least 8 columns")
 	}
 	distance, direction, location := GetDistanceFromLocation(words[2])
 	return report.HailMsg{
 		Type:      collector.Hail.String(),
-		Time:      StringToUnixTime(time.Now().UTC().Format(time.DateOnly), words[0]),
+		Time:      toTime(words[0]),
 		Size:      StringToInt32(words[1]),
 		Distance:  distance,
 		Direction: direction,
@@ -114,3 +124,38 @@ func StringToUnixTime(dateOnly string, hhmm string) int64 {
 	return newTime.UTC().Unix()
 
 }
+
+// ConvectiveDayTime builds the UTC timestamp of a report line's hhmm field on an
+// SPC convective day, which runs from 1200Z on its date to 1200Z the next day.
+// Times before 1200 are therefore on the calendar date after day.
+func ConvectiveDayTime(day time.Time, hhmm string) int64 {
+	date := day.UTC()
+	if len(hhmm) >= 4 && hhmm[:4] < "1200" {
+		date = date.AddDate(0, 0, 1)
+	}
+	return StringToUnixTime(date.Format(time.DateOnly), hhmm)
+}
+
+// today dates a report line of the live reports on the convective day in
+// progress, so evening reports read after 0000Z keep the previous date rather
+// than landing hours in the future.
+func today(hhmm string) int64 {
+	return ConvectiveDayTime(CurrentConvectiveDay(time.Now()), hhmm)
+}
+
+// CurrentConvectiveDay returns the date of the SPC convective day in progress at
+// now, the day before the UTC date until 1200Z.
+func CurrentConvectiveDay(now time.Time) time.Time {
+	now = now.UTC()
+	if now.Hour() < 12 {
+		now = now.AddDate(0, 0, -1)
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// onConvectiveDay dates report lines on the convective day.
+func onConvectiveDay(day time.Time) func(string) int64 {
+	return func(hhmm string) int64 {
+		return ConvectiveDayTime(day, hhmm)
+	}
+}
@@ -2,8 +2,10 @@ package report
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestToUnixTime(t *testing.T) {
@@ -105,3 +107,90 @@ func TestGetDistanceFromLocation(t *testing.T) {
 		})
 	}
 }
+
+func TestConvectiveDayTime(t *testing.T) {
+	day := time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		hhmm string
+		want string
+	}{
+		{name: "afternoon is on the day", hhmm: "1835", want: "2024-05-17T18:35:00Z"},
+		{name: "start of the day", hhmm: "1200", want: "2024-05-17T12:00:00Z"},
+		{name: "after midnight is on the next date", hhmm: "0130", want: "2024-05-18T01:30:00Z"},
+		{name: "end of the day", hhmm: "1159", want: "2024-05-18T11:59:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ConvectiveDayTime(day, tt.hhmm)
+			assert.Equal(t, tt.want, time.Unix(got, 0).UTC().Format(time.RFC3339))
+		})
+	}
+	assert.Zero(t, ConvectiveDayTime(day, "130"))
+
+	msg, err := FromCSVLineToHailMsgOn([]byte("0130,100,2 W Ralston,Douglas,NE,41.21,-96.08,Quarter hail. (OAX)"), day)
+	require.NoError(t, err)
+	assert.Equal(t, ConvectiveDayTime(day, "0130"), msg.GetTime())
+}
+
+func TestFromCSVLine_RemarksWithCommas(t *testing.T) {
+	const remarks = "Large limbs down, shingles off a roof, and a carport destroyed. (OAX)"
+	tests := []struct {
+		name  string
+		parse func([]byte) (Report, error)
+		line  string
+	}{
+		{
+			name: "hail",
+			parse: func(line []byte) (Report, error) {
+				msg, err := FromCSVLineToHailMsg(line)
+				return &msg, err
+			},
+			line: "1830,100,2 W Ralston,Douglas,NE,41.21,-96.08," + remarks,
+		},
+		{
+			name: "wind",
+			parse: func(line []byte) (Report, error) {
+				msg, err := FromCSVLineToWindMsg(line)
+				return &msg, err
+			},
+			line: "1830,UNK,2 W Ralston,Douglas,NE,41.21,-96.08," + remarks,
+		},
+		{
+			name: "tornado",
+			parse: func(line []byte) (Report, error) {
+				msg, err := FromCSVLineToTornadoMsg(line)
+				return &msg, err
+			},
+			line: "1830,UNK,2 W Ralston,Douglas,NE,41.21,-96.08," + remarks,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := tt.parse([]byte(tt.line))
+			require.NoError(t, err)
+			assert.Equal(t, remarks, r.GetRemarks())
+			assert.Equal(t, "OAX", ApplyRemarks(r).GetOffice(), "the office tag after the commas is found")
+		})
+	}
+}
+
+func TestCurrentConvectiveDay(t *testing.T) {
+	tests := []struct {
+		now  time.Time
+		want string
+	}{
+		{now: time.Date(2024, 5, 18, 3, 0, 0, 0, time.UTC), want: "2024-05-17"},
+		{now: time.Date(2024, 5, 18, 12, 0, 0, 0, time.UTC), want: "2024-05-18"},
+		{now: time.Date(2024, 5, 17, 22, 0, 0, 0, time.FixedZone("CDT", -5*60*60)), want: "2024-05-17"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.now.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, CurrentConvectiveDay(tt.now).Format(time.DateOnly))
+		})
+	}
+
+	now := time.Date(2024, 5, 18, 3, 0, 0, 0, time.UTC)
+	got := time.Unix(ConvectiveDayTime(CurrentConvectiveDay(now), "1830"), 0)
+	assert.True(t, got.Before(now), "an evening report read after 0000Z is dated the evening before, not in the future")
+}
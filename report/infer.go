@@ -0,0 +1,154 @@
+package report
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	report "github.com/stormsync/transformer/proto"
+)
+
+type descriptor struct {
+	re    *regexp.Regexp
+	value int32
+}
+
+var (
+	// explicitHailRe matches sizes such as "1.75 inch", "(1.00 in.)" and `2"`.
+	explicitHailRe = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?|\.\d+)\s*(?:INCHES|INCH|IN\b|")`)
+
+	// hailDescriptors follow the NWS hail size chart, in hundredths of an inch.
+	hailDescriptors = []descriptor{
+		{re: regexp.MustCompile(`(?i)\bPEAS?\b`), value: 25},
+		{re: regexp.MustCompile(`(?i)\b(MARBLES?|MOTHBALLS?)\b`), value: 50},
+		{re: regexp.MustCompile(`(?i)\bDIMES?\b`), value: 70},
+		{re: regexp.MustCompile(`(?i)\bPENN(Y|IES)\b`), value: 75},
+		{re: regexp.MustCompile(`(?i)\bNICKELS?\b`), value: 88},
+		{re: regexp.MustCompile(`(?i)\bQUARTERS?\b`), value: 100},
+		{re: regexp.MustCompile(`(?i)\bHALF\s+DOLLARS?\b`), value: 125},
+		{re: regexp.MustCompile(`(?i)\b(WALNUTS?|PING\s*PONG(\s+BALLS?)?)\b`), value: 150},
+		{re: regexp.MustCompile(`(?i)\bGOLF\s*BALLS?\b`), value: 175},
+		{re: regexp.MustCompile(`(?i)\b(HEN\s+)?EGGS?\b`), value: 200},
+		{re: regexp.MustCompile(`(?i)\bTENNIS\s*BALLS?\b`), value: 250},
+		{re: regexp.MustCompile(`(?i)\bBASEBALLS?\b`), value: 275},
+		{re: regexp.MustCompile(`(?i)\bTEA\s*CUPS?\b`), value: 300},
+		{re: regexp.MustCompile(`(?i)\bGRAPEFRUITS?\b`), value: 400},
+		{re: regexp.MustCompile(`(?i)\bSOFTBALLS?\b`), value: 450},
+	}
+
+	// explicitWindRe matches speeds such as "70 mph" and "58 kts".
+	explicitWindRe = regexp.MustCompile(`(?i)\b(\d{2,3})\s*(MPH|KTS?|KNOTS?)\b`)
+
+	// windDescriptors estimate wind speed in knots from the damage described,
+	// following the NWS damage based estimates used for wind reports.
+	windDescriptors = []descriptor{
+		{re: regexp.MustCompile(`(?i)\bSMALL\s+(TREE\s+)?(LIMBS?|BRANCH(ES)?)\b`), value: 35},
+		{re: regexp.MustCompile(`(?i)\bLARGE\s+(TREE\s+)?(LIMBS?|BRANCH(ES)?)\b`), value: 50},
+		{re: regexp.MustCompile(`(?i)\b(TREES?\s+(WERE\s+)?(DOWN|DOWNED|BLOWN\s+DOWN|KNOCKED\s+DOWN|BLOWN\s+OVER)|DOWNED\s+TREES?)\b`), value: 50},
+		{re: regexp.MustCompile(`(?i)\b((POWER\s*)?LINES?\s+(WERE\s+)?DOWN|DOWNED\s+(POWER\s*)?LINES?)\b`), value: 50},
+		{re: regexp.MustCompile(`(?i)\b(UPROOTED|SNAPPED)\b`), value: 55},
+		{re: regexp.MustCompile(`(?i)\b(SHINGLES?|ROOF\s+DAMAGE|DAMAGED\s+ROOFS?)\b`), value: 55},
+		{re: regexp.MustCompile(`(?i)\b(ROOFS?\s+(WAS\s+|WERE\s+)?(BLOWN|TORN|RIPPED)\s+OFF|(LOST|LOSS\s+OF)\s+(THE\s+|ITS\s+|A\s+)?ROOF)\b`), value: 70},
+		{re: regexp.MustCompile(`(?i)\b(MOBILE\s+HOMES?\s+(WAS\s+|WERE\s+)?(OVERTURNED|ROLLED|DESTROYED)|(SEMIS?|TRACTOR\s+TRAILERS?|TRUCKS?)\s+(WAS\s+|WERE\s+)?(BLOWN\s+OVER|OVERTURNED))\b`), value: 70},
+	}
+
+	tornadoRatingRe = regexp.MustCompile(`(?i)\bE?F([0-5])\b`)
+)
+
+// knotsPerMPH converts miles per hour into knots.
+const knotsPerMPH = 0.868976
+
+// InferMagnitude estimates the magnitude of a report from its remarks when the
+// magnitude column is UNK. It returns nil when the column has a magnitude or the
+// remarks do not describe one.
+//
+// Hail sizes written out in inches, or wind speeds in mph or knots, are high
+// confidence. Hail described by an object from the NWS size chart is medium
+// confidence and wind estimated from damage is low confidence. Tornado ratings
+// mentioned in the remarks are medium confidence. Since an EF0 rating and an UNK
+// column both parse to zero, tornado remarks are checked for either.
+func InferMagnitude(r Report) *report.InferredMagnitude {
+	if Magnitude(r) > 0 {
+		return nil
+	}
+	remarks := r.GetRemarks()
+	switch r.(type) {
+	case *report.HailMsg:
+		if m := explicitHailRe.FindAllStringSubmatch(remarks, -1); m != nil {
+			var best float64
+			var source string
+			for _, match := range m {
+				if v, err := strconv.ParseFloat(match[1], 64); err == nil && v > best && v <= 8 {
+					best, source = v, match[0]
+				}
+			}
+			if best > 0 {
+				return &report.InferredMagnitude{Value: int32(math.Round(best * 100)), Confidence: report.Confidence_CONFIDENCE_HIGH, Source: strings.TrimSpace(source)}
+			}
+		}
+		return matchDescriptors(hailDescriptors, remarks, report.Confidence_CONFIDENCE_MEDIUM)
+	case *report.WindMsg:
+		if m := explicitWindRe.FindAllStringSubmatch(remarks, -1); m != nil {
+			var best int32
+			var source string
+			for _, match := range m {
+				n, err := strconv.Atoi(match[1])
+				if err != nil {
+					continue
+				}
+				kt := float64(n)
+				if strings.EqualFold(match[2], "MPH") {
+					kt *= knotsPerMPH
+				}
+				if v := int32(math.Round(kt)); v > best {
+					best, source = v, match[0]
+				}
+			}
+			if best > 0 {
+				return &report.InferredMagnitude{Value: best, Confidence: report.Confidence_CONFIDENCE_HIGH, Source: source}
+			}
+		}
+		return matchDescriptors(windDescriptors, remarks, report.Confidence_CONFIDENCE_LOW)
+	case *report.TornadoMsg:
+		var inferred *report.InferredMagnitude
+		for _, match := range tornadoRatingRe.FindAllStringSubmatch(remarks, -1) {
+			n, _ := strconv.Atoi(match[1])
+			if inferred == nil || int32(n) > inferred.Value {
+				inferred = &report.InferredMagnitude{Value: int32(n), Confidence: report.Confidence_CONFIDENCE_MEDIUM, Source: match[0]}
+			}
+		}
+		return inferred
+	}
+	return nil
+}
+
+// matchDescriptors returns the largest value of the descriptors found in the remarks.
+func matchDescriptors(descriptors []descriptor, remarks string, confidence report.Confidence) *report.InferredMagnitude {
+	var inferred *report.InferredMagnitude
+	for _, d := range descriptors {
+		m := d.re.FindString(remarks)
+		if m == "" {
+			continue
+		}
+		if inferred == nil || d.value > inferred.Value {
+			inferred = &report.InferredMagnitude{Value: d.value, Confidence: confidence, Source: m}
+		}
+	}
+	return inferred
+}
+
+// ApplyInference infers the magnitude of the report from its remarks and records
+// it on the report. The magnitude column is left as it is.
+func ApplyInference(r Report) *report.InferredMagnitude {
+	inferred := InferMagnitude(r)
+	switch m := r.(type) {
+	case *report.HailMsg:
+		m.InferredMagnitude = inferred
+	case *report.WindMsg:
+		m.InferredMagnitude = inferred
+	case *report.TornadoMsg:
+		m.InferredMagnitude = inferred
+	}
+	return inferred
+}
@@ -0,0 +1,72 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+
+	report "github.com/stormsync/transformer/proto"
+)
+
+func TestInferMagnitude(t *testing.T) {
+	tests := []struct {
+		name   string
+		report Report
+		want   *report.InferredMagnitude
+	}{
+		{
+			name:   "should not infer when the column has a magnitude",
+			report: &report.HailMsg{Size: 100, Remarks: "Golf ball size hail. (OAX)"},
+			want:   nil,
+		},
+		{
+			name:   "should take an explicit hail size in inches",
+			report: &report.HailMsg{Remarks: "Report from mPING: Quarter (1.00 in.). (OAX)"},
+			want:   &report.InferredMagnitude{Value: 100, Confidence: report.Confidence_CONFIDENCE_HIGH, Source: "1.00 in"},
+		},
+		{
+			name:   "should map a hail descriptor to its diameter",
+			report: &report.HailMsg{Remarks: "Pea to golf ball size hail covering the ground. (FWD)"},
+			want:   &report.InferredMagnitude{Value: 175, Confidence: report.Confidence_CONFIDENCE_MEDIUM, Source: "golf ball"},
+		},
+		{
+			name:   "should convert an explicit wind speed in mph to knots",
+			report: &report.WindMsg{Remarks: "Estimated 70 mph wind gust. (OUN)"},
+			want:   &report.InferredMagnitude{Value: 61, Confidence: report.Confidence_CONFIDENCE_HIGH, Source: "70 mph"},
+		},
+		{
+			name:   "should estimate wind from large limbs down",
+			report: &report.WindMsg{Remarks: "Large tree limbs down. (TAE)"},
+			want:   &report.InferredMagnitude{Value: 50, Confidence: report.Confidence_CONFIDENCE_LOW, Source: "Large tree limbs"},
+		},
+		{
+			name:   "should use the strongest wind damage described",
+			report: &report.WindMsg{Remarks: "Trees down and roof blown off a house. (LZK)"},
+			want:   &report.InferredMagnitude{Value: 70, Confidence: report.Confidence_CONFIDENCE_LOW, Source: "roof blown off"},
+		},
+		{
+			name:   "should take the highest tornado rating mentioned",
+			report: &report.TornadoMsg{Remarks: "EF0 tree damage was confirmed in Jefferson county with EF1 dam (TAE)"},
+			want:   &report.InferredMagnitude{Value: 1, Confidence: report.Confidence_CONFIDENCE_MEDIUM, Source: "EF1"},
+		},
+		{
+			name:   "should return nil when nothing is described",
+			report: &report.WindMsg{Remarks: "Report relayed by broadcast media. (BMX)"},
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := InferMagnitude(tt.report)
+			assert.True(t, proto.Equal(tt.want, got), "InferMagnitude() = %v, want %v", got, tt.want)
+		})
+	}
+}
+
+func TestApplyInference(t *testing.T) {
+	msg := &report.HailMsg{Remarks: "Baseball size hail. (LBF)"}
+	ApplyInference(msg)
+	assert.Equal(t, int32(0), msg.GetSize())
+	assert.Equal(t, int32(275), msg.GetInferredMagnitude().GetValue())
+}
@@ -0,0 +1,367 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stormsync/collector"
+
+	report "github.com/stormsync/transformer/proto"
+)
+
+// LSR is the report type of the reports in NWS Local Storm Report text products.
+// Unlike the CSV report types, each report is a block of fixed-width lines that
+// is parsed along with the issuing office and time of its product. It is also the
+// type of the LSR messages of events other than hail, wind and tornadoes.
+const LSR = "LSR"
+
+var (
+	// lsrProductID matches the AWIPS ID line of an LSR product, LSR and the office.
+	lsrProductID = regexp.MustCompile(`^LSR([A-Z0-9]{3})$`)
+	// lsrWMOHeading matches the WMO heading of an LSR product, such as NWUS54 KOUN 200130.
+	lsrWMOHeading = regexp.MustCompile(`^NWUS\d\d [A-Z]([A-Z]{3}) \d{6}`)
+	// lsrIssuedLine matches the issuance time of a product, such as 830 PM CDT SUN MAY 19 2024.
+	lsrIssuedLine = regexp.MustCompile(`^(\d{1,4}) (AM|PM) ([A-Z]{3,4}) [A-Z]{3} ([A-Z]{3}) (\d{1,2}) (\d{4})$`)
+	// lsrEntryStart matches the first line of each report, which starts with its time.
+	lsrEntryStart = regexp.MustCompile(`^\d{4} (AM|PM) `)
+	// lsrMagnitude matches a magnitude such as E1.75 INCH or M65 MPH.
+	lsrMagnitude = regexp.MustCompile(`^([EMU])?\s*(\d+(?:\.\d+)?)\s*([A-Z]*)$`)
+)
+
+// lsrEvents normalizes the event types of LSR products. Hail, thunderstorm wind
+// and tornado events become the Hail, Wind and Tornado report types; the others
+// are the event types of LSR messages.
+var lsrEvents = map[string]string{
+	"HAIL":             collector.Hail.String(),
+	"MARINE HAIL":      collector.Hail.String(),
+	"TSTM WND GST":     collector.Wind.String(),
+	"TSTM WND DMG":     collector.Wind.String(),
+	"MARINE TSTM WIND": collector.Wind.String(),
+	"DOWNBURST":        collector.Wind.String(),
+	"TORNADO":          collector.Tornado.String(),
+	"NON-TSTM WND GST": "High Wind",
+	"NON-TSTM WND DMG": "High Wind",
+	"HIGH SUST WINDS":  "High Wind",
+	"FLOOD":            "Flood",
+	"FLASH FLOOD":      "Flood",
+	"COASTAL FLOOD":    "Flood",
+	"LAKESHORE FLOOD":  "Flood",
+	"HEAVY RAIN":       "Rain",
+	"RAIN":             "Rain",
+	"SNOW":             "Snow",
+	"HEAVY SNOW":       "Snow",
+	"BLIZZARD":         "Snow",
+	"SNOW SQUALL":      "Snow",
+	"FREEZING RAIN":    "Ice",
+	"SLEET":            "Ice",
+	"ICE STORM":        "Ice",
+}
+
+// LSREventType normalizes the event type of an LSR, such as TSTM WND GST, to a
+// report type such as Wind. Event types it does not know are returned as written.
+func LSREventType(event string) string {
+	event = strings.ToUpper(strings.Join(strings.Fields(event), " "))
+	if t, ok := lsrEvents[event]; ok {
+		return t
+	}
+	return event
+}
+
+// LSRProduct is an NWS Local Storm Report text product split into its reports.
+type LSRProduct struct {
+	// Office is the issuing weather forecast office, such as OUN, when the product
+	// has an AWIPS ID or WMO heading.
+	Office string
+	// Issued is the issuance time of the product, in the time zone of its reports.
+	Issued  time.Time
+	Entries []LSREntry
+}
+
+// LSREntry is the block of lines of one report in an LSR product.
+type LSREntry struct {
+	// Line is the line of the product the report starts on, counting from 1.
+	Line int
+	Text []byte
+}
+
+// IsLSRProductStart reports whether the line is the WMO heading, AWIPS ID or
+// title of an LSR product, which the rest of its lines follow.
+func IsLSRProductStart(line string) bool {
+	line = strings.TrimSpace(line)
+	return lsrWMOHeading.MatchString(line) || lsrProductID.MatchString(line) ||
+		strings.Contains(line, "LOCAL STORM REPORT")
+}
+
+// ParseLSRProduct reads the office and issuance time of an LSR product and splits
+// it into the entries of its reports. A product may hold any number of reports,
+// including none, and ends at the $$ line.
+func ParseLSRProduct(text []byte) (LSRProduct, error) {
+	var p LSRProduct
+	var entry *LSREntry
+	var found bool
+	scanner := bufio.NewScanner(bytes.NewReader(text))
+	for n := 1; scanner.Scan(); n++ {
+		raw := strings.TrimRight(scanner.Text(), " \r")
+		line := strings.TrimSpace(raw)
+		if line == "$$" {
+			break
+		}
+		switch {
+		case p.Office == "" && lsrProductID.MatchString(line):
+			p.Office = lsrProductID.FindStringSubmatch(line)[1]
+		case p.Office == "" && lsrWMOHeading.MatchString(line):
+			p.Office = lsrWMOHeading.FindStringSubmatch(line)[1]
+		case !found && lsrIssuedLine.MatchString(line):
+			issued, err := lsrIssued(lsrIssuedLine.FindStringSubmatch(line))
+			if err != nil {
+				return LSRProduct{}, err
+			}
+			p.Issued, found = issued, true
+		case line == "&&":
+			entry = nil
+		case lsrEntryStart.MatchString(raw):
+			p.Entries = append(p.Entries, LSREntry{Line: n, Text: []byte(raw)})
+			entry = &p.Entries[len(p.Entries)-1]
+		case entry != nil && line != "":
+			entry.Text = append(append(entry.Text, '\n'), raw...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return LSRProduct{}, fmt.Errorf("unable to read LSR product: %w", err)
+	}
+	if !found {
+		return LSRProduct{}, errors.New("LSR product has no issuance time such as 830 PM CDT SUN MAY 19 2024")
+	}
+	return p, nil
+}
+
+// Reports parses every entry of the product. The errors of the entries that
+// cannot be parsed are returned together.
+func (p LSRProduct) Reports() ([]Report, error) {
+	var reports []Report
+	var errs []error
+	for _, e := range p.Entries {
+		r, err := FromLSREntry(e.Text, p.Office, p.Issued)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", e.Line, err))
+			continue
+		}
+		reports = append(reports, r)
+	}
+	return reports, errors.Join(errs...)
+}
+
+// lsrIssued parses the issuance line matched by lsrIssuedLine into a time in the
+// product's time zone.
+func lsrIssued(match []string) (time.Time, error) {
+	hours, ok := zoneOffsets[match[3]]
+	if !ok {
+		return time.Time{}, fmt.Errorf("unknown time zone %q", match[3])
+	}
+	loc := time.FixedZone(match[3], hours*60*60)
+	hhmm := strings.Repeat("0", 4-len(match[1])) + match[1]
+	t, err := time.ParseInLocation("0304 PM Jan 2 2006", strings.Join([]string{hhmm, match[2],
+		match[4][:1] + strings.ToLower(match[4][1:]), match[5], match[6]}, " "), loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid issuance time %q", strings.Join(match[1:], " "))
+	}
+	return t, nil
+}
+
+// Columns of the two fixed-width lines that start each LSR entry.
+const (
+	lsrTimeEnd   = 12 // time, then date
+	lsrEventEnd  = 29 // event, then magnitude
+	lsrCityEnd   = 53 // city, then county and state
+	lsrCountyEnd = 48
+	lsrStateEnd  = 53
+)
+
+// FromLSREntry converts an entry of an LSR product into the report message of its
+// event type, with the office and issuance time of its product. Its times are in
+// the time zone of issued. Events other than hail, thunderstorm wind and
+// tornadoes, such as floods and snow, become LSR messages.
+func FromLSREntry(entry []byte, office string, issued time.Time) (Report, error) {
+	lines := strings.Split(string(entry), "\n")
+	if len(lines) < 2 {
+		return nil, errors.New("LSR entry did not contain its time and date lines")
+	}
+	first, second := lines[0], lines[1]
+
+	event := column(first, lsrTimeEnd, lsrEventEnd)
+	rptType := LSREventType(event)
+
+	t, err := time.ParseInLocation("0304 PM 01/02/2006", column(first, 0, lsrTimeEnd)+" "+column(second, 0, lsrTimeEnd), issued.Location())
+	if err != nil {
+		return nil, fmt.Errorf("invalid time and date %q %q", column(first, 0, lsrTimeEnd), column(second, 0, lsrTimeEnd))
+	}
+	lat, lon, err := lsrLatLon(column(first, lsrCityEnd, len(first)))
+	if err != nil {
+		return nil, err
+	}
+	qualifier, magnitude, unit, err := lsrMagnitudeOf(column(second, lsrTimeEnd, lsrEventEnd))
+	if err != nil {
+		return nil, err
+	}
+
+	var remarks []string
+	for _, l := range lines[2:] {
+		if r := column(l, 0, len(l)); r != "" {
+			remarks = append(remarks, r)
+		}
+	}
+	distance, direction, location := GetDistanceFromLocation(column(first, lsrEventEnd, lsrCityEnd))
+	lsr := &report.LocalStormReport{
+		Office:             office,
+		ProductTime:        issued.Unix(),
+		Event:              event,
+		MagnitudeQualifier: qualifier,
+		Source:             column(second, lsrStateEnd, len(second)),
+	}
+	var (
+		county = column(second, lsrEventEnd, lsrCountyEnd)
+		state  = column(second, lsrCountyEnd, lsrStateEnd)
+		text   = strings.Join(remarks, " ")
+	)
+
+	switch rptType {
+	case collector.Hail.String():
+		return &report.HailMsg{
+			Type:      rptType,
+			Time:      t.UTC().Unix(),
+			Size:      int32(math.Round(magnitude * 100)),
+			Distance:  distance,
+			Direction: direction,
+			Location:  location,
+			County:    county,
+			State:     state,
+			Lat:       lat,
+			Lon:       lon,
+			Remarks:   text,
+			Cwa:       office,
+			Lsr:       lsr,
+		}, nil
+	case collector.Wind.String():
+		if unit == "MPH" {
+			magnitude *= knotsPerMPH
+		}
+		var magType string
+		if qualifier == "E" || qualifier == "M" {
+			// LSR wind speeds are gusts
+			magType = qualifier + "G"
+		}
+		return &report.WindMsg{
+			Type:          rptType,
+			Time:          t.UTC().Unix(),
+			Speed:         int32(math.Round(magnitude)),
+			Distance:      distance,
+			Direction:     direction,
+			Location:      location,
+			County:        county,
+			State:         state,
+			Lat:           lat,
+			Lon:           lon,
+			Remarks:       text,
+			Cwa:           office,
+			MagnitudeType: magType,
+			Lsr:           lsr,
+		}, nil
+	case collector.Tornado.String():
+		return &report.TornadoMsg{
+			Type:      rptType,
+			Time:      t.UTC().Unix(),
+			F_Scale:   fScale(column(second, lsrTimeEnd, lsrEventEnd)),
+			Distance:  distance,
+			Direction: direction,
+			Location:  location,
+			County:    county,
+			State:     state,
+			Lat:       lat,
+			Lon:       lon,
+			Remarks:   text,
+			Cwa:       office,
+			Lsr:       lsr,
+		}, nil
+	}
+	return &report.LSRMsg{
+		Type:      LSR,
+		EventType: rptType,
+		Time:      t.UTC().Unix(),
+		Magnitude: magnitude,
+		Unit:      unit,
+		Distance:  distance,
+		Direction: direction,
+		Location:  location,
+		County:    county,
+		State:     state,
+		Lat:       lat,
+		Lon:       lon,
+		Remarks:   text,
+		Cwa:       office,
+		Lsr:       lsr,
+	}, nil
+}
+
+// column returns the trimmed text of the line between the columns from and to,
+// or what there is of it when the line is shorter.
+func column(line string, from, to int) string {
+	if from >= len(line) {
+		return ""
+	}
+	return strings.TrimSpace(line[from:min(to, len(line))])
+}
+
+// lsrLatLon parses a location such as 35.22N 97.48W into signed coordinates.
+func lsrLatLon(s string) (string, string, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return "", "", fmt.Errorf("invalid lat/lon %q", s)
+	}
+	lat, latOK := signedCoordinate(fields[0], 'N', 'S')
+	lon, lonOK := signedCoordinate(fields[1], 'E', 'W')
+	if !latOK || !lonOK {
+		return "", "", fmt.Errorf("invalid lat/lon %q", s)
+	}
+	return lat, lon, nil
+}
+
+func signedCoordinate(s string, positive, negative byte) (string, bool) {
+	if s == "" {
+		return "", false
+	}
+	hemisphere, value := s[len(s)-1], s[:len(s)-1]
+	if _, err := strconv.ParseFloat(value, 64); err != nil {
+		return "", false
+	}
+	switch hemisphere {
+	case positive:
+		return value, true
+	case negative:
+		return "-" + value, true
+	}
+	return "", false
+}
+
+// lsrMagnitudeOf splits a magnitude such as E1.75 INCH into its qualifier, value
+// and unit. Tornado ratings such as EF1, and missing magnitudes, have no value.
+func lsrMagnitudeOf(s string) (string, float64, string, error) {
+	if s == "" || strings.HasPrefix(s, "EF") || strings.HasPrefix(s, "F") {
+		return "", 0, "", nil
+	}
+	match := lsrMagnitude.FindStringSubmatch(s)
+	if match == nil {
+		return "", 0, "", fmt.Errorf("invalid magnitude %q", s)
+	}
+	value, err := strconv.ParseFloat(match[2], 64)
+	if err != nil {
+		return "", 0, "", fmt.Errorf("invalid magnitude %q", s)
+	}
+	return match[1], value, match[3], nil
+}
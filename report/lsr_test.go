@@ -0,0 +1,224 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	report "github.com/stormsync/transformer/proto"
+)
+
+// lsrProduct is an LSR product with hail, wind, flash flood and tornado reports.
+const lsrProduct = `000
+NWUS54 KOUN 200130
+LSROUN
+
+PRELIMINARY LOCAL STORM REPORT
+NATIONAL WEATHER SERVICE NORMAN OK
+830 PM CDT SUN MAY 19 2024
+
+..TIME...   ...EVENT...      ...CITY LOCATION...     ...LAT.LON...
+..DATE...   ....MAG....      ..COUNTY LOCATION..ST.. ...SOURCE....
+            ..REMARKS..
+
+0815 PM     HAIL             2 W NORMAN              35.22N 97.48W
+05/19/2024  E1.75 INCH       CLEVELAND          OK   TRAINED SPOTTER
+
+            GOLF BALL SIZE HAIL COVERING
+            THE GROUND.
+
+
+0820 PM     TSTM WND GST     NORMAN                  35.22N 97.44W
+05/19/2024  M70 MPH          CLEVELAND          OK   ASOS
+
+            ASOS STATION KOUN.
+
+
+0825 PM     FLASH FLOOD      1 NNE MOORE             35.35N 97.48W
+05/19/2024                   CLEVELAND          OK   BROADCAST MEDIA
+
+            WATER OVER ROADS.
+
+
+0705 PM     TORNADO          3 SSW BLANCHARD         35.10N 97.68W
+05/19/2024  EF1              MCCLAIN            OK   NWS STORM SURVEY
+
+            BRIEF TORNADO DAMAGED A BARN.
+
+
+&&
+
+$$
+`
+
+func TestParseLSRProduct(t *testing.T) {
+	p, err := ParseLSRProduct([]byte(lsrProduct))
+	require.NoError(t, err)
+	assert.Equal(t, "OUN", p.Office)
+	assert.Equal(t, "2024-05-19T20:30:00-05:00", p.Issued.Format(time.RFC3339))
+	require.Len(t, p.Entries, 4)
+	assert.Equal(t, 13, p.Entries[0].Line)
+	assert.Equal(t, "0815 PM     HAIL             2 W NORMAN              35.22N 97.48W\n"+
+		"05/19/2024  E1.75 INCH       CLEVELAND          OK   TRAINED SPOTTER\n"+
+		"            GOLF BALL SIZE HAIL COVERING\n"+
+		"            THE GROUND.", string(p.Entries[0].Text))
+
+	reports, err := p.Reports()
+	require.NoError(t, err)
+	require.Len(t, reports, 4)
+
+	lsr := func(event, qualifier, source string) *report.LocalStormReport {
+		return &report.LocalStormReport{
+			Office:             "OUN",
+			ProductTime:        time.Date(2024, 5, 20, 1, 30, 0, 0, time.UTC).Unix(),
+			Event:              event,
+			MagnitudeQualifier: qualifier,
+			Source:             source,
+		}
+	}
+	want := []proto.Message{
+		&report.HailMsg{
+			Type:      "Hail",
+			Time:      time.Date(2024, 5, 20, 1, 15, 0, 0, time.UTC).Unix(),
+			Size:      175,
+			Distance:  2,
+			Direction: "W",
+			Location:  "NORMAN",
+			County:    "CLEVELAND",
+			State:     "OK",
+			Lat:       "35.22",
+			Lon:       "-97.48",
+			Remarks:   "GOLF BALL SIZE HAIL COVERING THE GROUND.",
+			Cwa:       "OUN",
+			Lsr:       lsr("HAIL", "E", "TRAINED SPOTTER"),
+		},
+		&report.WindMsg{
+			Type:          "Wind",
+			Time:          time.Date(2024, 5, 20, 1, 20, 0, 0, time.UTC).Unix(),
+			Speed:         61,
+			Location:      "NORMAN",
+			County:        "CLEVELAND",
+			State:         "OK",
+			Lat:           "35.22",
+			Lon:           "-97.44",
+			Remarks:       "ASOS STATION KOUN.",
+			Cwa:           "OUN",
+			MagnitudeType: "MG",
+			Lsr:           lsr("TSTM WND GST", "M", "ASOS"),
+		},
+		&report.LSRMsg{
+			Type:      "LSR",
+			EventType: "Flood",
+			Time:      time.Date(2024, 5, 20, 1, 25, 0, 0, time.UTC).Unix(),
+			Distance:  1,
+			Direction: "NNE",
+			Location:  "MOORE",
+			County:    "CLEVELAND",
+			State:     "OK",
+			Lat:       "35.35",
+			Lon:       "-97.48",
+			Remarks:   "WATER OVER ROADS.",
+			Cwa:       "OUN",
+			Lsr:       lsr("FLASH FLOOD", "", "BROADCAST MEDIA"),
+		},
+		&report.TornadoMsg{
+			Type:      "Tornado",
+			Time:      time.Date(2024, 5, 20, 0, 5, 0, 0, time.UTC).Unix(),
+			F_Scale:   1,
+			Distance:  3,
+			Direction: "SSW",
+			Location:  "BLANCHARD",
+			County:    "MCCLAIN",
+			State:     "OK",
+			Lat:       "35.10",
+			Lon:       "-97.68",
+			Remarks:   "BRIEF TORNADO DAMAGED A BARN.",
+			Cwa:       "OUN",
+			Lsr:       lsr("TORNADO", "", "NWS STORM SURVEY"),
+		},
+	}
+	for i, r := range reports {
+		assert.True(t, proto.Equal(want[i], r), "got %v", r)
+	}
+}
+
+func TestFromLSREntry_OtherEvents(t *testing.T) {
+	issued := time.Date(2024, 1, 9, 6, 0, 0, 0, time.FixedZone("CST", -6*60*60))
+	r, err := FromLSREntry([]byte("0530 AM     HEAVY SNOW       2 N DES MOINES          41.62N 93.61W\n"+
+		"01/09/2024  M8.5 INCH        POLK               IA   CO-OP OBSERVER\n"+
+		"            24 HOUR TOTAL."), "DMX", issued)
+	require.NoError(t, err)
+	want := &report.LSRMsg{
+		Type:      "LSR",
+		EventType: "Snow",
+		Time:      time.Date(2024, 1, 9, 11, 30, 0, 0, time.UTC).Unix(),
+		Magnitude: 8.5,
+		Unit:      "INCH",
+		Distance:  2,
+		Direction: "N",
+		Location:  "DES MOINES",
+		County:    "POLK",
+		State:     "IA",
+		Lat:       "41.62",
+		Lon:       "-93.61",
+		Remarks:   "24 HOUR TOTAL.",
+		Cwa:       "DMX",
+		Lsr: &report.LocalStormReport{
+			Office:             "DMX",
+			ProductTime:        issued.Unix(),
+			Event:              "HEAVY SNOW",
+			MagnitudeQualifier: "M",
+			Source:             "CO-OP OBSERVER",
+		},
+	}
+	assert.True(t, proto.Equal(want, r), "got %v", r)
+}
+
+func TestParseLSRProduct_Errors(t *testing.T) {
+	_, err := ParseLSRProduct([]byte("LSROUN\nPRELIMINARY LOCAL STORM REPORT\n$$\n"))
+	assert.ErrorContains(t, err, "no issuance time")
+
+	_, err = ParseLSRProduct([]byte("830 PM XYZ SUN MAY 19 2024\n"))
+	assert.EqualError(t, err, `unknown time zone "XYZ"`)
+
+	issued := time.Date(2024, 5, 19, 20, 30, 0, 0, time.FixedZone("CDT", -5*60*60))
+	_, err = FromLSREntry([]byte("0815 PM     HAIL             NORMAN                  35.22N 97.48W\n"+
+		"05/19/2024  BIG              CLEVELAND          OK   PUBLIC"), "OUN", issued)
+	assert.EqualError(t, err, `invalid magnitude "BIG"`)
+
+	_, err = FromLSREntry([]byte("0815 PM     HAIL             NORMAN                  35.22 97.48\n"+
+		"05/19/2024  E1.00 INCH       CLEVELAND          OK   PUBLIC"), "OUN", issued)
+	assert.EqualError(t, err, `invalid lat/lon "35.22 97.48"`)
+
+	_, err = FromLSREntry([]byte("0815 PM     HAIL             NORMAN                  35.22N 97.48W"), "OUN", issued)
+	assert.Error(t, err)
+}
+
+func TestLSREventType(t *testing.T) {
+	tests := []struct {
+		event string
+		want  string
+	}{
+		{event: "HAIL", want: "Hail"},
+		{event: "tstm wnd gst", want: "Wind"},
+		{event: "TSTM WND DMG", want: "Wind"},
+		{event: "TORNADO", want: "Tornado"},
+		{event: "FLASH  FLOOD", want: "Flood"},
+		{event: "HEAVY SNOW", want: "Snow"},
+		{event: "NON-TSTM WND GST", want: "High Wind"},
+		{event: "DUST STORM", want: "DUST STORM"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.event, func(t *testing.T) {
+			assert.Equal(t, tt.want, LSREventType(tt.event))
+		})
+	}
+
+	assert.True(t, IsLSRProductStart("NWUS54 KOUN 200130"))
+	assert.True(t, IsLSRProductStart("LSROUN"))
+	assert.True(t, IsLSRProductStart("PRELIMINARY LOCAL STORM REPORT...SUMMARY"))
+	assert.False(t, IsLSRProductStart("Time,Size,Location,County,State,Lat,Lon,Comments"))
+}
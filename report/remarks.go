@@ -0,0 +1,130 @@
+package report
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	report "github.com/stormsync/transformer/proto"
+)
+
+// Qualifiers of a reported magnitude.
+const (
+	QualifierMeasured  = "measured"
+	QualifierEstimated = "estimated"
+)
+
+// Damage descriptors recorded in RemarksInfo.Damage.
+const (
+	DamageTrees      = "trees"
+	DamagePowerLines = "power-lines"
+	DamageRoofs      = "roofs"
+	DamageVehicles   = "vehicles"
+	DamageStructures = "structures"
+)
+
+var (
+	// officeRe matches the issuing office at the end of a remark, such as "(FWD)".
+	officeRe = regexp.MustCompile(`\(([A-Z]{3})\)\s*$`)
+
+	qualifierRe = regexp.MustCompile(`(?i)\b(MEASURED|MEASURE|MEAS|ESTIMATED|ESTIMATE|EST)\b`)
+
+	// stationRe matches ICAO identifiers. They are only kept when the sentence
+	// mentions a station so words like KNOT are not picked up.
+	stationRe        = regexp.MustCompile(`\b[KP][A-Z][A-Z0-9]{2}\b`)
+	stationContextRe = regexp.MustCompile(`(?i)\b(ASOS|AWOS|AIRPORT|STATION|MESONET|SITE)\b`)
+
+	injuryRe   = regexp.MustCompile(`(?i)\b(NO|\d+|ONE|TWO|THREE|FOUR|FIVE|SIX|SEVEN|EIGHT|NINE|TEN)?\s*(?:MINOR\s+|SERIOUS\s+)?(?:PEOPLE\s+|PERSONS?\s+)?(?:WERE\s+|WAS\s+)?INJUR(?:Y|IES|ED)\b`)
+	fatalityRe = regexp.MustCompile(`(?i)\b(NO|\d+|ONE|TWO|THREE|FOUR|FIVE|SIX|SEVEN|EIGHT|NINE|TEN)?\s*(?:PEOPLE\s+|PERSONS?\s+)?(?:WERE\s+|WAS\s+)?(?:FATALIT(?:Y|IES)|KILLED|DEATHS?|DIED)\b`)
+
+	damageRes = []struct {
+		name string
+		re   *regexp.Regexp
+	}{
+		{name: DamageTrees, re: regexp.MustCompile(`(?i)\b(TREES?|LIMBS?|BRANCH(ES)?)\b`)},
+		{name: DamagePowerLines, re: regexp.MustCompile(`(?i)\b(POWER\s*LINES?|POWER\s*POLES?|UTILITY\s*(LINES?|POLES?)|LINES?\s+DOWN)\b`)},
+		{name: DamageRoofs, re: regexp.MustCompile(`(?i)\b(ROOFS?|SHINGLES?)\b`)},
+		{name: DamageVehicles, re: regexp.MustCompile(`(?i)\b(VEHICLES?|CARS?|TRUCKS?|WINDSHIELDS?|SEMIS?)\b`)},
+		{name: DamageStructures, re: regexp.MustCompile(`(?i)\b(HOMES?|HOUSES?|BARNS?|BUILDINGS?|SHEDS?|OUTBUILDINGS?|CARPORTS?|MOBILE\s+HOMES?)\b`)},
+	}
+
+	numberWords = map[string]int32{
+		"ONE": 1, "TWO": 2, "THREE": 3, "FOUR": 4, "FIVE": 5,
+		"SIX": 6, "SEVEN": 7, "EIGHT": 8, "NINE": 9, "TEN": 10,
+	}
+)
+
+// AnalyzeRemarks pulls the issuing office, magnitude qualifier, damage descriptors,
+// injury and fatality mentions, and station identifiers out of a remarks column.
+// For example "MEASURED GUST AT KDFW ASOS. TREES DOWN ON POWER LINES. (FWD)" yields
+// office FWD, qualifier measured, damage trees and power-lines, and station KDFW.
+func AnalyzeRemarks(remarks string) *report.RemarksInfo {
+	info := &report.RemarksInfo{}
+	if m := officeRe.FindStringSubmatch(remarks); m != nil {
+		info.Office = m[1]
+	}
+
+	if m := qualifierRe.FindString(remarks); m != "" {
+		if strings.HasPrefix(strings.ToUpper(m), "MEAS") {
+			info.Qualifier = QualifierMeasured
+		} else {
+			info.Qualifier = QualifierEstimated
+		}
+	}
+
+	for _, d := range damageRes {
+		if d.re.MatchString(remarks) {
+			info.Damage = append(info.Damage, d.name)
+		}
+	}
+
+	info.Injuries, info.InjuryCount = casualties(injuryRe, remarks)
+	info.Fatalities, info.FatalityCount = casualties(fatalityRe, remarks)
+
+	for _, sentence := range strings.Split(officeRe.ReplaceAllString(remarks, ""), ".") {
+		if !stationContextRe.MatchString(sentence) {
+			continue
+		}
+		for _, id := range stationRe.FindAllString(sentence, -1) {
+			if !slices.Contains(info.Stations, id) {
+				info.Stations = append(info.Stations, id)
+			}
+		}
+	}
+	return info
+}
+
+// casualties reports whether the remarks mention injuries or fatalities and the
+// total count when it is given. A mention preceded by "no" does not count.
+func casualties(re *regexp.Regexp, remarks string) (bool, int32) {
+	var mentioned bool
+	var count int32
+	for _, m := range re.FindAllStringSubmatch(remarks, -1) {
+		qty := strings.ToUpper(m[1])
+		if qty == "NO" {
+			continue
+		}
+		mentioned = true
+		if n, ok := numberWords[qty]; ok {
+			count += n
+		} else if n, err := strconv.Atoi(qty); err == nil {
+			count += int32(n)
+		}
+	}
+	return mentioned, count
+}
+
+// ApplyRemarks analyzes the report's remarks and records the result on the report.
+func ApplyRemarks(r Report) *report.RemarksInfo {
+	info := AnalyzeRemarks(r.GetRemarks())
+	switch m := r.(type) {
+	case *report.HailMsg:
+		m.RemarksInfo = info
+	case *report.WindMsg:
+		m.RemarksInfo = info
+	case *report.TornadoMsg:
+		m.RemarksInfo = info
+	}
+	return info
+}
@@ -0,0 +1,81 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+
+	report "github.com/stormsync/transformer/proto"
+)
+
+func TestAnalyzeRemarks(t *testing.T) {
+	tests := []struct {
+		name    string
+		remarks string
+		want    *report.RemarksInfo
+	}{
+		{
+			name:    "should find office, qualifier, damage and station",
+			remarks: "MEASURED GUST AT KDFW ASOS. TREES DOWN ON POWER LINES. (FWD)",
+			want: &report.RemarksInfo{
+				Office:    "FWD",
+				Qualifier: QualifierMeasured,
+				Damage:    []string{DamageTrees, DamagePowerLines},
+				Stations:  []string{"KDFW"},
+			},
+		},
+		{
+			name:    "should find an estimated qualifier and roof and vehicle damage",
+			remarks: "Estimated 70 mph winds. Roof blown off a barn and a truck overturned. (OUN)",
+			want: &report.RemarksInfo{
+				Office:    "OUN",
+				Qualifier: QualifierEstimated,
+				Damage:    []string{DamageRoofs, DamageVehicles, DamageStructures},
+			},
+		},
+		{
+			name:    "should count injuries and fatalities",
+			remarks: "Mobile home destroyed. Two people injured and 1 killed. (TAE)",
+			want: &report.RemarksInfo{
+				Office:        "TAE",
+				Damage:        []string{DamageStructures},
+				Injuries:      true,
+				InjuryCount:   2,
+				Fatalities:    true,
+				FatalityCount: 1,
+			},
+		},
+		{
+			name:    "should ignore negated casualties",
+			remarks: "Trees down. No injuries reported. (FFC)",
+			want: &report.RemarksInfo{
+				Office: "FFC",
+				Damage: []string{DamageTrees},
+			},
+		},
+		{
+			name:    "should not treat words as stations without station context",
+			remarks: "GUST OF 58 KNOT REPORTED BY SPOTTER. (LUB)",
+			want:    &report.RemarksInfo{Office: "LUB"},
+		},
+		{
+			name:    "should handle empty remarks",
+			remarks: "",
+			want:    &report.RemarksInfo{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AnalyzeRemarks(tt.remarks)
+			assert.True(t, proto.Equal(tt.want, got), "AnalyzeRemarks(%q) = %v", tt.remarks, got)
+		})
+	}
+}
+
+func TestApplyRemarks(t *testing.T) {
+	msg := &report.WindMsg{Remarks: "Trees down on McLeod Road. (TAE)"}
+	ApplyRemarks(msg)
+	assert.Equal(t, "TAE", msg.GetRemarksInfo().GetOffice())
+	assert.Equal(t, []string{DamageTrees}, msg.GetRemarksInfo().GetDamage())
+}
@@ -0,0 +1,66 @@
+package report
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	report "github.com/stormsync/transformer/proto"
+)
+
+// Report is the set of fields shared by the hail, wind, and tornado messages.
+// It allows the stages that run between parsing and producing to work on a
+// report without caring about its concrete type.
+type Report interface {
+	proto.Message
+	GetTime() int64
+	GetDistance() int32
+	GetDirection() string
+	GetLocation() string
+	GetCounty() string
+	GetState() string
+	GetLat() string
+	GetLon() string
+	GetRemarks() string
+	GetType() string
+}
+
+// Magnitude returns the magnitude column of a report: hail size in hundredths of
+// an inch, wind speed in knots, or the F/EF scale of a tornado.
+// Zero is returned when the column was UNK or the report type is unknown.
+func Magnitude(r Report) int32 {
+	switch m := r.(type) {
+	case *report.HailMsg:
+		return m.GetSize()
+	case *report.WindMsg:
+		return m.GetSpeed()
+	case *report.TornadoMsg:
+		return m.GetF_Scale()
+	}
+	return 0
+}
+
+// Kind returns what a report is of, for grouping it with others of the same kind:
+// its type, or the event type of an LSR message, such as Flood, so that floods
+// and snow are not grouped together as LSRs.
+func Kind(r Report) string {
+	if m, ok := r.(*report.LSRMsg); ok {
+		return m.GetEventType()
+	}
+	return r.GetType()
+}
+
+// LatLon parses the lat and lon columns of a report into float64 values.
+func LatLon(r Report) (float64, float64, error) {
+	lat, err := strconv.ParseFloat(strings.TrimSpace(r.GetLat()), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid lat %q: %w", r.GetLat(), err)
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(r.GetLon()), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid lon %q: %w", r.GetLon(), err)
+	}
+	return lat, lon, nil
+}
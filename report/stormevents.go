@@ -0,0 +1,392 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stormsync/collector"
+
+	report "github.com/stormsync/transformer/proto"
+)
+
+// StormEvents is the report type of the NCEI Storm Events details files, such as
+// StormEvents_details-ftp_v1.0_d2023_c20240117.csv. Hail, thunderstorm wind and
+// tornado records are parsed into their report messages with the rest of the
+// record in a StormEvent.
+const StormEvents = "StormEvents"
+
+// ErrUnsupportedEvent is returned for Storm Events records and LSRs of an event
+// type without a report message, such as floods, which are not errors in the file.
+var ErrUnsupportedEvent = errors.New("event type is not hail, thunderstorm wind or tornado")
+
+// Columns of the Storm Events details files.
+const (
+	seBeginYearMonth = iota
+	seBeginDay
+	seBeginTime
+	seEndYearMonth
+	seEndDay
+	seEndTime
+	seEpisodeID
+	seEventID
+	seState
+	seStateFIPS
+	seYear
+	seMonthName
+	seEventType
+	seZoneType
+	seZoneFIPS
+	seZoneName
+	seWFO
+	seBeginDateTime
+	seTimeZone
+	seEndDateTime
+	seInjuriesDirect
+	seInjuriesIndirect
+	seDeathsDirect
+	seDeathsIndirect
+	seDamageProperty
+	seDamageCrops
+	seSource
+	seMagnitude
+	seMagnitudeType
+	seFloodCause
+	seCategory
+	seTorFScale
+	seTorLength
+	seTorWidth
+	seTorOtherWFO
+	seTorOtherState
+	seTorOtherFIPS
+	seTorOtherName
+	seBeginRange
+	seBeginAzimuth
+	seBeginLocation
+	seEndRange
+	seEndAzimuth
+	seEndLocation
+	seBeginLat
+	seBeginLon
+	seEndLat
+	seEndLon
+	seEpisodeNarrative
+	seEventNarrative
+	seDataSource
+	seColumns
+)
+
+// zoneOffsets are the UTC offsets, in hours, of the time zone codes written
+// without one in older Storm Events files.
+var zoneOffsets = map[string]int{
+	"AST":  -4,
+	"EST":  -5,
+	"EDT":  -4,
+	"CST":  -6,
+	"CDT":  -5,
+	"MST":  -7,
+	"MDT":  -6,
+	"PST":  -8,
+	"PDT":  -7,
+	"AKST": -9,
+	"AKDT": -8,
+	"HST":  -10,
+	"SST":  -11,
+	"GST":  10,
+	"CHST": 10,
+	"GMT":  0,
+	"UTC":  0,
+}
+
+// stateCodes maps the state names of Storm Events records to USPS codes.
+var stateCodes = map[string]string{
+	"ALABAMA":              "AL",
+	"ALASKA":               "AK",
+	"AMERICAN SAMOA":       "AS",
+	"ARIZONA":              "AZ",
+	"ARKANSAS":             "AR",
+	"CALIFORNIA":           "CA",
+	"COLORADO":             "CO",
+	"CONNECTICUT":          "CT",
+	"DELAWARE":             "DE",
+	"DISTRICT OF COLUMBIA": "DC",
+	"FLORIDA":              "FL",
+	"GEORGIA":              "GA",
+	"GUAM":                 "GU",
+	"HAWAII":               "HI",
+	"IDAHO":                "ID",
+	"ILLINOIS":             "IL",
+	"INDIANA":              "IN",
+	"IOWA":                 "IA",
+	"KANSAS":               "KS",
+	"KENTUCKY":             "KY",
+	"LOUISIANA":            "LA",
+	"MAINE":                "ME",
+	"MARYLAND":             "MD",
+	"MASSACHUSETTS":        "MA",
+	"MICHIGAN":             "MI",
+	"MINNESOTA":            "MN",
+	"MISSISSIPPI":          "MS",
+	"MISSOURI":             "MO",
+	"MONTANA":              "MT",
+	"NEBRASKA":             "NE",
+	"NEVADA":               "NV",
+	"NEW HAMPSHIRE":        "NH",
+	"NEW JERSEY":           "NJ",
+	"NEW MEXICO":           "NM",
+	"NEW YORK":             "NY",
+	"NORTH CAROLINA":       "NC",
+	"NORTH DAKOTA":         "ND",
+	"OHIO":                 "OH",
+	"OKLAHOMA":             "OK",
+	"OREGON":               "OR",
+	"PENNSYLVANIA":         "PA",
+	"PUERTO RICO":          "PR",
+	"RHODE ISLAND":         "RI",
+	"SOUTH CAROLINA":       "SC",
+	"SOUTH DAKOTA":         "SD",
+	"TENNESSEE":            "TN",
+	"TEXAS":                "TX",
+	"UTAH":                 "UT",
+	"VERMONT":              "VT",
+	"VIRGIN ISLANDS":       "VI",
+	"VIRGINIA":             "VA",
+	"WASHINGTON":           "WA",
+	"WEST VIRGINIA":        "WV",
+	"WISCONSIN":            "WI",
+	"WYOMING":              "WY",
+}
+
+// damageMultipliers are the values of the suffixes of Storm Events damage amounts.
+var damageMultipliers = map[byte]float64{
+	'H': 1e2,
+	'K': 1e3,
+	'M': 1e6,
+	'B': 1e9,
+}
+
+// FromStormEventsLine converts a line of a Storm Events details file into the
+// report message of its event type. ErrUnsupportedEvent is returned for event
+// types other than Hail, Thunderstorm Wind and Tornado.
+func FromStormEventsLine(line []byte) (Report, error) {
+	reader := csv.NewReader(bytes.NewReader(line))
+	reader.LazyQuotes = true
+	fields, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read storm events line: %w", err)
+	}
+	if len(fields) < seColumns {
+		return nil, fmt.Errorf("line did not contain at least %d columns", seColumns)
+	}
+	for i, f := range fields {
+		fields[i] = strings.TrimSpace(f)
+	}
+	r := &seRecord{record: record{fields: fields}}
+
+	eventType := fields[seEventType]
+	switch strings.ToUpper(eventType) {
+	case "HAIL", "THUNDERSTORM WIND", "TORNADO":
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedEvent, eventType)
+	}
+
+	loc, err := r.location()
+	if err != nil {
+		return nil, err
+	}
+	begin := r.time(seBeginYearMonth, seBeginDay, seBeginTime, loc)
+	event := &report.StormEvent{
+		EpisodeID:        int64(r.int(seEpisodeID, "episode id")),
+		EventID:          int64(r.int(seEventID, "event id")),
+		EventType:        eventType,
+		EndTime:          r.time(seEndYearMonth, seEndDay, seEndTime, loc),
+		TimeZone:         fields[seTimeZone],
+		ZoneType:         fields[seZoneType],
+		ZoneFips:         fields[seZoneFIPS],
+		ZoneName:         fields[seZoneName],
+		InjuriesIndirect: r.int(seInjuriesIndirect, "indirect injuries"),
+		DeathsIndirect:   r.int(seDeathsIndirect, "indirect deaths"),
+		Source:           fields[seSource],
+		EndDistance:      int32(math.Round(r.float(seEndRange, "end range"))),
+		EndDirection:     fields[seEndAzimuth],
+		EndLocation:      fields[seEndLocation],
+		EndLat:           fields[seEndLat],
+		EndLon:           fields[seEndLon],
+		EpisodeNarrative: fields[seEpisodeNarrative],
+		DataSource:       fields[seDataSource],
+	}
+	impact := &report.Impact{
+		Injuries:     r.int(seInjuriesDirect, "direct injuries"),
+		Fatalities:   r.int(seDeathsDirect, "direct deaths"),
+		PropertyLoss: r.damage(seDamageProperty, "property damage"),
+		CropLoss:     r.damage(seDamageCrops, "crop damage"),
+	}
+	distance := int32(math.Round(r.float(seBeginRange, "begin range")))
+	magnitude := r.float(seMagnitude, "magnitude")
+	county, countyFIPS := r.county()
+
+	var msg Report
+	switch strings.ToUpper(eventType) {
+	case "HAIL":
+		msg = &report.HailMsg{
+			Type:       collector.Hail.String(),
+			Size:       int32(math.Round(magnitude * 100)),
+			Time:       begin,
+			Distance:   distance,
+			Direction:  fields[seBeginAzimuth],
+			Location:   fields[seBeginLocation],
+			County:     county,
+			State:      stateCodes[strings.ToUpper(fields[seState])],
+			Lat:        fields[seBeginLat],
+			Lon:        fields[seBeginLon],
+			Remarks:    fields[seEventNarrative],
+			CountyFips: countyFIPS,
+			StateFips:  r.stateFIPS(),
+			Cwa:        fields[seWFO],
+			Impact:     impact,
+			StormEvent: event,
+		}
+	case "THUNDERSTORM WIND":
+		msg = &report.WindMsg{
+			Type:          collector.Wind.String(),
+			Speed:         int32(math.Round(magnitude)),
+			Time:          begin,
+			Distance:      distance,
+			Direction:     fields[seBeginAzimuth],
+			Location:      fields[seBeginLocation],
+			County:        county,
+			State:         stateCodes[strings.ToUpper(fields[seState])],
+			Lat:           fields[seBeginLat],
+			Lon:           fields[seBeginLon],
+			Remarks:       fields[seEventNarrative],
+			CountyFips:    countyFIPS,
+			StateFips:     r.stateFIPS(),
+			Cwa:           fields[seWFO],
+			Impact:        impact,
+			MagnitudeType: fields[seMagnitudeType],
+			StormEvent:    event,
+		}
+	case "TORNADO":
+		track := &report.Track{
+			EndLat:      fields[seEndLat],
+			EndLon:      fields[seEndLon],
+			LengthMiles: r.float(seTorLength, "tornado length"),
+			WidthYards:  int32(math.Round(r.float(seTorWidth, "tornado width"))),
+		}
+		if countyFIPS != "" {
+			track.CountyFips = []string{countyFIPS}
+		}
+		msg = &report.TornadoMsg{
+			Type:       collector.Tornado.String(),
+			F_Scale:    fScale(fields[seTorFScale]),
+			Time:       begin,
+			Distance:   distance,
+			Direction:  fields[seBeginAzimuth],
+			Location:   fields[seBeginLocation],
+			County:     county,
+			State:      stateCodes[strings.ToUpper(fields[seState])],
+			Lat:        fields[seBeginLat],
+			Lon:        fields[seBeginLon],
+			Remarks:    fields[seEventNarrative],
+			CountyFips: countyFIPS,
+			StateFips:  r.stateFIPS(),
+			Cwa:        fields[seWFO],
+			Impact:     impact,
+			Track:      track,
+			StormEvent: event,
+		}
+	}
+	if err := r.err(); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// seRecord is a line of a Storm Events details file.
+type seRecord struct {
+	record
+}
+
+// location returns the time zone of the record's local times, from a code such
+// as CST-6, or CST in older files.
+func (r *seRecord) location() (*time.Location, error) {
+	code := strings.ToUpper(r.fields[seTimeZone])
+	name := strings.TrimRight(code, "+-0123456789")
+	hours, ok := zoneOffsets[name]
+	if offset := code[len(name):]; offset != "" {
+		n, err := strconv.Atoi(offset)
+		hours, ok = n, err == nil
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown time zone %q", r.fields[seTimeZone])
+	}
+	return time.FixedZone(code, hours*60*60), nil
+}
+
+// time returns the UTC time of the year and month, day and hhmm columns in loc.
+// The YEARMONTH column is used rather than the two digit year of the DATE_TIME
+// columns, which cannot tell 1955 from 2055.
+func (r *seRecord) time(yearMonth, day, hhmm int, loc *time.Location) int64 {
+	ym, d, hm := r.int(yearMonth, "year and month"), r.int(day, "day"), r.int(hhmm, "time")
+	if ym < 100000 || hm%100 > 59 || hm/100 > 23 {
+		r.errs = append(r.errs, fmt.Errorf("invalid date and time %q %q %q", r.fields[yearMonth], r.fields[day], r.fields[hhmm]))
+		return 0
+	}
+	t := time.Date(int(ym/100), time.Month(ym%100), int(d), int(hm/100), int(hm%100), 0, 0, loc)
+	return t.UTC().Unix()
+}
+
+// damage parses a damage amount such as 10.00K or 1.5M into dollars.
+func (r *seRecord) damage(col int, name string) float64 {
+	amount := strings.ToUpper(r.fields[col])
+	if amount == "" {
+		return 0
+	}
+	multiplier := 1.0
+	if m, ok := damageMultipliers[amount[len(amount)-1]]; ok {
+		amount, multiplier = amount[:len(amount)-1], m
+	}
+	if amount == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("invalid %s %q", name, r.fields[col]))
+	}
+	return f * multiplier
+}
+
+// county returns the county name and five digit FIPS code when the record is
+// for a county rather than a forecast or marine zone.
+func (r *seRecord) county() (string, string) {
+	if !strings.EqualFold(r.fields[seZoneType], "C") {
+		return "", ""
+	}
+	state, county := r.int(seStateFIPS, "state fips"), r.int(seZoneFIPS, "zone fips")
+	if state <= 0 || county <= 0 {
+		return r.fields[seZoneName], ""
+	}
+	return r.fields[seZoneName], fmt.Sprintf("%02d%03d", state, county)
+}
+
+func (r *seRecord) stateFIPS() string {
+	if n := r.int(seStateFIPS, "state fips"); n > 0 {
+		return fmt.Sprintf("%02d", n)
+	}
+	return ""
+}
+
+// fScale returns the number of an F or EF scale rating such as EF2, with zero for
+// an unknown rating such as EFU, as the daily reports have for UNK.
+func fScale(rating string) int32 {
+	n, err := strconv.Atoi(strings.TrimLeft(strings.ToUpper(rating), "EF"))
+	if err != nil {
+		return 0
+	}
+	return int32(n)
+}
@@ -0,0 +1,227 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	report "github.com/stormsync/transformer/proto"
+)
+
+// stormEventsLine returns a Storm Events details line of a thunderstorm wind
+// event, with the columns in set replaced.
+func stormEventsLine(t *testing.T, set map[int]string) []byte {
+	t.Helper()
+	fields := make([]string, seColumns)
+	for col, v := range map[int]string{
+		seBeginYearMonth: "202305", seBeginDay: "17", seBeginTime: "1735",
+		seEndYearMonth: "202305", seEndDay: "17", seEndTime: "1750",
+		seEpisodeID: "180001", seEventID: "1100002",
+		seState: "TEXAS", seStateFIPS: "48", seYear: "2023", seMonthName: "May",
+		seEventType: "Thunderstorm Wind", seZoneType: "C", seZoneFIPS: "221", seZoneName: "HOOD", seWFO: "FWD",
+		seBeginDateTime: "17-MAY-23 17:35:00", seTimeZone: "CST-6", seEndDateTime: "17-MAY-23 17:50:00",
+		seInjuriesDirect: "0", seInjuriesIndirect: "1", seDeathsDirect: "0", seDeathsIndirect: "0",
+		seDamageProperty: "10.00K", seDamageCrops: "0.00K", seSource: "Trained Spotter",
+		seMagnitude: "61", seMagnitudeType: "EG",
+		seBeginRange: "2", seBeginAzimuth: "SW", seBeginLocation: "GRANBURY",
+		seEndRange: "3", seEndAzimuth: "NE", seEndLocation: "TOLAR",
+		seBeginLat: "32.36", seBeginLon: "-97.66", seEndLat: "32.4", seEndLon: "-97.6",
+		seEpisodeNarrative: "Storms developed along a dryline, producing damaging winds.",
+		seEventNarrative:   `Trees were "snapped" near Granbury, one falling on a car.`,
+		seDataSource:       "CSV",
+	} {
+		fields[col] = v
+	}
+	for col, v := range set {
+		fields[col] = v
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	require.NoError(t, w.Write(fields))
+	w.Flush()
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+func TestFromStormEventsLine(t *testing.T) {
+	event := func(eventType string, change func(*report.StormEvent)) *report.StormEvent {
+		e := &report.StormEvent{
+			EpisodeID:        180001,
+			EventID:          1100002,
+			EventType:        eventType,
+			EndTime:          time.Date(2023, 5, 17, 23, 50, 0, 0, time.UTC).Unix(),
+			TimeZone:         "CST-6",
+			ZoneType:         "C",
+			ZoneFips:         "221",
+			ZoneName:         "HOOD",
+			InjuriesIndirect: 1,
+			Source:           "Trained Spotter",
+			EndDistance:      3,
+			EndDirection:     "NE",
+			EndLocation:      "TOLAR",
+			EndLat:           "32.4",
+			EndLon:           "-97.6",
+			EpisodeNarrative: "Storms developed along a dryline, producing damaging winds.",
+			DataSource:       "CSV",
+		}
+		if change != nil {
+			change(e)
+		}
+		return e
+	}
+	begin := time.Date(2023, 5, 17, 23, 35, 0, 0, time.UTC).Unix()
+	remarks := `Trees were "snapped" near Granbury, one falling on a car.`
+
+	tests := []struct {
+		name string
+		set  map[int]string
+		want proto.Message
+	}{
+		{
+			name: "thunderstorm wind with a measured gust type",
+			want: &report.WindMsg{
+				Type:          "Wind",
+				Speed:         61,
+				Time:          begin,
+				Distance:      2,
+				Direction:     "SW",
+				Location:      "GRANBURY",
+				County:        "HOOD",
+				State:         "TX",
+				Lat:           "32.36",
+				Lon:           "-97.66",
+				Remarks:       remarks,
+				CountyFips:    "48221",
+				StateFips:     "48",
+				Cwa:           "FWD",
+				Impact:        &report.Impact{PropertyLoss: 10000},
+				MagnitudeType: "EG",
+				StormEvent:    event("Thunderstorm Wind", nil),
+			},
+		},
+		{
+			name: "hail in inches with damage in millions",
+			set:  map[int]string{seEventType: "Hail", seMagnitude: "1.75", seMagnitudeType: "", seDamageProperty: "1.5M", seDamageCrops: "2B"},
+			want: &report.HailMsg{
+				Type:       "Hail",
+				Size:       175,
+				Time:       begin,
+				Distance:   2,
+				Direction:  "SW",
+				Location:   "GRANBURY",
+				County:     "HOOD",
+				State:      "TX",
+				Lat:        "32.36",
+				Lon:        "-97.66",
+				Remarks:    remarks,
+				CountyFips: "48221",
+				StateFips:  "48",
+				Cwa:        "FWD",
+				Impact:     &report.Impact{PropertyLoss: 1.5e6, CropLoss: 2e9},
+				StormEvent: event("Hail", nil),
+			},
+		},
+		{
+			name: "tornado with its track, in an older file's time zone",
+			set: map[int]string{
+				seEventType: "Tornado", seMagnitude: "", seMagnitudeType: "", seTimeZone: "CDT",
+				seTorFScale: "EF2", seTorLength: "4.2", seTorWidth: "200",
+				seInjuriesDirect: "2", seDeathsDirect: "1", seDeathsIndirect: "1",
+			},
+			want: &report.TornadoMsg{
+				Type:       "Tornado",
+				F_Scale:    2,
+				Time:       time.Date(2023, 5, 17, 22, 35, 0, 0, time.UTC).Unix(),
+				Distance:   2,
+				Direction:  "SW",
+				Location:   "GRANBURY",
+				County:     "HOOD",
+				State:      "TX",
+				Lat:        "32.36",
+				Lon:        "-97.66",
+				Remarks:    remarks,
+				CountyFips: "48221",
+				StateFips:  "48",
+				Cwa:        "FWD",
+				Impact:     &report.Impact{Injuries: 2, Fatalities: 1, PropertyLoss: 10000},
+				Track:      &report.Track{EndLat: "32.4", EndLon: "-97.6", LengthMiles: 4.2, WidthYards: 200, CountyFips: []string{"48221"}},
+				StormEvent: event("Tornado", func(e *report.StormEvent) {
+					e.EndTime = time.Date(2023, 5, 17, 22, 50, 0, 0, time.UTC).Unix()
+					e.TimeZone = "CDT"
+					e.DeathsIndirect = 1
+				}),
+			},
+		},
+		{
+			name: "marine zone in a time zone ahead of UTC",
+			set: map[int]string{
+				seEventType: "Thunderstorm Wind", seState: "GUAM", seStateFIPS: "98", seZoneType: "M", seZoneFIPS: "150",
+				seZoneName: "GUAM COASTAL WATERS", seWFO: "GUM", seTimeZone: "ChST10", seBeginTime: "0905",
+			},
+			want: &report.WindMsg{
+				Type:          "Wind",
+				Speed:         61,
+				Time:          time.Date(2023, 5, 16, 23, 5, 0, 0, time.UTC).Unix(),
+				Distance:      2,
+				Direction:     "SW",
+				Location:      "GRANBURY",
+				State:         "GU",
+				Lat:           "32.36",
+				Lon:           "-97.66",
+				Remarks:       remarks,
+				StateFips:     "98",
+				Cwa:           "GUM",
+				Impact:        &report.Impact{PropertyLoss: 10000},
+				MagnitudeType: "EG",
+				StormEvent: event("Thunderstorm Wind", func(e *report.StormEvent) {
+					e.EndTime = time.Date(2023, 5, 17, 7, 50, 0, 0, time.UTC).Unix()
+					e.TimeZone = "ChST10"
+					e.ZoneType = "M"
+					e.ZoneFips = "150"
+					e.ZoneName = "GUAM COASTAL WATERS"
+				}),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromStormEventsLine(stormEventsLine(t, tt.set))
+			require.NoError(t, err)
+			assert.True(t, proto.Equal(tt.want, got), "got %v", got)
+		})
+	}
+}
+
+func TestFromStormEventsLine_Errors(t *testing.T) {
+	_, err := FromStormEventsLine(stormEventsLine(t, map[int]string{seEventType: "Flash Flood"}))
+	assert.ErrorIs(t, err, ErrUnsupportedEvent)
+
+	_, err = FromStormEventsLine([]byte("202305,17,1735"))
+	assert.EqualError(t, err, "line did not contain at least 51 columns")
+
+	_, err = FromStormEventsLine(stormEventsLine(t, map[int]string{seTimeZone: "XYZ"}))
+	assert.EqualError(t, err, `unknown time zone "XYZ"`)
+
+	_, err = FromStormEventsLine(stormEventsLine(t, map[int]string{seBeginTime: "2575", seDamageProperty: "lots"}))
+	assert.EqualError(t, err, "invalid date and time \"202305\" \"17\" \"2575\"\ninvalid property damage \"lots\"")
+}
+
+func TestStormEventsTypes(t *testing.T) {
+	got, ok := DatabaseTypeFromFilename("/data/StormEvents_details-ftp_v1.0_d2023_c20240117.csv")
+	assert.True(t, ok)
+	assert.Equal(t, StormEvents, got)
+
+	got, ok = DatabaseHeaderType("BEGIN_YEARMONTH,BEGIN_DAY,BEGIN_TIME,END_YEARMONTH,END_DAY,END_TIME,EPISODE_ID,EVENT_ID")
+	assert.True(t, ok)
+	assert.Equal(t, StormEvents, got)
+
+	parse, ok := DatabaseParser(StormEvents)
+	require.True(t, ok)
+	r, err := parse(stormEventsLine(t, nil))
+	require.NoError(t, err)
+	assert.Equal(t, int32(61), Magnitude(r))
+}
@@ -13,7 +13,17 @@ import (
 // FromCSVLineToTornado}Msg is the function that will do the actual work to get
 // a line transformed into a tornado message.
 func FromCSVLineToTornadoMsg(line []byte) (report.TornadoMsg, error) {
-	words := strings.Split(string(line), ",")
+	return tornadoMsgFromCSVLine(line, today)
+}
+
+// FromCSVLineToTornadoMsgOn converts a line of the tornado reports of an SPC convective
+// day, such as a line of 240517_rpts_torn.csv, dating it with ConvectiveDayTime.
+func FromCSVLineToTornadoMsgOn(line []byte, day time.Time) (report.TornadoMsg, error) {
+	return tornadoMsgFromCSVLine(line, onConvectiveDay(day))
+}
+
+func tornadoMsgFromCSVLine(line []byte, toTime func(hhmm string) int64) (report.TornadoMsg, error) {
+	words := strings.SplitN(string(line), ",", 8)
 	if len(words) < 8 {
 		return report.TornadoMsg{}, errors.New("line did not contain at least 8 columns")
 	}
@@ -21,7 +31,7 @@ func FromCSVLineToTornadoMsg(line []byte) (report.TornadoMsg, error) {
 
 	return report.TornadoMsg{
 		Type:      collector.Tornado.String(),
-		Time:      StringToUnixTime(time.Now().UTC().Format(time.DateOnly), words[0]),
+		Time:      toTime(words[0]),
 		F_Scale:   StringToInt32(words[1]),
 		Distance:  distance,
 		Direction: direction,
@@ -32,5 +42,4 @@ func FromCSVLineToTornadoMsg(line []byte) (report.TornadoMsg, error) {
 		Lon:       words[6],
 		Remarks:   words[7],
 	}, nil
-	// xx
 }
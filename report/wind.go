@@ -13,7 +13,17 @@ import (
 // FromCSVLineToWindMsg is the function that will do the actual work to get
 // a line transformed into a wind message.
 func FromCSVLineToWindMsg(line []byte) (report.WindMsg, error) {
-	words := strings.Split(string(line), ",")
+	return windMsgFromCSVLine(line, today)
+}
+
+// FromCSVLineToWindMsgOn converts a line of the wind reports of an SPC convective
+// day, such as a line of 240517_rpts_wind.csv, dating it with ConvectiveDayTime.
+func FromCSVLineToWindMsgOn(line []byte, day time.Time) (report.WindMsg, error) {
+	return windMsgFromCSVLine(line, onConvectiveDay(day))
+}
+
+func windMsgFromCSVLine(line []byte, toTime func(hhmm string) int64) (report.WindMsg, error) {
+	words := strings.SplitN(string(line), ",", 8)
 	if len(words) < 8 {
 		return report.WindMsg{}, errors.New("line did not contain at least 8 columns")
 	}
@@ -21,7 +31,7 @@ func FromCSVLineToWindMsg(line []byte) (report.WindMsg, error) {
 
 	return report.WindMsg{
 		Type:      collector.Wind.String(),
-		Time:      StringToUnixTime(time.Now().UTC().Format(time.DateOnly), words[0]),
+		Time:      toTime(words[0]),
 		Speed:     StringToInt32(words[1]),
 		Distance:  distance,
 		Direction: direction,
@@ -5,18 +5,41 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/stormsync/collector"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
 	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 
+	"github.com/stormsync/transformer/aggregate"
+	"github.com/stormsync/transformer/alert"
 	"github.com/stormsync/transformer/consumer"
+	"github.com/stormsync/transformer/correlate"
+	"github.com/stormsync/transformer/filter"
+	"github.com/stormsync/transformer/geo"
+	"github.com/stormsync/transformer/observability"
+	pb "github.com/stormsync/transformer/proto"
 	"github.com/stormsync/transformer/provider"
+	"github.com/stormsync/transformer/qc"
 	"github.com/stormsync/transformer/report"
 
 	"google.golang.org/protobuf/proto"
 )
 
+// Headers added to produced messages when classification is enabled.
+const (
+	SeverityHeader    = "severity"
+	SignificantHeader = "significant"
+)
+
 type Transformer struct {
 	consumer consumer.Consumer
 	producer provider.Provider
@@ -25,18 +48,144 @@ type Transformer struct {
 	consumerTopic string
 	producerTopic string // transformed-weather-data
 	logger        *slog.Logger
+
+	rules    atomic.Pointer[Rules]
+	geocoder *geo.Geocoder
+	remarks  bool
+	infer    bool
+	lenient  bool
+	skipped  atomic.Int64
+
+	aggregator      *aggregate.Aggregator
+	summaryProducer provider.Provider
+
+	correlator    *correlate.Correlator
+	eventProducer provider.Provider
+
+	alertProducer provider.Provider
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// Rules are the filter and alert rules, quality checks and classification
+// thresholds, which can be replaced while the transformer is running with
+// SetRules. A nil field disables its stage.
+type Rules struct {
+	Filter     *filter.Filter
+	Alerts     *alert.Engine
+	QC         *qc.Checker
+	Thresholds *report.Thresholds
+}
+
+// Option configures optional stages of a Transformer.
+type Option func(*Transformer)
+
+// WithFilter drops any report matched by one of the filter's rules before it is produced.
+func WithFilter(f *filter.Filter) Option {
+	return func(t *Transformer) {
+		rules := *t.rules.Load()
+		rules.Filter = f
+		t.rules.Store(&rules)
+	}
+}
+
+// WithRemarksAnalysis pulls structured details out of the remarks of each report.
+func WithRemarksAnalysis() Option {
+	return func(t *Transformer) {
+		t.remarks = true
+	}
+}
+
+// WithMagnitudeInference estimates the magnitude of reports with an UNK magnitude from their remarks.
+func WithMagnitudeInference() Option {
+	return func(t *Transformer) {
+		t.infer = true
+	}
+}
+
+// WithLenientParsing logs and skips lines that cannot be parsed instead of
+// returning an error from GetMessage.
+func WithLenientParsing() Option {
+	return func(t *Transformer) {
+		t.lenient = true
+	}
+}
+
+// WithGeocoder fills in the county, state, and CWA codes of each report from its coordinates.
+func WithGeocoder(g *geo.Geocoder) Option {
+	return func(t *Transformer) {
+		t.geocoder = g
+	}
+}
+
+// WithQC runs the checker's quality checks on each report and records the checks
+// that failed in the report's qc_flags and qc_issues fields.
+func WithQC(c *qc.Checker) Option {
+	return func(t *Transformer) {
+		rules := *t.rules.Load()
+		rules.QC = c
+		t.rules.Store(&rules)
+	}
+}
+
+// WithClassification records the severity category of each report using the thresholds,
+// both on the message and in the severity and significant headers.
+func WithClassification(th report.Thresholds) Option {
+	return func(t *Transformer) {
+		rules := *t.rules.Load()
+		rules.Thresholds = &th
+		t.rules.Store(&rules)
+	}
+}
+
+// WithAggregator adds each produced report to the aggregator's windows and writes the
+// summaries of closed windows to the summary provider.
+func WithAggregator(a *aggregate.Aggregator, summaries provider.Provider) Option {
+	return func(t *Transformer) {
+		t.aggregator = a
+		t.summaryProducer = summaries
+	}
+}
+
+// WithCorrelator assigns each report to an event of nearby reports and writes the
+// updated event to the event provider, keyed by event ID.
+func WithCorrelator(c *correlate.Correlator, events provider.Provider) Option {
+	return func(t *Transformer) {
+		t.correlator = c
+		t.eventProducer = events
+	}
+}
+
+// WithAlerts evaluates the alert rules against each produced report and writes any
+// alerts raised to the alert provider.
+func WithAlerts(e *alert.Engine, alerts provider.Provider) Option {
+	return func(t *Transformer) {
+		rules := *t.rules.Load()
+		rules.Alerts = e
+		t.rules.Store(&rules)
+		t.alertProducer = alerts
+	}
 }
 
 // NewTransformer will return a pointer to a Transformer that allowes for pulling report
 // messages off the raw topic, converting each line into a marshaled protobuff,
 // and sending that off to the transformed topic.
-func NewTransformer(consumer consumer.Consumer, provider provider.Provider, tracer trace.Tracer, logger *slog.Logger) *Transformer {
-	return &Transformer{
+func NewTransformer(consumer consumer.Consumer, provider provider.Provider, tracer trace.Tracer, logger *slog.Logger, opts ...Option) *Transformer {
+	if tracer == nil {
+		tracer = noop.NewTracerProvider().Tracer("transform")
+	}
+	t := &Transformer{
 		tracer:   tracer,
 		consumer: consumer,
 		producer: provider,
 		logger:   logger,
 	}
+	t.rules.Store(&Rules{})
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
 }
 
 // GetMessage pulls a message off of the topic, transforms it,
@@ -49,32 +198,363 @@ func (t *Transformer) GetMessage(ctx context.Context) error {
 		return fmt.Errorf("failed to get message: %w", err)
 	}
 
-	t.logger.Debug("incoming message", "message value ", string(readResponse.Value))
+	// logs and spans written while handling the message carry its position
+	ctx = observability.ContextWithMessage(ctx, observability.Message{
+		Topic:     readResponse.Topic,
+		Partition: readResponse.Partition,
+		Offset:    readResponse.Offset,
+	})
+	ctx = otel.GetTextMapPropagator().Extract(ctx, headerCarrier(readResponse.Headers))
+	ctx, span := t.tracer.Start(ctx, "transform message", trace.WithSpanKind(trace.SpanKindConsumer), trace.WithAttributes(
+		semconv.MessagingDestinationName(readResponse.Topic),
+		semconv.MessagingKafkaDestinationPartition(readResponse.Partition),
+		semconv.MessagingKafkaMessageOffset(int(readResponse.Offset)),
+	))
+	defer span.End()
+
+	if err := t.transform(ctx, readResponse); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
+
+// transform parses, enriches and writes the report in a message read from the consumer.
+func (t *Transformer) transform(ctx context.Context, readResponse consumer.ReaderResponse) error {
+	t.logger.DebugContext(ctx, "incoming message", "message value ", string(readResponse.Value))
 
-	reportType, err := getReportTypeFromHeader(readResponse.Headers)
+	reportType, parse, err := lineParser(readResponse.Headers)
 	if err != nil {
-		t.logger.Debug("getreporttypefromheader()", "error", err)
+		t.logger.DebugContext(ctx, "getreporttypefromheader()", "error", err)
 	}
-	t.logger.Debug("report type", "type", reportType.String())
+	t.logger.DebugContext(ctx, "report type", "type", reportType)
 
-	msgBytes, err := t.processMessage(reportType, readResponse.Value)
+	msg, err := parse(readResponse.Value)
+	if errors.Is(err, report.ErrUnsupportedEvent) {
+		t.logger.DebugContext(ctx, "ignoring event that is not a report", "error", err)
+		return nil
+	}
 	if err != nil {
+		if t.lenient {
+			t.skipped.Add(1)
+			t.logger.WarnContext(ctx, "skipping line that could not be parsed", "error", err, "report type", reportType, "line", string(readResponse.Value))
+			return nil
+		}
 		return fmt.Errorf("failed to process message: %w", err)
 	}
 
+	if t.remarks {
+		report.ApplyRemarks(msg)
+	}
+	if t.infer {
+		report.ApplyInference(msg)
+	}
+
+	if t.geocoder != nil {
+		res, mismatch, err := t.geocoder.Enrich(msg)
+		if err != nil {
+			t.logger.DebugContext(ctx, "unable to geocode report", "error", err, "line", string(readResponse.Value))
+		} else if mismatch {
+			t.logger.DebugContext(ctx, "report state does not match its coordinates", "state", msg.GetState(), "state fips", res.StateFIPS, "county fips", res.CountyFIPS)
+		}
+	}
+
+	// the rules are loaded once so the whole message sees a single version of them
+	rules := t.rules.Load()
+	if rules.QC != nil {
+		if flags := rules.QC.Apply(msg); flags != 0 {
+			t.logger.DebugContext(ctx, "report failed quality checks", "checks", qc.Names(flags), "report type", reportType, "line", string(readResponse.Value))
+		}
+	}
+
+	if rules.Filter != nil {
+		if rule, drop := rules.Filter.Drop(msg); drop {
+			t.logger.DebugContext(ctx, "report dropped by filter", "rule", rule, "report type", reportType, "line", string(readResponse.Value))
+			return nil
+		}
+	}
+
+	var headers []provider.WriterHeader
+	if rules.Thresholds != nil {
+		sev := report.ApplyClassification(msg, *rules.Thresholds)
+		headers = append(headers,
+			provider.WriterHeader{Key: SeverityHeader, Value: []byte(report.SeverityName(sev))},
+			provider.WriterHeader{Key: SignificantHeader, Value: []byte(strconv.FormatBool(sev == pb.Severity_SEVERITY_SIGNIFICANT))},
+		)
+	}
+
+	var event *pb.EventMsg
+	if t.correlator != nil {
+		event, err = t.correlator.Correlate(msg)
+		if err != nil {
+			t.logger.DebugContext(ctx, "unable to correlate report", "error", err, "line", string(readResponse.Value))
+		}
+	}
+
+	msgBytes, err := proto.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s message: %w", reportType, err)
+	}
+
 	wp := provider.WriterPayload{
-		Body: msgBytes,
-		Type: reportType.String(),
+		Body:    msgBytes,
+		Type:    msg.GetType(),
+		Headers: headers,
 	}
 	if err := t.producer.WriteMessage(ctx, wp); err != nil {
 
-		return fmt.Errorf("failed to write message for type %s: %w", reportType.String(), err)
+		return fmt.Errorf("failed to write message for type %s: %w", reportType, err)
+	}
+	t.logger.DebugContext(ctx, "message written to topic", "provider topic", t.producerTopic, "report type", reportType, "line", string(readResponse.Value))
+
+	if event != nil {
+		if err := t.writeEvent(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	if rules.Alerts != nil {
+		if err := t.writeAlerts(ctx, rules.Alerts.Evaluate(msg)); err != nil {
+			return err
+		}
+	}
+
+	if t.aggregator != nil {
+		closed, late := t.aggregator.Add(msg)
+		if late {
+			t.logger.DebugContext(ctx, "report arrived after its window closed", "report type", reportType, "time", msg.GetTime())
+		}
+		if err := t.writeSummaries(ctx, closed); err != nil {
+			return err
+		}
 	}
-	t.logger.Debug("message written to topic", "topic", t.producerTopic, "report type", reportType.String(), "line", string(readResponse.Value))
 
 	return nil
 }
 
+func (t *Transformer) writeEvent(ctx context.Context, event *pb.EventMsg) error {
+	b, err := proto.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event message: %w", err)
+	}
+	wp := provider.WriterPayload{
+		Key:  []byte(event.GetEventID()),
+		Body: b,
+		Type: correlate.EventType,
+		Headers: []provider.WriterHeader{
+			{Key: "eventType", Value: []byte(event.GetType())},
+		},
+	}
+	if err := t.eventProducer.WriteMessage(ctx, wp); err != nil {
+		return fmt.Errorf("failed to write event %s: %w", event.GetEventID(), err)
+	}
+	t.logger.Debug("event written", "event id", event.GetEventID(), "type", event.GetType(), "count", event.GetCount())
+	return nil
+}
+
+func (t *Transformer) writeAlerts(ctx context.Context, alerts []*pb.Alert) error {
+	for _, a := range alerts {
+		b, err := proto.Marshal(a)
+		if err != nil {
+			return fmt.Errorf("failed to marshal alert message: %w", err)
+		}
+		wp := provider.WriterPayload{
+			Key:  []byte(a.GetRuleID()),
+			Body: b,
+			Type: alert.AlertType,
+			Headers: []provider.WriterHeader{
+				{Key: "ruleID", Value: []byte(a.GetRuleID())},
+			},
+		}
+		if err := t.alertProducer.WriteMessage(ctx, wp); err != nil {
+			return fmt.Errorf("failed to write alert %s: %w", a.GetAlertID(), err)
+		}
+		t.logger.Info("alert raised", "alert id", a.GetAlertID(), "rule", a.GetRuleID(), "reports", len(a.GetReports()))
+	}
+	return nil
+}
+
+// Flush blocks until every message written so far has been delivered by each provider.
+func (t *Transformer) Flush(ctx context.Context) error {
+	var errs []error
+	for _, p := range t.providers() {
+		errs = append(errs, p.Flush(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// Close stops the transformer gracefully. The consumer is closed first so no more
+// messages are read, then the summaries of open windows are written, and finally
+// every provider is flushed and closed so no buffered message is dropped. It is
+// safe to call more than once; later calls return the result of the first.
+func (t *Transformer) Close(ctx context.Context) error {
+	t.closeOnce.Do(func() {
+		var errs []error
+		errs = append(errs, t.consumer.Close(ctx))
+		if err := t.FlushSummaries(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to flush summaries: %w", err))
+		}
+		for _, p := range t.providers() {
+			errs = append(errs, p.Flush(ctx), p.Close(ctx))
+		}
+		t.closeErr = errors.Join(errs...)
+	})
+	return t.closeErr
+}
+
+// providers returns each configured provider once, since the same provider may
+// be used for several outputs.
+func (t *Transformer) providers() []provider.Provider {
+	var providers []provider.Provider
+	for _, p := range []provider.Provider{t.producer, t.summaryProducer, t.eventProducer, t.alertProducer} {
+		if p != nil && !slices.Contains(providers, p) {
+			providers = append(providers, p)
+		}
+	}
+	return providers
+}
+
+// FlushSummaries closes all open aggregation windows and writes their summaries.
+// Close calls it before the providers are closed.
+func (t *Transformer) FlushSummaries(ctx context.Context) error {
+	if t.aggregator == nil {
+		return nil
+	}
+	return t.writeSummaries(ctx, t.aggregator.Flush())
+}
+
+func (t *Transformer) writeSummaries(ctx context.Context, summaries []*pb.SummaryMsg) error {
+	for _, s := range summaries {
+		b, err := proto.Marshal(s)
+		if err != nil {
+			return fmt.Errorf("failed to marshal summary message: %w", err)
+		}
+		wp := provider.WriterPayload{
+			Body: b,
+			Type: aggregate.SummaryType,
+			Headers: []provider.WriterHeader{
+				{Key: "summaryType", Value: []byte(s.GetType())},
+			},
+		}
+		if err := t.summaryProducer.WriteMessage(ctx, wp); err != nil {
+			return fmt.Errorf("failed to write summary for %s %s %s: %w", s.GetState(), s.GetCounty(), s.GetType(), err)
+		}
+		t.logger.Debug("summary written", "state", s.GetState(), "county", s.GetCounty(), "type", s.GetType(), "count", s.GetCount())
+	}
+	return nil
+}
+
+// DroppedCounts returns the number of reports dropped by each filter rule.
+// It returns nil when no filter has been configured. The counts start again
+// from zero when the filter is replaced by SetRules.
+func (t *Transformer) DroppedCounts() map[string]int64 {
+	f := t.rules.Load().Filter
+	if f == nil {
+		return nil
+	}
+	return f.Counts()
+}
+
+// SetRules replaces the rules. Messages already being processed
+// finish with the rules they started with. Alert rules can only be set when the
+// transformer was created with WithAlerts, since there is nowhere to write alerts
+// otherwise, and alert groups being tracked by the old engine are forgotten.
+func (t *Transformer) SetRules(rules Rules) error {
+	if rules.Alerts != nil && t.alertProducer == nil {
+		return errors.New("alert rules cannot be set without an alert provider")
+	}
+	t.rules.Store(&rules)
+	return nil
+}
+
+// CurrentRules returns the rules in use.
+func (t *Transformer) CurrentRules() Rules {
+	return *t.rules.Load()
+}
+
+// SkippedCount returns the number of lines skipped because they could not be
+// parsed, which only happens with lenient parsing.
+func (t *Transformer) SkippedCount() int64 {
+	return t.skipped.Load()
+}
+
+// QCCounts returns the number of reports that failed each quality check.
+// It returns nil when no checker has been configured. The counts start again
+// from zero when the checker is replaced by SetRules.
+func (t *Transformer) QCCounts() map[string]int64 {
+	c := t.rules.Load().QC
+	if c == nil {
+		return nil
+	}
+	return c.Counts()
+}
+
+// ParseReport parses a report line into its message, taking the report type from
+// the reportType header. None of a Transformer's enrichment is applied.
+func ParseReport(headers []consumer.ReaderHeader, line []byte) (report.Report, error) {
+	_, parse, err := lineParser(headers)
+	if err != nil {
+		return nil, err
+	}
+	return parse(line)
+}
+
+// lineParser returns the name of the report type in the headers and the parser of
+// its lines. Lines of the historical database types are parsed by the report
+// package's parsers and LSRs with the office and issuance time of their product,
+// while the daily report lines are dated on the convective day in the report date
+// header when there is one. When the report type cannot be determined the error
+// is returned along with the parser of the zero report type.
+func lineParser(headers []consumer.ReaderHeader) (string, func(line []byte) (report.Report, error), error) {
+	name := headerCarrier(headers).Get("reportType")
+	if parse, ok := report.DatabaseParser(name); ok {
+		return name, parse, nil
+	}
+	if name == report.LSR {
+		return name, func(line []byte) (report.Report, error) {
+			issued, err := time.Parse(time.RFC3339, headerCarrier(headers).Get(consumer.LSRIssuedHeader))
+			if err != nil {
+				return nil, fmt.Errorf("invalid LSR product issuance time: %w", err)
+			}
+			return report.FromLSREntry(line, headerCarrier(headers).Get(consumer.LSROfficeHeader), issued)
+		}, nil
+	}
+	rptType, err := getReportTypeFromHeader(headers)
+	return rptType.String(), func(line []byte) (report.Report, error) {
+		day, err := getReportDateFromHeader(headers)
+		if err != nil {
+			return nil, err
+		}
+		return parseMessage(rptType, day, line)
+	}, err
+}
+
+// headerCarrier reads trace context propagated in the headers of a consumed message.
+type headerCarrier []consumer.ReaderHeader
+
+// Get returns the value of the first header named key.
+func (c headerCarrier) Get(key string) string {
+	for _, h := range c {
+		if strings.EqualFold(h.Key, key) {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Set does nothing, consumed messages are not modified.
+func (c headerCarrier) Set(string, string) {}
+
+// Keys returns the header names.
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, len(c))
+	for i, h := range c {
+		keys[i] = h.Key
+	}
+	return keys
+}
+
 // getReportTypeFromHeader extracts the report type from the message headers
 func getReportTypeFromHeader(hdrs []consumer.ReaderHeader) (collector.ReportType, error) {
 	var rptType collector.ReportType
@@ -98,71 +578,87 @@ func getReportTypeFromHeader(hdrs []consumer.ReaderHeader) (collector.ReportType
 	return rptType, reportERR
 }
 
-// processMessage performs the logic to get a generic line from an input message and turn it
-// into the appropriate marshaled protob type that gets passed back as []byte.
-func (t *Transformer) processMessage(rptType collector.ReportType, line []byte) ([]byte, error) {
-	var msg []byte
+// getReportDateFromHeader extracts the convective day of the report from the message
+// headers. The zero time is returned when there is no report date header.
+func getReportDateFromHeader(hdrs []consumer.ReaderHeader) (time.Time, error) {
+	for _, v := range hdrs {
+		if strings.EqualFold(v.Key, consumer.ReportDateHeader) {
+			day, err := time.Parse(time.DateOnly, string(v.Value))
+			if err != nil {
+				return time.Time{}, fmt.Errorf("invalid report date %q: %w", string(v.Value), err)
+			}
+			return day, nil
+		}
+	}
+	return time.Time{}, nil
+}
+
+// parseMessage performs the logic to get a generic line from an input message and turn it
+// into the appropriate report message for the report type. Lines are dated on the
+// convective day, or on the current day when day is zero.
+func parseMessage(rptType collector.ReportType, day time.Time, line []byte) (report.Report, error) {
+	var msg report.Report
 	var err error
 	switch rptType {
 	case collector.Hail:
-		msg, err = processHailMessage(line)
+		msg, err = parseHailMessage(line, day)
 	case collector.Wind:
-		msg, err = processWindMessage(line)
+		msg, err = parseWindMessage(line, day)
 	case collector.Tornado:
-		msg, err = processTornadoMessage(line)
+		msg, err = parseTornadoMessage(line, day)
 	default:
 		err = fmt.Errorf("unknown report type %q", rptType.String())
 	}
 	return msg, err
 }
 
-func processHailMessage(line []byte) ([]byte, error) {
+func parseHailMessage(line []byte, day time.Time) (*pb.HailMsg, error) {
 	if line == nil {
 		return nil, errors.New("line cannot be nil")
 	}
-	hailMsg, err := report.FromCSVLineToHailMsg(line)
-	if err != nil {
-		return nil, fmt.Errorf("unable to convert line to hail report %q: %w", string(line), err)
+	var hailMsg pb.HailMsg
+	var err error
+	if day.IsZero() {
+		hailMsg, err = report.FromCSVLineToHailMsg(line)
+	} else {
+		hailMsg, err = report.FromCSVLineToHailMsgOn(line, day)
 	}
-
-	// implement any business logic before this line
-	mBytes, err := proto.Marshal(&hailMsg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to process hail message: %w", err)
+		return nil, fmt.Errorf("unable to convert line to hail report %q: %w", string(line), err)
 	}
-	return mBytes, nil
+	return &hailMsg, nil
 }
 
-func processWindMessage(line []byte) ([]byte, error) {
+func parseWindMessage(line []byte, day time.Time) (*pb.WindMsg, error) {
 	if line == nil {
 		return nil, errors.New("line cannot be nil")
 	}
-	windMsg, err := report.FromCSVLineToWindMsg(line)
-	if err != nil {
-		return nil, fmt.Errorf("unable to convert line to wind report %q: %w", string(line), err)
+	var windMsg pb.WindMsg
+	var err error
+	if day.IsZero() {
+		windMsg, err = report.FromCSVLineToWindMsg(line)
+	} else {
+		windMsg, err = report.FromCSVLineToWindMsgOn(line, day)
 	}
-
-	// implement any business logic before this line
-	mBytes, err := proto.Marshal(&windMsg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to process wind message: %w", err)
+		return nil, fmt.Errorf("unable to convert line to wind report %q: %w", string(line), err)
 	}
-	return mBytes, nil
+	return &windMsg, nil
 }
 
-func processTornadoMessage(line []byte) ([]byte, error) {
+func parseTornadoMessage(line []byte, day time.Time) (*pb.TornadoMsg, error) {
 	if line == nil {
 		return nil, errors.New("line cannot be nil")
 	}
-	tornadoMsg, err := report.FromCSVLineToTornadoMsg(line)
-	if err != nil {
-		return nil, fmt.Errorf("unable to convert line to tornado report %q: %w", string(line), err)
+	var tornadoMsg pb.TornadoMsg
+	var err error
+	if day.IsZero() {
+		tornadoMsg, err = report.FromCSVLineToTornadoMsg(line)
+	} else {
+		tornadoMsg, err = report.FromCSVLineToTornadoMsgOn(line, day)
 	}
-
-	// implement any business logic before this line
-	mBytes, err := proto.Marshal(&tornadoMsg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to process Tornado Message: %w", err)
+		return nil, fmt.Errorf("unable to convert line to tornado report %q: %w", string(line), err)
 	}
-	return mBytes, nil
+	return &tornadoMsg, nil
 }
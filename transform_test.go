@@ -1,6 +1,7 @@
 package transformer
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -13,13 +14,28 @@ import (
 	slogenv "github.com/cbrewster/slog-env"
 	"github.com/stormsync/collector"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/stormsync/transformer/aggregate"
+	"github.com/stormsync/transformer/alert"
+	"github.com/stormsync/transformer/consumer"
+	"github.com/stormsync/transformer/correlate"
+	"github.com/stormsync/transformer/filter"
+	"github.com/stormsync/transformer/memqueue"
+	"github.com/stormsync/transformer/observability"
 	report "github.com/stormsync/transformer/proto"
+	"github.com/stormsync/transformer/provider"
+	"github.com/stormsync/transformer/qc"
 	report2 "github.com/stormsync/transformer/report"
 )
 
-func Test_processHailMessage(t *testing.T) {
+func Test_parseHailMessage(t *testing.T) {
 	type args struct {
 		line []byte
 	}
@@ -35,7 +51,7 @@ func Test_processHailMessage(t *testing.T) {
 				line: []byte("2132,450,9 SE Granbury,Hood,TX,32.36,-97.66,DELAYED REPORT emergency management reported 4.5 inch hail in Pecan Plantation. (FWD)"),
 			},
 			want: mustMarshal(&report.HailMsg{
-				Time:      report2.StringToUnixTime(time.Now().UTC().Format(time.DateOnly), "2132"),
+				Time:      report2.ConvectiveDayTime(report2.CurrentConvectiveDay(time.Now()), "2132"),
 				Size:      int32(450),
 				Distance:  9,
 				Direction: "SE",
@@ -59,10 +75,13 @@ func Test_processHailMessage(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := processHailMessage(tt.args.line)
+			msg, err := parseHailMessage(tt.args.line, time.Time{})
 
+			var got []byte
 			if err != nil {
 				err = errors.Unwrap(err)
+			} else {
+				got = mustMarshal(msg)
 			}
 			assert.Equal(t, tt.want, got)
 			assert.Equal(t, tt.wantErr, err)
@@ -78,7 +97,7 @@ func mustMarshal(m proto.Message) []byte {
 	return b
 }
 
-func Test_processWindMessage(t *testing.T) {
+func Test_parseWindMessage(t *testing.T) {
 	type args struct {
 		line []byte
 	}
@@ -92,7 +111,7 @@ func Test_processWindMessage(t *testing.T) {
 			name: "should parse a valid wind message line correctly",
 			args: args{line: []byte("1835,UNK,2 N Holt,Irwin,GA,31.63,-83.15,Trees down on McLeod Road. (TAE)")},
 			want: mustMarshal(&report.WindMsg{
-				Time:      report2.StringToUnixTime(time.Now().UTC().Format(time.DateOnly), "1835"),
+				Time:      report2.ConvectiveDayTime(report2.CurrentConvectiveDay(time.Now()), "1835"),
 				Speed:     int32(0),
 				Distance:  2,
 				Direction: "N",
@@ -115,17 +134,20 @@ func Test_processWindMessage(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := processWindMessage(tt.args.line)
+			msg, err := parseWindMessage(tt.args.line, time.Time{})
+			var got []byte
 			if err != nil {
 				err = errors.Unwrap(err)
+			} else {
+				got = mustMarshal(msg)
 			}
-			assert.Equalf(t, tt.want, got, "processWindMessage(%v)", tt.args.line)
+			assert.Equalf(t, tt.want, got, "parseWindMessage(%v)", tt.args.line)
 			assert.Equal(t, tt.wantErr, err)
 		})
 	}
 }
 
-func Test_processTornadoMessage(t *testing.T) {
+func Test_parseTornadoMessage(t *testing.T) {
 	type args struct {
 		line []byte
 	}
@@ -140,7 +162,7 @@ func Test_processTornadoMessage(t *testing.T) {
 			args: args{line: []byte("1131,UNK,2 SSW Lamont,Jefferson,FL,30.35,-83.83,A tornado touched down in far eastern Jefferson county and moved through most of southern Madison county. EF0 tree damage was confirmed in Jefferson county with EF1 dam (TAE)")},
 			want: mustMarshal(&report.TornadoMsg{
 				Type:      "Tornado",
-				Time:      report2.StringToUnixTime(time.Now().UTC().Format(time.DateOnly), "1131"),
+				Time:      report2.ConvectiveDayTime(report2.CurrentConvectiveDay(time.Now()), "1131"),
 				F_Scale:   int32(0),
 				Distance:  2,
 				Direction: "SSW",
@@ -162,17 +184,20 @@ func Test_processTornadoMessage(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := processTornadoMessage(tt.args.line)
+			msg, err := parseTornadoMessage(tt.args.line, time.Time{})
+			var got []byte
 			if err != nil {
 				err = errors.Unwrap(err)
+			} else {
+				got = mustMarshal(msg)
 			}
-			assert.Equalf(t, tt.want, got, "processTornadoMessage(%v)", tt.args.line)
+			assert.Equalf(t, tt.want, got, "parseTornadoMessage(%v)", tt.args.line)
 			assert.Equal(t, tt.wantErr, err)
 		})
 	}
 }
 
-func TestTransformer_processMessage(t *testing.T) {
+func Test_parseMessage(t *testing.T) {
 	type args struct {
 		rptType collector.ReportType
 		line    []byte
@@ -191,7 +216,7 @@ func TestTransformer_processMessage(t *testing.T) {
 			},
 			want: mustMarshal(&report.HailMsg{
 				Type:      collector.Hail.String(),
-				Time:      report2.StringToUnixTime(time.Now().UTC().Format(time.DateOnly), "1830"),
+				Time:      report2.ConvectiveDayTime(report2.CurrentConvectiveDay(time.Now()), "1830"),
 				Size:      int32(100),
 				Distance:  2,
 				Direction: "W",
@@ -212,7 +237,7 @@ func TestTransformer_processMessage(t *testing.T) {
 			},
 			want: mustMarshal(&report.WindMsg{
 				Type:      collector.Wind.String(),
-				Time:      report2.StringToUnixTime(time.Now().UTC().Format(time.DateOnly), "1835"),
+				Time:      report2.ConvectiveDayTime(report2.CurrentConvectiveDay(time.Now()), "1835"),
 				Speed:     int32(0),
 				Distance:  2,
 				Direction: "N",
@@ -233,7 +258,7 @@ func TestTransformer_processMessage(t *testing.T) {
 			},
 			want: mustMarshal(&report.TornadoMsg{
 				Type:      collector.Tornado.String(),
-				Time:      report2.StringToUnixTime(time.Now().UTC().Format(time.DateOnly), "1131"),
+				Time:      report2.ConvectiveDayTime(report2.CurrentConvectiveDay(time.Now()), "1131"),
 				F_Scale:   int32(0),
 				Distance:  2,
 				Direction: "SSW",
@@ -249,39 +274,58 @@ func TestTransformer_processMessage(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tr := new(Transformer)
-
-			got, err := tr.processMessage(tt.args.rptType, tt.args.line)
-			assert.Equal(t, string(tt.want), string(got))
+			msg, err := parseMessage(tt.args.rptType, time.Time{}, tt.args.line)
+			assert.Equal(t, string(tt.want), string(mustMarshal(msg)))
 			assert.Equal(t, tt.wantErr, err)
 		})
 	}
 }
 
 type mockConsumer struct {
-	expectedData  ReaderResponse
+	expectedData  consumer.ReaderResponse
 	expectedError error
+	closes        int
 }
 
-func (mc *mockConsumer) ReadMessage(ctx context.Context) (ReaderResponse, error) {
+func (mc *mockConsumer) ReadMessage(ctx context.Context) (consumer.ReaderResponse, error) {
 	return mc.expectedData, mc.expectedError
 }
 
+func (mc *mockConsumer) Close(ctx context.Context) error {
+	mc.closes++
+	return nil
+}
+
 type mockProducer struct {
 	expectedError error
+	written       []provider.WriterPayload
+	flushes       int
+	closes        int
 }
 
-func (mp *mockProducer) WriteMessage(ctx context.Context, wp WriterPayload) error {
+func (mp *mockProducer) WriteMessage(ctx context.Context, wp provider.WriterPayload) error {
+	mp.written = append(mp.written, wp)
 	return mp.expectedError
 }
 
+func (mp *mockProducer) Flush(ctx context.Context) error {
+	mp.flushes++
+	return nil
+}
+
+func (mp *mockProducer) Close(ctx context.Context) error {
+	mp.closes++
+	return nil
+}
+
 func TestTransformer_GetMessage(t1 *testing.T) {
 	type fields struct {
-		consumer      Consumer
-		producer      Provider
+		consumer      consumer.Consumer
+		producer      provider.Provider
 		consumerTopic string
 		producerTopic string
 		logger        *slog.Logger
+		filter        *filter.Filter
 	}
 	type args struct {
 		ctx context.Context
@@ -296,11 +340,11 @@ func TestTransformer_GetMessage(t1 *testing.T) {
 			name: "should properly get and process messages",
 			fields: fields{
 				consumer: &mockConsumer{
-					expectedData: ReaderResponse{
+					expectedData: consumer.ReaderResponse{
 						Topic: "raw-weather-report",
 						Key:   nil,
 						Value: []byte("1835,UNK,2 N Holt,Irwin,GA,31.63,-83.15,Trees down on McLeod Road. (TAE)"),
-						Headers: []ReaderHeader{{
+						Headers: []consumer.ReaderHeader{{
 							Key:   "reportType",
 							Value: []byte(collector.Tornado.String()),
 						}},
@@ -319,11 +363,11 @@ func TestTransformer_GetMessage(t1 *testing.T) {
 			name: "should properly get and process messages",
 			fields: fields{
 				consumer: &mockConsumer{
-					expectedData: ReaderResponse{
+					expectedData: consumer.ReaderResponse{
 						Topic: "raw-weather-report",
 						Key:   nil,
 						Value: []byte("1835,UNK,2 N Holt,Irwin,GA,31.63,-83.15,Trees down on McLeod Road. (TAE)"),
-						Headers: []ReaderHeader{{
+						Headers: []consumer.ReaderHeader{{
 							Key:   "reportType",
 							Value: []byte(collector.Tornado.String()),
 						}},
@@ -338,16 +382,39 @@ func TestTransformer_GetMessage(t1 *testing.T) {
 			args:    args{ctx: context.Background()},
 			wantErr: fmt.Errorf("failed to write message for type tornado: %w", errors.New("some producer error")),
 		},
+		{
+			name: "should drop a report matched by a filter rule without producing it",
+			fields: fields{
+				consumer: &mockConsumer{
+					expectedData: consumer.ReaderResponse{
+						Topic: "raw-weather-report",
+						Value: []byte("1835,UNK,2 N Holt,Irwin,GA,31.63,-83.15,Trees down on McLeod Road. (TAE)"),
+						Headers: []consumer.ReaderHeader{{
+							Key:   "reportType",
+							Value: []byte(collector.Wind.String()),
+						}},
+					},
+				},
+				producer:      &mockProducer{expectedError: errors.New("should not be called")},
+				consumerTopic: "raw-weather-report",
+				logger:        slog.New(slogenv.NewHandler(slog.NewTextHandler(os.Stderr, nil))),
+				filter:        mustFilter([]filter.Rule{{Name: "southern-plains", Field: filter.FieldState, Op: filter.OpNotIn, Values: []string{"TX", "OK", "KS"}}}),
+			},
+			args:    args{ctx: context.Background()},
+			wantErr: nil,
+		},
 	}
 	for _, tt := range tests {
 		t1.Run(tt.name, func(t1 *testing.T) {
 			t := &Transformer{
+				tracer:        noop.NewTracerProvider().Tracer("test"),
 				consumer:      tt.fields.consumer,
 				consumerTopic: tt.fields.consumerTopic,
 				producerTopic: tt.fields.producerTopic,
 				producer:      tt.fields.producer,
 				logger:        tt.fields.logger,
 			}
+			t.rules.Store(&Rules{Filter: tt.fields.filter})
 
 			err := t.GetMessage(tt.args.ctx)
 			if err != nil {
@@ -358,9 +425,201 @@ func TestTransformer_GetMessage(t1 *testing.T) {
 	}
 }
 
+func TestTransformer_GetMessageClassification(t *testing.T) {
+	producer := &mockProducer{}
+	tr := NewTransformer(&mockConsumer{
+		expectedData: consumer.ReaderResponse{
+			Value:   []byte("2132,450,9 SE Granbury,Hood,TX,32.36,-97.66,DELAYED REPORT emergency management reported 4.5 inch hail in Pecan Plantation. (FWD)"),
+			Headers: []consumer.ReaderHeader{{Key: "reportType", Value: []byte(collector.Hail.String())}},
+		},
+	}, producer, nil, slog.New(slogenv.NewHandler(slog.NewTextHandler(os.Stderr, nil))), WithClassification(report2.DefaultThresholds()))
+
+	assert.NoError(t, tr.GetMessage(context.Background()))
+	assert.Len(t, producer.written, 1)
+	assert.Equal(t, []provider.WriterHeader{
+		{Key: SeverityHeader, Value: []byte("significant")},
+		{Key: SignificantHeader, Value: []byte("true")},
+	}, producer.written[0].Headers)
+
+	var msg report.HailMsg
+	assert.NoError(t, proto.Unmarshal(producer.written[0].Body, &msg))
+	assert.Equal(t, report.Severity_SEVERITY_SIGNIFICANT, msg.GetSeverity())
+	assert.True(t, msg.GetSignificant())
+}
+
+func TestTransformer_GetMessageLenientParsing(t *testing.T) {
+	logger := slog.New(slogenv.NewHandler(slog.NewTextHandler(os.Stderr, nil)))
+	cons := &mockConsumer{
+		expectedData: consumer.ReaderResponse{
+			Value:   []byte("not,a,report"),
+			Headers: []consumer.ReaderHeader{{Key: "reportType", Value: []byte(collector.Hail.String())}},
+		},
+	}
+
+	strict := NewTransformer(cons, &mockProducer{}, nil, logger)
+	assert.Error(t, strict.GetMessage(context.Background()))
+
+	producer := &mockProducer{}
+	lenient := NewTransformer(cons, producer, nil, logger, WithLenientParsing())
+	assert.NoError(t, lenient.GetMessage(context.Background()))
+	assert.Empty(t, producer.written)
+	assert.Equal(t, int64(1), lenient.SkippedCount())
+}
+
+func TestTransformer_GetMessageTraceContext(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator()) })
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+
+	var logs bytes.Buffer
+	handler, err := observability.NewLogHandler(&logs, observability.LogFormatJSON, &slog.HandlerOptions{Level: slog.LevelDebug})
+	assert.NoError(t, err)
+
+	cons := &mockConsumer{
+		expectedData: consumer.ReaderResponse{
+			Topic:     "raw-weather-report",
+			Partition: 1,
+			Offset:    42,
+			Value:     []byte("not,a,report"),
+			Headers: []consumer.ReaderHeader{
+				{Key: "reportType", Value: []byte(collector.Hail.String())},
+				{Key: "traceparent", Value: []byte("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")},
+			},
+		},
+	}
+	tr := NewTransformer(cons, &mockProducer{}, tp.Tracer("test"), slog.New(handler), WithLenientParsing())
+	assert.NoError(t, tr.GetMessage(context.Background()))
+
+	ended := spans.Ended()
+	if assert.Len(t, ended, 1) {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", ended[0].SpanContext().TraceID().String(), "the trace is continued from the message headers")
+		assert.Equal(t, "00f067aa0ba902b7", ended[0].Parent().SpanID().String())
+	}
+	assert.Contains(t, logs.String(), `"msg":"skipping line that could not be parsed"`)
+	assert.Contains(t, logs.String(), `"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"`)
+	assert.Contains(t, logs.String(), `"topic":"raw-weather-report","partition":1,"offset":42`)
+}
+
+func TestTransformer_EndToEnd(t *testing.T) {
+	logger := slog.New(slogenv.NewHandler(slog.NewTextHandler(os.Stderr, nil)))
+	broker := memqueue.NewBroker()
+	lines := []struct {
+		rptType collector.ReportType
+		line    string
+	}{
+		{collector.Hail, "2132,450,9 SE Granbury,Hood,TX,32.36,-97.66,4.5 inch hail in Pecan Plantation. (FWD)"},
+		{collector.Wind, "1835,UNK,Tallahassee,Leon,FL,30.44,-84.28,Large tree limbs down. (TAE)"},
+		{collector.Hail, "1900,75,2 N Norman,Cleveland,OK,35.25,-97.44,Penny size hail. (OUN)"},
+	}
+	for _, l := range lines {
+		broker.Publish("raw", nil, []byte(l.line), []consumer.ReaderHeader{{Key: "reportType", Value: []byte(l.rptType.String())}})
+	}
+
+	f := mustFilter([]filter.Rule{{Name: "sub-severe-hail", Types: []string{"Hail"}, Field: filter.FieldMagnitude, Op: filter.OpLt, Value: "100"}})
+	tr := NewTransformer(memqueue.NewConsumer(broker, "raw", "transform"), memqueue.NewProvider(broker, "transformed", logger), nil, logger,
+		WithFilter(f), WithClassification(report2.DefaultThresholds()), WithMagnitudeInference())
+	for range lines {
+		assert.NoError(t, tr.GetMessage(context.Background()))
+	}
+
+	assert.Equal(t, int64(0), broker.Lag("transform", "raw"))
+	assert.Equal(t, int64(3), broker.Committed("transform", "raw", 0))
+
+	out := broker.Messages("transformed")
+	assert.Len(t, out, 2)
+	var hail report.HailMsg
+	assert.NoError(t, proto.Unmarshal(out[0].Value, &hail))
+	assert.Equal(t, int32(450), hail.GetSize())
+	assert.Equal(t, report.Severity_SEVERITY_SIGNIFICANT, hail.GetSeverity())
+
+	var wind report.WindMsg
+	assert.NoError(t, proto.Unmarshal(out[1].Value, &wind))
+	assert.Equal(t, int32(50), wind.GetInferredMagnitude().GetValue())
+	assert.Equal(t, []byte(collector.Wind.String()), out[1].Headers[0].Value)
+	assert.Equal(t, map[string]int64{"sub-severe-hail": 1}, tr.DroppedCounts())
+}
+
+func TestTransformer_SetRules(t *testing.T) {
+	logger := slog.New(slogenv.NewHandler(slog.NewTextHandler(os.Stderr, nil)))
+	cons := &mockConsumer{
+		expectedData: consumer.ReaderResponse{
+			Value:   []byte("1900,75,2 N Norman,Cleveland,OK,35.25,-97.44,Penny size hail. (OUN)"),
+			Headers: []consumer.ReaderHeader{{Key: "reportType", Value: []byte(collector.Hail.String())}},
+		},
+	}
+	producer := &mockProducer{}
+	tr := NewTransformer(cons, producer, nil, logger)
+
+	assert.NoError(t, tr.GetMessage(context.Background()))
+	assert.Len(t, producer.written, 1)
+
+	f := mustFilter([]filter.Rule{{Name: "sub-severe-hail", Types: []string{"Hail"}, Field: filter.FieldMagnitude, Op: filter.OpLt, Value: "100"}})
+	assert.NoError(t, tr.SetRules(Rules{Filter: f}))
+	assert.Same(t, f, tr.CurrentRules().Filter)
+	assert.NoError(t, tr.GetMessage(context.Background()))
+	assert.Len(t, producer.written, 1, "the new filter drops the report")
+	assert.Equal(t, map[string]int64{"sub-severe-hail": 1}, tr.DroppedCounts())
+
+	engine, err := alert.NewEngine(nil)
+	assert.NoError(t, err)
+	assert.Error(t, tr.SetRules(Rules{Alerts: engine}), "alerts need an alert provider")
+	assert.Same(t, f, tr.CurrentRules().Filter, "a rejected update keeps the current rules")
+
+	// lowering the severe hail threshold classifies the penny size hail as severe
+	th := report2.DefaultThresholds()
+	th.SevereHail = 75
+	checker, err := qc.NewChecker(qc.DefaultConfig())
+	assert.NoError(t, err)
+	assert.NoError(t, tr.SetRules(Rules{QC: checker, Thresholds: &th}))
+	assert.Nil(t, tr.DroppedCounts(), "the filter was removed")
+	assert.NoError(t, tr.GetMessage(context.Background()))
+	assert.Len(t, producer.written, 2)
+	assert.Contains(t, producer.written[1].Headers, provider.WriterHeader{Key: SeverityHeader, Value: []byte("severe")})
+	assert.Contains(t, tr.QCCounts(), "coordinates", "the new checker runs")
+}
+
+func TestTransformer_Close(t *testing.T) {
+	logger := slog.New(slogenv.NewHandler(slog.NewTextHandler(os.Stderr, nil)))
+	cons := &mockConsumer{
+		expectedData: consumer.ReaderResponse{
+			Value:   []byte("2132,450,9 SE Granbury,Hood,TX,32.36,-97.66,4.5 inch hail. (FWD)"),
+			Headers: []consumer.ReaderHeader{{Key: "reportType", Value: []byte(collector.Hail.String())}},
+		},
+	}
+	reports, shared := &mockProducer{}, &mockProducer{}
+	aggregator, err := aggregate.NewAggregator(time.Hour, 0)
+	assert.NoError(t, err)
+	correlator, err := correlate.NewCorrelator(10, time.Hour)
+	assert.NoError(t, err)
+
+	tr := NewTransformer(cons, reports, nil, logger, WithAggregator(aggregator, shared), WithCorrelator(correlator, shared))
+	assert.NoError(t, tr.GetMessage(context.Background()))
+	assert.NoError(t, tr.Close(context.Background()))
+	assert.NoError(t, tr.Close(context.Background()))
+
+	assert.Equal(t, 1, cons.closes)
+	assert.Equal(t, []int{1, 1}, []int{reports.flushes, reports.closes})
+	assert.Equal(t, []int{1, 1}, []int{shared.flushes, shared.closes}, "a shared provider should be closed once")
+
+	var types []string
+	for _, wp := range shared.written {
+		types = append(types, wp.Type)
+	}
+	assert.Equal(t, []string{correlate.EventType, aggregate.SummaryType}, types, "open windows should be flushed on close")
+}
+
+func mustFilter(rules []filter.Rule) *filter.Filter {
+	f, err := filter.NewFilter(rules)
+	if err != nil {
+		log.Fatal("failed to setup filter for test: ", err)
+	}
+	return f
+}
+
 func Test_getReportTypeFromHeader(t *testing.T) {
 	type args struct {
-		hdrs []ReaderHeader
+		hdrs []consumer.ReaderHeader
 	}
 	tests := []struct {
 		name    string
@@ -370,25 +629,25 @@ func Test_getReportTypeFromHeader(t *testing.T) {
 	}{
 		{
 			name:    "should return hail report type for hail header",
-			args:    args{hdrs: []ReaderHeader{{"reportType", []byte(collector.Hail.String())}}},
+			args:    args{hdrs: []consumer.ReaderHeader{{Key: "reportType", Value: []byte(collector.Hail.String())}}},
 			want:    collector.Hail,
 			wantErr: nil,
 		},
 		{
 			name:    "should return tornado report type for tornado header",
-			args:    args{hdrs: []ReaderHeader{{"reportType", []byte(collector.Tornado.String())}}},
+			args:    args{hdrs: []consumer.ReaderHeader{{Key: "reportType", Value: []byte(collector.Tornado.String())}}},
 			want:    collector.Tornado,
 			wantErr: nil,
 		},
 		{
 			name:    "should return wind report type for wind header",
-			args:    args{hdrs: []ReaderHeader{{"reportType", []byte(collector.Wind.String())}}},
+			args:    args{hdrs: []consumer.ReaderHeader{{Key: "reportType", Value: []byte(collector.Wind.String())}}},
 			want:    collector.Wind,
 			wantErr: nil,
 		},
 		{
 			name:    "should return error for no key found.",
-			args:    args{hdrs: []ReaderHeader{{"", []byte(collector.Wind.String())}}},
+			args:    args{hdrs: []consumer.ReaderHeader{{Key: "", Value: []byte(collector.Wind.String())}}},
 			want:    collector.Hail,
 			wantErr: errors.New("unable to find reportType key, cannot determine report type"),
 		},
@@ -402,3 +661,109 @@ func Test_getReportTypeFromHeader(t *testing.T) {
 		})
 	}
 }
+
+func TestParseReport_ReportDate(t *testing.T) {
+	line := []byte("0130,UNK,2 N Holt,Irwin,GA,31.63,-83.15,Trees down on McLeod Road. (TAE)")
+	headers := []consumer.ReaderHeader{
+		{Key: "reportType", Value: []byte("Wind")},
+		{Key: consumer.ReportDateHeader, Value: []byte("2024-05-17")},
+	}
+	msg, err := ParseReport(headers, line)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-05-18T01:30:00Z", time.Unix(msg.GetTime(), 0).UTC().Format(time.RFC3339), "times before 12Z are on the next date")
+
+	headers[1].Value = []byte("05/17/2024")
+	_, err = ParseReport(headers, line)
+	assert.ErrorContains(t, err, "invalid report date")
+}
+
+func TestTransformer_GetMessageHistorical(t *testing.T) {
+	producer := &mockProducer{}
+	tr := NewTransformer(&mockConsumer{
+		expectedData: consumer.ReaderResponse{
+			Value:   []byte("1,1950,1,3,1950-01-03,11:00:00,3,MO,29,1,3,3,0,6,0,38.77,-90.22,38.83,-90.03,9.5,150,2,0,1,0,0,0,0,0"),
+			Headers: []consumer.ReaderHeader{{Key: "reportType", Value: []byte(report2.HistoricalTornado)}},
+		},
+	}, producer, nil, slog.New(slogenv.NewHandler(slog.NewTextHandler(os.Stderr, nil))))
+
+	require.NoError(t, tr.GetMessage(context.Background()))
+	require.Len(t, producer.written, 1)
+	assert.Equal(t, "Tornado", producer.written[0].Type, "written as the message it was parsed into")
+
+	var msg report.TornadoMsg
+	require.NoError(t, proto.Unmarshal(producer.written[0].Body, &msg))
+	assert.Equal(t, "38.83", msg.GetTrack().GetEndLat())
+	assert.Equal(t, int32(3), msg.GetImpact().GetInjuries())
+}
+
+func TestTransformer_GetMessageUnsupportedEvent(t *testing.T) {
+	producer := &mockProducer{}
+	line := "202305,17,1735,202305,17,1750,180001,1100002,TEXAS,48,2023,May,Flash Flood,C,221,HOOD,FWD,17-MAY-23 17:35:00,CST-6,17-MAY-23 17:50:00,0,0,0,0,0.00K,0.00K,Public,,,Heavy Rain,,,,,,,,,2,SW,GRANBURY,3,NE,TOLAR,32.36,-97.66,32.4,-97.6,,Roads flooded.,CSV"
+	tr := NewTransformer(&mockConsumer{
+		expectedData: consumer.ReaderResponse{
+			Value:   []byte(line),
+			Headers: []consumer.ReaderHeader{{Key: "reportType", Value: []byte(report2.StormEvents)}},
+		},
+	}, producer, nil, slog.New(slogenv.NewHandler(slog.NewTextHandler(os.Stderr, nil))))
+
+	require.NoError(t, tr.GetMessage(context.Background()), "events that are not reports are not errors")
+	assert.Empty(t, producer.written)
+	assert.Zero(t, tr.SkippedCount())
+}
+
+func TestTransformer_GetMessageLSR(t *testing.T) {
+	producer := &mockProducer{}
+	tr := NewTransformer(&mockConsumer{
+		expectedData: consumer.ReaderResponse{
+			Value: []byte("0820 PM     TSTM WND GST     NORMAN                  35.22N 97.44W\n" +
+				"05/19/2024  M70 MPH          CLEVELAND          OK   ASOS\n" +
+				"            ASOS STATION KOUN."),
+			Headers: []consumer.ReaderHeader{
+				{Key: "reportType", Value: []byte(report2.LSR)},
+				{Key: consumer.LSROfficeHeader, Value: []byte("OUN")},
+				{Key: consumer.LSRIssuedHeader, Value: []byte("2024-05-19T20:30:00-05:00")},
+			},
+		},
+	}, producer, nil, slog.New(slogenv.NewHandler(slog.NewTextHandler(os.Stderr, nil))))
+
+	require.NoError(t, tr.GetMessage(context.Background()))
+	require.Len(t, producer.written, 1)
+	assert.Equal(t, "Wind", producer.written[0].Type)
+
+	var msg report.WindMsg
+	require.NoError(t, proto.Unmarshal(producer.written[0].Body, &msg))
+	assert.Equal(t, int32(61), msg.GetSpeed())
+	assert.Equal(t, "2024-05-20T01:20:00Z", time.Unix(msg.GetTime(), 0).UTC().Format(time.RFC3339))
+	assert.Equal(t, "OUN", msg.GetLsr().GetOffice())
+
+	_, err := ParseReport([]consumer.ReaderHeader{{Key: "reportType", Value: []byte(report2.LSR)}}, []byte("0820 PM"))
+	assert.ErrorContains(t, err, "invalid LSR product issuance time")
+}
+
+func TestTransformer_GetMessageLSRFlood(t *testing.T) {
+	producer := &mockProducer{}
+	tr := NewTransformer(&mockConsumer{
+		expectedData: consumer.ReaderResponse{
+			Value: []byte("0825 PM     FLOOD            1 NNE MOORE             35.35N 97.48W\n" +
+				"05/19/2024                   CLEVELAND          OK   BROADCAST MEDIA\n" +
+				"            WATER OVER ROADS."),
+			Headers: []consumer.ReaderHeader{
+				{Key: "reportType", Value: []byte(report2.LSR)},
+				{Key: consumer.LSROfficeHeader, Value: []byte("OUN")},
+				{Key: consumer.LSRIssuedHeader, Value: []byte("2024-05-19T20:30:00-05:00")},
+			},
+		},
+	}, producer, nil, slog.New(slogenv.NewHandler(slog.NewTextHandler(os.Stderr, nil))), WithClassification(report2.DefaultThresholds()))
+
+	require.NoError(t, tr.GetMessage(context.Background()))
+	require.Len(t, producer.written, 1, "events other than hail, wind and tornadoes are published too")
+	assert.Equal(t, report2.LSR, producer.written[0].Type)
+
+	msg, err := provider.Decode(producer.written[0])
+	require.NoError(t, err)
+	lsr := msg.(*report.LSRMsg)
+	assert.Equal(t, "Flood", lsr.GetEventType())
+	assert.Equal(t, "FLOOD", lsr.GetLsr().GetEvent())
+	assert.Equal(t, "MOORE", lsr.GetLocation())
+	assert.Contains(t, producer.written[0].Headers, provider.WriterHeader{Key: SeverityHeader, Value: []byte("unknown")})
+}